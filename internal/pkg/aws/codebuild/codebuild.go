@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package codebuild provides a client to make API requests to AWS CodeBuild.
+package codebuild
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+)
+
+// Statuses of a build.
+const (
+	StatusInProgress = codebuild.StatusTypeInProgress
+	StatusSucceeded  = codebuild.StatusTypeSucceeded
+)
+
+type api interface {
+	StartBuildWithContext(aws.Context, *codebuild.StartBuildInput, ...request.Option) (*codebuild.StartBuildOutput, error)
+	BatchGetBuildsWithContext(aws.Context, *codebuild.BatchGetBuildsInput, ...request.Option) (*codebuild.BatchGetBuildsOutput, error)
+}
+
+// CodeBuild wraps an AWS CodeBuild client.
+type CodeBuild struct {
+	client api
+}
+
+// New returns a CodeBuild client configured against the input session.
+func New(s *session.Session) *CodeBuild {
+	return &CodeBuild{
+		client: codebuild.New(s),
+	}
+}
+
+// StartBuildInput holds the overrides of a build of an existing project.
+type StartBuildInput struct {
+	Project   string // Required. Name of the CodeBuild project.
+	Source    string // Required. Location of the zip or tarball of the source in S3, as "<bucket>/<key>".
+	Buildspec string // Required. Content of the buildspec to run.
+}
+
+// Build is the status of a build.
+type Build struct {
+	ID                string
+	Status            string            // Status of the build, for example "IN_PROGRESS" or "SUCCEEDED".
+	Phase             string            // Current phase of the build, for example "BUILD".
+	ExportedVariables map[string]string // Variables listed under "env.exported-variables" in the buildspec, set once the build completes.
+	LogsURL           string            // Link to the logs of the build in the console.
+}
+
+// StartBuild starts a build of the project with the source and buildspec of in, and returns the ID of the build.
+func (c *CodeBuild) StartBuild(ctx context.Context, in *StartBuildInput) (string, error) {
+	out, err := c.client.StartBuildWithContext(ctx, &codebuild.StartBuildInput{
+		ProjectName:            aws.String(in.Project),
+		SourceTypeOverride:     aws.String(codebuild.SourceTypeS3),
+		SourceLocationOverride: aws.String(in.Source),
+		BuildspecOverride:      aws.String(in.Buildspec),
+	})
+	if err != nil {
+		return "", fmt.Errorf("start build of project %s: %w", in.Project, err)
+	}
+	return aws.StringValue(out.Build.Id), nil
+}
+
+// Build returns the status of the build with the id.
+func (c *CodeBuild) Build(ctx context.Context, id string) (*Build, error) {
+	out, err := c.client.BatchGetBuildsWithContext(ctx, &codebuild.BatchGetBuildsInput{
+		Ids: aws.StringSlice([]string{id}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get build %s: %w", id, err)
+	}
+	if len(out.Builds) == 0 {
+		return nil, fmt.Errorf("build %s not found", id)
+	}
+	b := out.Builds[0]
+	build := &Build{
+		ID:                aws.StringValue(b.Id),
+		Status:            aws.StringValue(b.BuildStatus),
+		Phase:             aws.StringValue(b.CurrentPhase),
+		ExportedVariables: make(map[string]string, len(b.ExportedEnvironmentVariables)),
+	}
+	for _, v := range b.ExportedEnvironmentVariables {
+		build.ExportedVariables[aws.StringValue(v.Name)] = aws.StringValue(v.Value)
+	}
+	if b.Logs != nil {
+		build.LogsURL = aws.StringValue(b.Logs.DeepLink)
+	}
+	return build, nil
+}
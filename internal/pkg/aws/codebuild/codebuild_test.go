@@ -0,0 +1,121 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package codebuild
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/copilot-cli/internal/pkg/aws/codebuild/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeBuild_StartBuild(t *testing.T) {
+	ctx := context.Background()
+	in := &StartBuildInput{
+		Project:   "copilot-remote-build",
+		Source:    "bucket/context.tar.gz",
+		Buildspec: "version: 0.2",
+	}
+	wantedInput := &codebuild.StartBuildInput{
+		ProjectName:            aws.String("copilot-remote-build"),
+		SourceTypeOverride:     aws.String("S3"),
+		SourceLocationOverride: aws.String("bucket/context.tar.gz"),
+		BuildspecOverride:      aws.String("version: 0.2"),
+	}
+
+	t.Run("returns the id of the build", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := mocks.NewMockapi(ctrl)
+		m.EXPECT().StartBuildWithContext(ctx, wantedInput).Return(&codebuild.StartBuildOutput{
+			Build: &codebuild.Build{Id: aws.String("copilot-remote-build:1234")},
+		}, nil)
+		cb := &CodeBuild{client: m}
+
+		id, err := cb.StartBuild(ctx, in)
+
+		require.NoError(t, err)
+		require.Equal(t, "copilot-remote-build:1234", id)
+	})
+	t.Run("returns a wrapped error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := mocks.NewMockapi(ctrl)
+		m.EXPECT().StartBuildWithContext(ctx, wantedInput).Return(nil, errors.New("some error"))
+		cb := &CodeBuild{client: m}
+
+		_, err := cb.StartBuild(ctx, in)
+
+		require.EqualError(t, err, "start build of project copilot-remote-build: some error")
+	})
+}
+
+func TestCodeBuild_Build(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		setupMocks func(m *mocks.Mockapi)
+
+		wanted    *Build
+		wantedErr string
+	}{
+		"returns the status and exported variables of the build": {
+			setupMocks: func(m *mocks.Mockapi) {
+				m.EXPECT().BatchGetBuildsWithContext(ctx, &codebuild.BatchGetBuildsInput{
+					Ids: aws.StringSlice([]string{"copilot-remote-build:1234"}),
+				}).Return(&codebuild.BatchGetBuildsOutput{
+					Builds: []*codebuild.Build{
+						{
+							Id:           aws.String("copilot-remote-build:1234"),
+							BuildStatus:  aws.String("SUCCEEDED"),
+							CurrentPhase: aws.String("COMPLETED"),
+							ExportedEnvironmentVariables: []*codebuild.ExportedEnvironmentVariable{
+								{Name: aws.String("IMAGE_DIGEST"), Value: aws.String("sha256:abc")},
+							},
+							Logs: &codebuild.LogsLocation{DeepLink: aws.String("https://console.aws.amazon.com/logs")},
+						},
+					},
+				}, nil)
+			},
+			wanted: &Build{
+				ID:                "copilot-remote-build:1234",
+				Status:            StatusSucceeded,
+				Phase:             "COMPLETED",
+				ExportedVariables: map[string]string{"IMAGE_DIGEST": "sha256:abc"},
+				LogsURL:           "https://console.aws.amazon.com/logs",
+			},
+		},
+		"errors if the build doesn't exist": {
+			setupMocks: func(m *mocks.Mockapi) {
+				m.EXPECT().BatchGetBuildsWithContext(ctx, gomock.Any()).Return(&codebuild.BatchGetBuildsOutput{}, nil)
+			},
+			wantedErr: "build copilot-remote-build:1234 not found",
+		},
+		"returns a wrapped error": {
+			setupMocks: func(m *mocks.Mockapi) {
+				m.EXPECT().BatchGetBuildsWithContext(ctx, gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			wantedErr: "get build copilot-remote-build:1234: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks.NewMockapi(ctrl)
+			tc.setupMocks(m)
+			cb := &CodeBuild{client: m}
+
+			got, err := cb.Build(ctx, "copilot-remote-build:1234")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
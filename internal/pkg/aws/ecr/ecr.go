@@ -5,6 +5,7 @@
 package ecr
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -14,8 +15,10 @@ import (
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/copilot-cli/internal/pkg/deploy"
 	"github.com/aws/copilot-cli/internal/pkg/term/log"
 )
 
@@ -28,9 +31,14 @@ const (
 
 type api interface {
 	DescribeImages(*ecr.DescribeImagesInput) (*ecr.DescribeImagesOutput, error)
+	DescribeImagesWithContext(aws.Context, *ecr.DescribeImagesInput, ...request.Option) (*ecr.DescribeImagesOutput, error)
 	GetAuthorizationToken(*ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error)
 	DescribeRepositories(*ecr.DescribeRepositoriesInput) (*ecr.DescribeRepositoriesOutput, error)
+	DescribeRepositoriesWithContext(aws.Context, *ecr.DescribeRepositoriesInput, ...request.Option) (*ecr.DescribeRepositoriesOutput, error)
+	ListTagsForResourceWithContext(aws.Context, *ecr.ListTagsForResourceInput, ...request.Option) (*ecr.ListTagsForResourceOutput, error)
 	BatchDeleteImage(*ecr.BatchDeleteImageInput) (*ecr.BatchDeleteImageOutput, error)
+	BatchDeleteImageWithContext(aws.Context, *ecr.BatchDeleteImageInput, ...request.Option) (*ecr.BatchDeleteImageOutput, error)
+	DescribePullThroughCacheRules(*ecr.DescribePullThroughCacheRulesInput) (*ecr.DescribePullThroughCacheRulesOutput, error)
 }
 
 // ECR wraps an AWS ECR client.
@@ -127,6 +135,27 @@ func (c ECR) ListImages(repoName string) ([]Image, error) {
 	return images, nil
 }
 
+// PullThroughCacheRules calls the ECR DescribePullThroughCacheRules API and returns
+// the repository prefix of each pull through cache rule of the registry keyed by upstream registry URL,
+// for example "registry-1.docker.io": "docker-hub".
+func (c ECR) PullThroughCacheRules() (map[string]string, error) {
+	rules := make(map[string]string)
+	in := &ecr.DescribePullThroughCacheRulesInput{}
+	for {
+		resp, err := c.client.DescribePullThroughCacheRules(in)
+		if err != nil {
+			return nil, fmt.Errorf("ecr describe pull through cache rules: %w", err)
+		}
+		for _, rule := range resp.PullThroughCacheRules {
+			rules[aws.StringValue(rule.UpstreamRegistryUrl)] = aws.StringValue(rule.EcrRepositoryPrefix)
+		}
+		if resp.NextToken == nil {
+			return rules, nil
+		}
+		in.NextToken = resp.NextToken
+	}
+}
+
 // DeleteImages calls the ECR BatchDeleteImage API with the input image list and repository name.
 func (c ECR) DeleteImages(images []Image, repoName string) error {
 	if len(images) == 0 {
@@ -160,6 +189,133 @@ func (c ECR) DeleteImages(images []Image, repoName string) error {
 	return nil
 }
 
+// ImageDeletion describes the outcome of deleting a tag or an image from a repository.
+type ImageDeletion struct {
+	Digest       string   // Digest of the image that the deletion applies to.
+	Tags         []string // Tags that are removed from the repository.
+	ImageDeleted bool     // True if the image itself is removed, either directly or because its last tag is removed.
+	DryRun       bool     // True if nothing was deleted and the deletion only describes what would happen.
+}
+
+// DeleteRemoteTag removes the tag from the image it references in the repository.
+// ECR deletes the image as well if the tag is the last one referencing it, which is reported in the returned ImageDeletion.
+// The repository must have been created by copilot. If dryRun is true, the tag is only looked up and nothing is deleted.
+func (c ECR) DeleteRemoteTag(ctx context.Context, repoName, tag string, dryRun bool) (*ImageDeletion, error) {
+	id := &ecr.ImageIdentifier{
+		ImageTag: aws.String(tag),
+	}
+	if err := c.checkCopilotRepository(ctx, repoName); err != nil {
+		return nil, err
+	}
+	detail, err := c.describeImage(ctx, repoName, id)
+	if err != nil {
+		return nil, err
+	}
+	deletion := &ImageDeletion{
+		Digest:       aws.StringValue(detail.ImageDigest),
+		Tags:         []string{tag},
+		ImageDeleted: len(detail.ImageTags) <= 1,
+		DryRun:       dryRun,
+	}
+	if dryRun {
+		return deletion, nil
+	}
+	if err := c.batchDeleteImage(ctx, repoName, id); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// DeleteRemoteImage removes the image with the digest, along with all of its tags, from the repository.
+// The repository must have been created by copilot. If dryRun is true, the image is only looked up and nothing is deleted.
+func (c ECR) DeleteRemoteImage(ctx context.Context, repoName, digest string, dryRun bool) (*ImageDeletion, error) {
+	id := &ecr.ImageIdentifier{
+		ImageDigest: aws.String(digest),
+	}
+	if err := c.checkCopilotRepository(ctx, repoName); err != nil {
+		return nil, err
+	}
+	detail, err := c.describeImage(ctx, repoName, id)
+	if err != nil {
+		return nil, err
+	}
+	deletion := &ImageDeletion{
+		Digest:       aws.StringValue(detail.ImageDigest),
+		Tags:         aws.StringValueSlice(detail.ImageTags),
+		ImageDeleted: true,
+		DryRun:       dryRun,
+	}
+	if dryRun {
+		return deletion, nil
+	}
+	if err := c.batchDeleteImage(ctx, repoName, id); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// checkCopilotRepository returns an error if the repository doesn't have the tags that copilot adds to its repositories,
+// so that images pushed by other tools are never deleted.
+func (c ECR) checkCopilotRepository(ctx context.Context, repoName string) error {
+	repos, err := c.client.DescribeRepositoriesWithContext(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{repoName}),
+	})
+	if err != nil {
+		return fmt.Errorf("ecr describe repository %s: %w", repoName, err)
+	}
+	if len(repos.Repositories) == 0 {
+		return fmt.Errorf("ecr repo %s not found", repoName)
+	}
+	tags, err := c.client.ListTagsForResourceWithContext(ctx, &ecr.ListTagsForResourceInput{
+		ResourceArn: repos.Repositories[0].RepositoryArn,
+	})
+	if err != nil {
+		return fmt.Errorf("ecr repo %s list tags: %w", repoName, err)
+	}
+	for _, tag := range tags.Tags {
+		if aws.StringValue(tag.Key) == deploy.AppTagKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("ecr repo %s is not managed by copilot: it has no %s tag", repoName, deploy.AppTagKey)
+}
+
+func (c ECR) describeImage(ctx context.Context, repoName string, id *ecr.ImageIdentifier) (*ecr.ImageDetail, error) {
+	resp, err := c.client.DescribeImagesWithContext(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repoName),
+		ImageIds:       []*ecr.ImageIdentifier{id},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ecr repo %s describe image %s: %w", repoName, imageIDString(id), err)
+	}
+	if len(resp.ImageDetails) == 0 {
+		return nil, fmt.Errorf("ecr repo %s: image %s not found", repoName, imageIDString(id))
+	}
+	return resp.ImageDetails[0], nil
+}
+
+func (c ECR) batchDeleteImage(ctx context.Context, repoName string, id *ecr.ImageIdentifier) error {
+	resp, err := c.client.BatchDeleteImageWithContext(ctx, &ecr.BatchDeleteImageInput{
+		RepositoryName: aws.String(repoName),
+		ImageIds:       []*ecr.ImageIdentifier{id},
+	})
+	if err != nil {
+		return fmt.Errorf("ecr repo %s batch delete image %s: %w", repoName, imageIDString(id), err)
+	}
+	if len(resp.Failures) != 0 {
+		failure := resp.Failures[0]
+		return fmt.Errorf("ecr repo %s delete image %s: %s %s", repoName, imageIDString(id), aws.StringValue(failure.FailureCode), aws.StringValue(failure.FailureReason))
+	}
+	return nil
+}
+
+func imageIDString(id *ecr.ImageIdentifier) string {
+	if id.ImageTag != nil {
+		return aws.StringValue(id.ImageTag)
+	}
+	return aws.StringValue(id.ImageDigest)
+}
+
 // ClearRepository orchestrates a ListImages call followed by a DeleteImages
 // call to delete all images from the input ECR repository name.
 func (c ECR) ClearRepository(repoName string) error {
@@ -4,6 +4,7 @@
 package ecr
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -264,6 +265,74 @@ func TestListImages(t *testing.T) {
 	}
 }
 
+func TestPullThroughCacheRules(t *testing.T) {
+	mockNextToken := "next"
+	tests := map[string]struct {
+		mockECRClient func(m *mocks.Mockapi)
+
+		wantRules map[string]string
+		wantError error
+	}{
+		"should wrap error returned by ECR DescribePullThroughCacheRules": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				m.EXPECT().DescribePullThroughCacheRules(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			wantError: errors.New("ecr describe pull through cache rules: some error"),
+		},
+		"should return the rules of all the pages": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				m.EXPECT().DescribePullThroughCacheRules(&ecr.DescribePullThroughCacheRulesInput{}).Return(&ecr.DescribePullThroughCacheRulesOutput{
+					PullThroughCacheRules: []*ecr.PullThroughCacheRule{
+						{
+							UpstreamRegistryUrl: aws.String("registry-1.docker.io"),
+							EcrRepositoryPrefix: aws.String("docker-hub"),
+						},
+					},
+					NextToken: &mockNextToken,
+				}, nil)
+				m.EXPECT().DescribePullThroughCacheRules(&ecr.DescribePullThroughCacheRulesInput{
+					NextToken: &mockNextToken,
+				}).Return(&ecr.DescribePullThroughCacheRulesOutput{
+					PullThroughCacheRules: []*ecr.PullThroughCacheRule{
+						{
+							UpstreamRegistryUrl: aws.String("ghcr.io"),
+							EcrRepositoryPrefix: aws.String("github"),
+						},
+					},
+				}, nil)
+			},
+			wantRules: map[string]string{
+				"registry-1.docker.io": "docker-hub",
+				"ghcr.io":              "github",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockECRAPI := mocks.NewMockapi(ctrl)
+			tc.mockECRClient(mockECRAPI)
+
+			client := ECR{
+				mockECRAPI,
+			}
+
+			gotRules, gotError := client.PullThroughCacheRules()
+
+			if tc.wantError != nil {
+				require.EqualError(t, gotError, tc.wantError.Error())
+				return
+			}
+			require.NoError(t, gotError)
+			require.Equal(t, tc.wantRules, gotRules)
+		})
+	}
+}
+
 func TestDeleteImages(t *testing.T) {
 	mockRepoName := "mockRepoName"
 	mockError := errors.New("mockError")
@@ -450,3 +519,140 @@ func TestClearRepository(t *testing.T) {
 		})
 	}
 }
+
+// mockCopilotRepository expects the lookup of the tags of the repository, which is tagged by copilot if managed is true.
+func mockCopilotRepository(ctx context.Context, m *mocks.Mockapi, repoName string, managed bool) {
+	m.EXPECT().DescribeRepositoriesWithContext(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{repoName}),
+	}).Return(&ecr.DescribeRepositoriesOutput{
+		Repositories: []*ecr.Repository{{RepositoryArn: aws.String("mockRepoARN")}},
+	}, nil)
+	var tags []*ecr.Tag
+	if managed {
+		tags = append(tags, &ecr.Tag{Key: aws.String("copilot-application"), Value: aws.String("phonetool")})
+	}
+	m.EXPECT().ListTagsForResourceWithContext(ctx, &ecr.ListTagsForResourceInput{
+		ResourceArn: aws.String("mockRepoARN"),
+	}).Return(&ecr.ListTagsForResourceOutput{Tags: tags}, nil)
+}
+
+func TestDeleteRemoteTag(t *testing.T) {
+	ctx := context.Background()
+	mockRepoName := "mockRepoName"
+	tagID := &ecr.ImageIdentifier{ImageTag: aws.String("v1")}
+
+	tests := map[string]struct {
+		dryRun        bool
+		mockECRClient func(m *mocks.Mockapi)
+
+		wanted    *ImageDeletion
+		wantedErr string
+	}{
+		"returns the image that would be deleted in dry-run mode without deleting it": {
+			dryRun: true,
+			mockECRClient: func(m *mocks.Mockapi) {
+				mockCopilotRepository(ctx, m, mockRepoName, true)
+				m.EXPECT().DescribeImagesWithContext(ctx, &ecr.DescribeImagesInput{
+					RepositoryName: aws.String(mockRepoName),
+					ImageIds:       []*ecr.ImageIdentifier{tagID},
+				}).Return(&ecr.DescribeImagesOutput{
+					ImageDetails: []*ecr.ImageDetail{{ImageDigest: aws.String("sha256:abc"), ImageTags: aws.StringSlice([]string{"v1"})}},
+				}, nil)
+			},
+			wanted: &ImageDeletion{Digest: "sha256:abc", Tags: []string{"v1"}, ImageDeleted: true, DryRun: true},
+		},
+		"untags an image that has other tags": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				m.EXPECT().DescribeImagesWithContext(ctx, gomock.Any()).Return(&ecr.DescribeImagesOutput{
+					ImageDetails: []*ecr.ImageDetail{{ImageDigest: aws.String("sha256:abc"), ImageTags: aws.StringSlice([]string{"v1", "latest"})}},
+				}, nil)
+				mockCopilotRepository(ctx, m, mockRepoName, true)
+				m.EXPECT().BatchDeleteImageWithContext(ctx, &ecr.BatchDeleteImageInput{
+					RepositoryName: aws.String(mockRepoName),
+					ImageIds:       []*ecr.ImageIdentifier{tagID},
+				}).Return(&ecr.BatchDeleteImageOutput{}, nil)
+			},
+			wanted: &ImageDeletion{Digest: "sha256:abc", Tags: []string{"v1"}},
+		},
+		"returns an error if the tag does not exist": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				mockCopilotRepository(ctx, m, mockRepoName, true)
+				m.EXPECT().DescribeImagesWithContext(ctx, gomock.Any()).Return(&ecr.DescribeImagesOutput{}, nil)
+			},
+			wantedErr: "ecr repo mockRepoName: image v1 not found",
+		},
+		"refuses to delete from a repository that copilot doesn't manage": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				mockCopilotRepository(ctx, m, mockRepoName, false)
+			},
+			wantedErr: "ecr repo mockRepoName is not managed by copilot: it has no copilot-application tag",
+		},
+		"refuses a dry run in a repository that copilot doesn't manage": {
+			dryRun: true,
+			mockECRClient: func(m *mocks.Mockapi) {
+				mockCopilotRepository(ctx, m, mockRepoName, false)
+			},
+			wantedErr: "ecr repo mockRepoName is not managed by copilot: it has no copilot-application tag",
+		},
+		"returns an error if the deletion fails": {
+			mockECRClient: func(m *mocks.Mockapi) {
+				m.EXPECT().DescribeImagesWithContext(ctx, gomock.Any()).Return(&ecr.DescribeImagesOutput{
+					ImageDetails: []*ecr.ImageDetail{{ImageDigest: aws.String("sha256:abc")}},
+				}, nil)
+				mockCopilotRepository(ctx, m, mockRepoName, true)
+				m.EXPECT().BatchDeleteImageWithContext(ctx, gomock.Any()).Return(&ecr.BatchDeleteImageOutput{
+					Failures: []*ecr.ImageFailure{{FailureCode: aws.String("ImageNotFound"), FailureReason: aws.String("gone")}},
+				}, nil)
+			},
+			wantedErr: "ecr repo mockRepoName delete image v1: ImageNotFound gone",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockECRAPI := mocks.NewMockapi(ctrl)
+			tc.mockECRClient(mockECRAPI)
+			client := ECR{
+				mockECRAPI,
+			}
+
+			got, err := client.DeleteRemoteTag(ctx, mockRepoName, "v1", tc.dryRun)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDeleteRemoteImage(t *testing.T) {
+	ctx := context.Background()
+	digestID := &ecr.ImageIdentifier{ImageDigest: aws.String("sha256:abc")}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockECRAPI := mocks.NewMockapi(ctrl)
+	mockECRAPI.EXPECT().DescribeImagesWithContext(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String("mockRepoName"),
+		ImageIds:       []*ecr.ImageIdentifier{digestID},
+	}).Return(&ecr.DescribeImagesOutput{
+		ImageDetails: []*ecr.ImageDetail{{ImageDigest: aws.String("sha256:abc"), ImageTags: aws.StringSlice([]string{"v1", "latest"})}},
+	}, nil)
+	mockCopilotRepository(ctx, mockECRAPI, "mockRepoName", true)
+	mockECRAPI.EXPECT().BatchDeleteImageWithContext(ctx, &ecr.BatchDeleteImageInput{
+		RepositoryName: aws.String("mockRepoName"),
+		ImageIds:       []*ecr.ImageIdentifier{digestID},
+	}).Return(&ecr.BatchDeleteImageOutput{}, nil)
+	client := ECR{
+		mockECRAPI,
+	}
+
+	got, err := client.DeleteRemoteImage(ctx, "mockRepoName", "sha256:abc", false)
+
+	require.NoError(t, err)
+	require.Equal(t, &ImageDeletion{Digest: "sha256:abc", Tags: []string{"v1", "latest"}, ImageDeleted: true}, got)
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ecr
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecrpublic"
+)
+
+// publicRegion is the only region that serves the ECR Public API.
+const publicRegion = "us-east-1"
+
+type publicAPI interface {
+	GetAuthorizationToken(*ecrpublic.GetAuthorizationTokenInput) (*ecrpublic.GetAuthorizationTokenOutput, error)
+	DescribeRepositories(*ecrpublic.DescribeRepositoriesInput) (*ecrpublic.DescribeRepositoriesOutput, error)
+}
+
+// Public wraps an AWS ECR Public client.
+type Public struct {
+	client publicAPI
+}
+
+// NewPublic returns a Public configured against the input session.
+// The ECR Public API is only available in us-east-1, regardless of the region of the session.
+func NewPublic(s *session.Session) Public {
+	return Public{
+		client: ecrpublic.New(s, aws.NewConfig().WithRegion(publicRegion)),
+	}
+}
+
+// Auth returns the basic authentication credentials needed to push images to ECR Public,
+// or to pull images with the higher rate limits of authenticated users.
+func (c Public) Auth() (username string, password string, err error) {
+	response, err := c.client.GetAuthorizationToken(&ecrpublic.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", "", fmt.Errorf("get ECR Public auth: %w", err)
+	}
+	if response.AuthorizationData == nil || response.AuthorizationData.AuthorizationToken == nil {
+		return "", "", errors.New("no authorization data returned by ECR Public")
+	}
+	authToken, err := base64.StdEncoding.DecodeString(aws.StringValue(response.AuthorizationData.AuthorizationToken))
+	if err != nil {
+		return "", "", fmt.Errorf("decode auth token: %w", err)
+	}
+	username, password, ok := strings.Cut(string(authToken), ":")
+	if !ok {
+		return "", "", errors.New("parse auth token: missing separator between the username and the password")
+	}
+	return username, password, nil
+}
+
+// RepositoryURI returns the ECR Public repository URI, like "public.ecr.aws/<alias>/<name>".
+func (c Public) RepositoryURI(name string) (string, error) {
+	result, err := c.client.DescribeRepositories(&ecrpublic.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("ecr-public describe repository %s: %w", name, err)
+	}
+	if len(result.Repositories) == 0 {
+		return "", errors.New("no repositories found")
+	}
+	return aws.StringValue(result.Repositories[0].RepositoryUri), nil
+}
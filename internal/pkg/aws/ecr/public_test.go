@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ecr
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecrpublic"
+	"github.com/aws/copilot-cli/internal/pkg/aws/ecr/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublic_Auth(t *testing.T) {
+	testCases := map[string]struct {
+		mockClient func(m *mocks.MockpublicAPI)
+
+		wantedUsername string
+		wantedPassword string
+		wantedErr      string
+	}{
+		"should return wrapped error given error returned from GetAuthorizationToken": {
+			mockClient: func(m *mocks.MockpublicAPI) {
+				m.EXPECT().GetAuthorizationToken(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			wantedErr: "get ECR Public auth: some error",
+		},
+		"should return Auth data": {
+			mockClient: func(m *mocks.MockpublicAPI) {
+				m.EXPECT().GetAuthorizationToken(gomock.Any()).Return(&ecrpublic.GetAuthorizationTokenOutput{
+					AuthorizationData: &ecrpublic.AuthorizationData{
+						AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:token"))),
+					},
+				}, nil)
+			},
+			wantedUsername: "AWS",
+			wantedPassword: "token",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			ctrl := gomock.NewController(t)
+			m := mocks.NewMockpublicAPI(ctrl)
+			tc.mockClient(m)
+			client := Public{
+				client: m,
+			}
+
+			// WHEN
+			username, password, err := client.Auth()
+
+			// THEN
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedUsername, username)
+			require.Equal(t, tc.wantedPassword, password)
+		})
+	}
+}
+
+func TestPublic_RepositoryURI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mocks.NewMockpublicAPI(ctrl)
+	m.EXPECT().DescribeRepositories(&ecrpublic.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{"web"}),
+	}).Return(&ecrpublic.DescribeRepositoriesOutput{
+		Repositories: []*ecrpublic.Repository{
+			{RepositoryUri: aws.String("public.ecr.aws/alias/web")},
+		},
+	}, nil)
+	client := Public{
+		client: m,
+	}
+
+	uri, err := client.RepositoryURI("web")
+
+	require.NoError(t, err)
+	require.Equal(t, "public.ecr.aws/alias/web", uri)
+}
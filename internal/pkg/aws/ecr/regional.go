@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ecr
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+// RegionalRepository gives access to a repository with the same name in several regions.
+type RegionalRepository struct {
+	name      string
+	newClient func(region string) api
+
+	mu      sync.Mutex
+	clients map[string]ECR
+}
+
+// NewRegionalRepository returns a RegionalRepository for the repository name, with clients created from the session in each region.
+func NewRegionalRepository(s *session.Session, name string) *RegionalRepository {
+	return &RegionalRepository{
+		name: name,
+		newClient: func(region string) api {
+			return ecr.New(s, aws.NewConfig().WithRegion(region))
+		},
+		clients: make(map[string]ECR),
+	}
+}
+
+// RepositoryURI returns the URI of the repository in the region.
+func (r *RegionalRepository) RepositoryURI(region string) (string, error) {
+	return r.client(region).RepositoryURI(r.name)
+}
+
+// Auth returns the basic authentication credentials needed to push images to the registry of the region.
+func (r *RegionalRepository) Auth(region string) (username, password string, err error) {
+	return r.client(region).Auth()
+}
+
+func (r *RegionalRepository) client(region string) ECR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.clients[region]
+	if !ok {
+		c = ECR{
+			client: r.newClient(region),
+		}
+		r.clients[region] = c
+	}
+	return c
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ecr
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/copilot-cli/internal/pkg/aws/ecr/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionalRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clients := map[string]*mocks.Mockapi{
+		"us-west-2": mocks.NewMockapi(ctrl),
+		"eu-west-1": mocks.NewMockapi(ctrl),
+	}
+	var created []string
+	repo := &RegionalRepository{
+		name: "app/svc",
+		newClient: func(region string) api {
+			created = append(created, region)
+			return clients[region]
+		},
+		clients: make(map[string]ECR),
+	}
+	clients["us-west-2"].EXPECT().DescribeRepositories(&ecr.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{"app/svc"}),
+	}).Return(&ecr.DescribeRepositoriesOutput{
+		Repositories: []*ecr.Repository{
+			{RepositoryUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc")},
+		},
+	}, nil)
+	clients["us-west-2"].EXPECT().GetAuthorizationToken(gomock.Any()).Return(&ecr.GetAuthorizationTokenOutput{
+		AuthorizationData: []*ecr.AuthorizationData{
+			{AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:west")))},
+		},
+	}, nil)
+	clients["eu-west-1"].EXPECT().GetAuthorizationToken(gomock.Any()).Return(&ecr.GetAuthorizationTokenOutput{
+		AuthorizationData: []*ecr.AuthorizationData{
+			{AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:eu")))},
+		},
+	}, nil)
+
+	uri, err := repo.RepositoryURI("us-west-2")
+	require.NoError(t, err)
+	require.Equal(t, "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc", uri)
+	_, password, err := repo.Auth("us-west-2")
+	require.NoError(t, err)
+	require.Equal(t, "west", password)
+	_, password, err = repo.Auth("eu-west-1")
+	require.NoError(t, err)
+	require.Equal(t, "eu", password)
+	require.Equal(t, []string{"us-west-2", "eu-west-1"}, created, "clients are reused across calls")
+}
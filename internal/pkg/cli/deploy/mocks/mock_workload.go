@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./internal/pkg/cli/deploy/workload.go
+// Source: ./workload.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -9,6 +9,7 @@ import (
 	io "io"
 	reflect "reflect"
 
+	session "github.com/aws/aws-sdk-go/aws/session"
 	addon "github.com/aws/copilot-cli/internal/pkg/addon"
 	cloudformation "github.com/aws/copilot-cli/internal/pkg/aws/cloudformation"
 	cloudformation0 "github.com/aws/copilot-cli/internal/pkg/deploy/cloudformation"
@@ -107,12 +108,13 @@ func (mr *MockrepositoryServiceMockRecorder) BuildAndPush(ctx, args, w interface
 }
 
 // Login mocks base method.
-func (m *MockrepositoryService) Login() (string, error) {
+func (m *MockrepositoryService) Login() (string, dockerengine.CredentialSource, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login")
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dockerengine.CredentialSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Login indicates an expected call of Login.
@@ -121,6 +123,97 @@ func (mr *MockrepositoryServiceMockRecorder) Login() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockrepositoryService)(nil).Login))
 }
 
+// URI mocks base method.
+func (m *MockrepositoryService) URI() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URI")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// URI indicates an expected call of URI.
+func (mr *MockrepositoryServiceMockRecorder) URI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URI", reflect.TypeOf((*MockrepositoryService)(nil).URI))
+}
+
+// MockimageBuilder is a mock of imageBuilder interface.
+type MockimageBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockimageBuilderMockRecorder
+}
+
+// MockimageBuilderMockRecorder is the mock recorder for MockimageBuilder.
+type MockimageBuilderMockRecorder struct {
+	mock *MockimageBuilder
+}
+
+// NewMockimageBuilder creates a new mock instance.
+func NewMockimageBuilder(ctrl *gomock.Controller) *MockimageBuilder {
+	mock := &MockimageBuilder{ctrl: ctrl}
+	mock.recorder = &MockimageBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockimageBuilder) EXPECT() *MockimageBuilderMockRecorder {
+	return m.recorder
+}
+
+// Build mocks base method.
+func (m *MockimageBuilder) Build(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Build", ctx, args, w)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Build indicates an expected call of Build.
+func (mr *MockimageBuilderMockRecorder) Build(ctx, args, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockimageBuilder)(nil).Build), ctx, args, w)
+}
+
+// MockremoteImageBuilder is a mock of remoteImageBuilder interface.
+type MockremoteImageBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockremoteImageBuilderMockRecorder
+}
+
+// MockremoteImageBuilderMockRecorder is the mock recorder for MockremoteImageBuilder.
+type MockremoteImageBuilderMockRecorder struct {
+	mock *MockremoteImageBuilder
+}
+
+// NewMockremoteImageBuilder creates a new mock instance.
+func NewMockremoteImageBuilder(ctrl *gomock.Controller) *MockremoteImageBuilder {
+	mock := &MockremoteImageBuilder{ctrl: ctrl}
+	mock.recorder = &MockremoteImageBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockremoteImageBuilder) EXPECT() *MockremoteImageBuilderMockRecorder {
+	return m.recorder
+}
+
+// BuildAndPush mocks base method.
+func (m *MockremoteImageBuilder) BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildAndPush", ctx, args, w)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildAndPush indicates an expected call of BuildAndPush.
+func (mr *MockremoteImageBuilderMockRecorder) BuildAndPush(ctx, args, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildAndPush", reflect.TypeOf((*MockremoteImageBuilder)(nil).BuildAndPush), ctx, args, w)
+}
+
 // Mocktemplater is a mock of templater interface.
 type Mocktemplater struct {
 	ctrl     *gomock.Controller
@@ -530,6 +623,59 @@ func (mr *MockdockerEngineRunCheckerMockRecorder) CheckDockerEngineRunning() *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDockerEngineRunning", reflect.TypeOf((*MockdockerEngineRunChecker)(nil).CheckDockerEngineRunning))
 }
 
+// MockregistrySessionProvider is a mock of registrySessionProvider interface.
+type MockregistrySessionProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockregistrySessionProviderMockRecorder
+}
+
+// MockregistrySessionProviderMockRecorder is the mock recorder for MockregistrySessionProvider.
+type MockregistrySessionProviderMockRecorder struct {
+	mock *MockregistrySessionProvider
+}
+
+// NewMockregistrySessionProvider creates a new mock instance.
+func NewMockregistrySessionProvider(ctrl *gomock.Controller) *MockregistrySessionProvider {
+	mock := &MockregistrySessionProvider{ctrl: ctrl}
+	mock.recorder = &MockregistrySessionProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockregistrySessionProvider) EXPECT() *MockregistrySessionProviderMockRecorder {
+	return m.recorder
+}
+
+// DefaultWithRegion mocks base method.
+func (m *MockregistrySessionProvider) DefaultWithRegion(region string) (*session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultWithRegion", region)
+	ret0, _ := ret[0].(*session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DefaultWithRegion indicates an expected call of DefaultWithRegion.
+func (mr *MockregistrySessionProviderMockRecorder) DefaultWithRegion(region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultWithRegion", reflect.TypeOf((*MockregistrySessionProvider)(nil).DefaultWithRegion), region)
+}
+
+// FromRole mocks base method.
+func (m *MockregistrySessionProvider) FromRole(roleARN, region string) (*session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FromRole", roleARN, region)
+	ret0, _ := ret[0].(*session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FromRole indicates an expected call of FromRole.
+func (mr *MockregistrySessionProviderMockRecorder) FromRole(roleARN, region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromRole", reflect.TypeOf((*MockregistrySessionProvider)(nil).FromRole), roleARN, region)
+}
+
 // MocktimeoutError is a mock of timeoutError interface.
 type MocktimeoutError struct {
 	ctrl     *gomock.Controller
@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/copilot-cli/internal/pkg/addon"
 	awscloudformation "github.com/aws/copilot-cli/internal/pkg/aws/cloudformation"
+	"github.com/aws/copilot-cli/internal/pkg/aws/codebuild"
 	"github.com/aws/copilot-cli/internal/pkg/aws/ecr"
 	"github.com/aws/copilot-cli/internal/pkg/aws/identity"
 	"github.com/aws/copilot-cli/internal/pkg/aws/partitions"
@@ -58,7 +59,7 @@ const (
 )
 
 const (
-	labelForBuilder       = "com.aws.copilot.image.builder"
+	labelForBuilder       = dockerengine.CopilotBuilderLabel
 	labelForVersion       = "com.aws.copilot.image.version"
 	labelForContainerName = "com.aws.copilot.image.container.name"
 )
@@ -80,11 +81,20 @@ func (noopActionRecommender) RecommendedActions() []string {
 }
 
 type repositoryService interface {
-	Login() (string, error)
+	Login() (string, dockerengine.CredentialSource, error)
+	URI() (string, error)
 	BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error)
 	Build(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error)
 }
 
+type imageBuilder interface {
+	Build(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error)
+}
+
+type remoteImageBuilder interface {
+	BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error)
+}
+
 type templater interface {
 	Template() (string, error)
 }
@@ -168,6 +178,58 @@ func RepoName(app, workload string) string {
 	return fmt.Sprintf("%s/%s", app, workload)
 }
 
+type registrySessionProvider interface {
+	FromRole(roleARN string, region string) (*session.Session, error)
+	DefaultWithRegion(region string) (*session.Session, error)
+}
+
+// RegistrySession returns the session used to authenticate to the ECR repositories of the workloads deployed to env.
+// If the environment has a registry role, for example because the images are stored in a centralized account,
+// the role is assumed. Otherwise, the default credentials are used in the region of the environment.
+func RegistrySession(provider registrySessionProvider, env *config.Environment) (*session.Session, error) {
+	if env.RegistryRoleARN != "" {
+		sess, err := provider.FromRole(env.RegistryRoleARN, env.Region)
+		if err != nil {
+			return nil, fmt.Errorf("assume registry role %s in region %s: %w", env.RegistryRoleARN, env.Region, err)
+		}
+		return sess, nil
+	}
+	sess, err := provider.DefaultWithRegion(env.Region)
+	if err != nil {
+		return nil, fmt.Errorf("create default session with region %s: %w", env.Region, err)
+	}
+	return sess, nil
+}
+
+// RegistryRepository returns the repository that the images of the workload are pushed to when deploying to env.
+// The repository of the application stack, appRepoURI, is in the application account. If the environment has a registry role,
+// the images live in the account of the role instead, so the repository is looked up there by name.
+func RegistryRepository(provider registrySessionProvider, env *config.Environment, repoName, appRepoURI string) (*repository.Repository, error) {
+	sess, err := RegistrySession(provider, env)
+	if err != nil {
+		return nil, err
+	}
+	public := ecr.NewPublic(sess)
+	if env.RegistryRoleARN != "" {
+		return repository.New(ecr.New(sess), repoName).WithPublicRegistry(public), nil
+	}
+	return repository.NewWithURI(ecr.New(sess), repoName, appRepoURI).WithPublicRegistry(public), nil
+}
+
+// RemoteBuilder returns the builder that builds the images of the workloads deployed to env in CodeBuild
+// when the docker engine isn't running, or nil if the environment doesn't build images remotely.
+// The CodeBuild project and the bucket of its build contexts are in the account of the repositories.
+func RemoteBuilder(provider registrySessionProvider, env *config.Environment) (*repository.RemoteBuilder, error) {
+	if env.RemoteBuild == nil {
+		return nil, nil
+	}
+	sess, err := RegistrySession(provider, env)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewRemoteBuilder(s3.New(sess), codebuild.New(sess), env.RemoteBuild.Project, env.RemoteBuild.Bucket), nil
+}
+
 type workloadDeployer struct {
 	name          string
 	app           *config.Application
@@ -183,6 +245,7 @@ type workloadDeployer struct {
 	s3Client           uploader
 	addons             stackBuilder
 	repository         repositoryService
+	remoteBuilder      remoteImageBuilder // Nil if the environment doesn't build images remotely.
 	deployer           serviceDeployer
 	tmplGetter         deployedTemplateGetter
 	endpointGetter     endpointGetter
@@ -237,12 +300,12 @@ type ImageActionInput struct {
 	Name              string
 	WorkspacePath     string
 	Image             ContainerImageIdentifier
-	Builder           repositoryService
+	Builder           imageBuilder
 	CustomTag         string
 	GitShortCommitTag string
 	Mft               interface{}
 
-	Login              func() (string, error)
+	Login              func() (string, dockerengine.CredentialSource, error)
 	CheckDockerEngine  func() error
 	LabeledTermPrinter func(fw syncbuffer.FileWriter, bufs []*syncbuffer.LabeledSyncBuffer, opts ...syncbuffer.LabeledTermPrinterOption) LabeledTermPrinter
 }
@@ -280,9 +343,14 @@ func newWorkloadDeployer(in *WorkloadDeployerInput) (*workloadDeployer, error) {
 		addons = nil // so that we can check for no addons with nil comparison
 	}
 
-	repoName := RepoName(in.App.Name, in.Name)
-	repository := repository.NewWithURI(
-		ecr.New(defaultSessEnvRegion), repoName, resources.RepositoryURLs[in.Name])
+	repository, err := RegistryRepository(in.SessionProvider, in.Env, RepoName(in.App.Name, in.Name), resources.RepositoryURLs[in.Name])
+	if err != nil {
+		return nil, err
+	}
+	remoteBuilder, err := RemoteBuilder(in.SessionProvider, in.Env)
+	if err != nil {
+		return nil, err
+	}
 	store := config.NewSSMStore(identity.New(defaultSession), ssm.New(defaultSession), aws.StringValue(defaultSession.Config.Region))
 	envDescriber, err := describe.NewEnvDescriber(describe.NewEnvDescriberConfig{
 		App:         in.App.Name,
@@ -308,7 +376,7 @@ func newWorkloadDeployer(in *WorkloadDeployerInput) (*workloadDeployer, error) {
 		return syncbuffer.NewLabeledTermPrinter(fw, bufs, opts...)
 	}
 	docker := dockerengine.New(exec.NewCmd())
-	return &workloadDeployer{
+	wkld := &workloadDeployer{
 		name:                     in.Name,
 		app:                      in.App,
 		env:                      in.Env,
@@ -337,7 +405,11 @@ func newWorkloadDeployer(in *WorkloadDeployerInput) (*workloadDeployer, error) {
 
 		mft:    in.Mft,
 		rawMft: in.RawMft,
-	}, nil
+	}
+	if remoteBuilder != nil {
+		wkld.remoteBuilder = remoteBuilder
+	}
+	return wkld, nil
 }
 
 // DeployDiff returns the stringified diff of the template against the deployed template of the workload.
@@ -418,7 +490,7 @@ func (img ContainerImageIdentifier) Tag() string {
 }
 
 func (d *workloadDeployer) buildAndPushContainerImages(out *UploadArtifactsOutput) error {
-	return processContainerImages(&ImageActionInput{
+	in := &ImageActionInput{
 		Name:               d.name,
 		WorkspacePath:      d.workspacePath,
 		Image:              d.image,
@@ -428,8 +500,17 @@ func (d *workloadDeployer) buildAndPushContainerImages(out *UploadArtifactsOutpu
 		Login:              d.repository.Login,
 		CheckDockerEngine:  d.docker.CheckDockerEngineRunning,
 		LabeledTermPrinter: d.labeledTermPrinter,
-	}, out, d.repository.BuildAndPush)
-
+	}
+	buildAndPush := d.repository.BuildAndPush
+	if d.remoteBuilder != nil {
+		builder := &repository.FallbackBuilder{
+			Local:             d.repository,
+			Remote:            d.remoteBuilder,
+			CheckDockerEngine: d.docker.CheckDockerEngineRunning,
+		}
+		in.CheckDockerEngine, in.Login, buildAndPush = builder.CheckDockerEngineRunning, builder.Login, builder.BuildAndPush
+	}
+	return processContainerImages(in, out, buildAndPush)
 }
 
 // BuildContainerImages builds the all the images given the build arguments
@@ -449,45 +530,52 @@ func processContainerImages(in *ImageActionInput, out *UploadArtifactsOutput, bu
 	if err := in.CheckDockerEngine(); err != nil {
 		return fmt.Errorf("check if docker engine is running: %w", err)
 	}
-	uri, err := in.Login()
+	uri, _, err := in.Login()
 	if err != nil {
 		return fmt.Errorf("login to image repository: %w", err)
 	}
-	isMultipleContainerImages := len(buildArgsPerContainer) > 1
+	// Containers built from identical inputs, such as sidecars sharing a Dockerfile, produce identical images,
+	// so they are built and pushed once with the tags of all of them.
+	builds := dockerengine.CollapseIdenticalBuilds(buildArgsPerContainer, labelForContainerName)
+	isMultipleContainerImages := len(builds) > 1
 	if isMultipleContainerImages {
-		return buildContainerImagesInParallel(in, uri, buildArgsPerContainer, buildFunc, out)
+		return buildContainerImagesInParallel(in, uri, buildArgsPerContainer, builds, buildFunc, out)
 	}
-	return buildSingleContainerImage(in, uri, buildArgsPerContainer, buildFunc, out)
+	return buildSingleContainerImage(in, uri, builds, buildFunc, out)
 }
 
-func buildSingleContainerImage(in *ImageActionInput, uri string, buildArgsPerContainer map[string]*dockerengine.BuildArguments, buildFunc func(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error), out *UploadArtifactsOutput) error {
-	out.ImageDigests = make(map[string]ContainerImageIdentifier, len(buildArgsPerContainer))
-	for name, buildArgs := range buildArgsPerContainer {
+func buildSingleContainerImage(in *ImageActionInput, uri string, builds []dockerengine.CollapsedBuild, buildFunc func(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error), out *UploadArtifactsOutput) error {
+	out.ImageDigests = make(map[string]ContainerImageIdentifier)
+	for _, build := range builds {
+		name, buildArgs := strings.Join(build.Names, ", "), build.Args
 		buildArgs.URI = uri
 		digest, err := buildFunc(context.Background(), buildArgs, os.Stderr)
 		if err != nil {
 			return fmt.Errorf("build and push the image %q: %w", name, err)
 		}
-		out.ImageDigests[name] = ContainerImageIdentifier{
-			Digest:            digest,
-			CustomTag:         in.CustomTag,
-			GitShortCommitTag: in.GitShortCommitTag,
+		for _, container := range build.Names {
+			out.ImageDigests[container] = ContainerImageIdentifier{
+				Digest:            digest,
+				CustomTag:         in.CustomTag,
+				GitShortCommitTag: in.GitShortCommitTag,
+			}
 		}
 	}
 	return nil
 }
 
-func buildContainerImagesInParallel(in *ImageActionInput, uri string, buildArgsPerContainer map[string]*dockerengine.BuildArguments, buildFunc func(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error), out *UploadArtifactsOutput) error {
+func buildContainerImagesInParallel(in *ImageActionInput, uri string, buildArgsPerContainer map[string]*dockerengine.BuildArguments, builds []dockerengine.CollapsedBuild, buildFunc func(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error), out *UploadArtifactsOutput) error {
 	var digestsMu sync.Mutex
 	out.ImageDigests = make(map[string]ContainerImageIdentifier, len(buildArgsPerContainer))
 	var labeledBuffers []*syncbuffer.LabeledSyncBuffer
 	g, ctx := errgroup.WithContext(context.Background())
 	cursor := cursor.New()
 	cursor.Hide()
-	for name, buildArgs := range buildArgsPerContainer {
+	for _, build := range builds {
 		// create a copy of loop variables to avoid data race.
-		name := name
-		buildArgs := buildArgs
+		name := strings.Join(build.Names, ", ")
+		containers := build.Names
+		buildArgs := build.Args
 
 		buildArgs.URI = uri
 		buildArgsList, err := buildArgs.GenerateDockerBuildArgs(dockerengine.New(exec.NewCmd()))
@@ -505,12 +593,14 @@ func buildContainerImagesInParallel(in *ImageActionInput, uri string, buildArgsP
 			}
 			digestsMu.Lock()
 			defer digestsMu.Unlock()
-			imageName := fmt.Sprintf("%s:%s", uri, buildArgs.Tags[0])
-			out.ImageDigests[name] = ContainerImageIdentifier{
-				Digest:            digest,
-				CustomTag:         in.CustomTag,
-				GitShortCommitTag: in.GitShortCommitTag,
-				ImageName:         imageName,
+			for _, container := range containers {
+				imageName := fmt.Sprintf("%s:%s", uri, buildArgsPerContainer[container].Tags[0])
+				out.ImageDigests[container] = ContainerImageIdentifier{
+					Digest:            digest,
+					CustomTag:         in.CustomTag,
+					GitShortCommitTag: in.GitShortCommitTag,
+					ImageName:         imageName,
+				}
 			}
 			return nil
 		})
@@ -62,6 +62,7 @@ type deployMocks struct {
 	mockValidator              *mocks.MockaliasCertValidator
 	mockLabeledTermPrinter     *mocks.MockLabeledTermPrinter
 	mockdockerEngineRunChecker *mocks.MockdockerEngineRunChecker
+	mockRemoteImageBuilder     *mocks.MockremoteImageBuilder
 }
 
 type mockTemplateFS struct {
@@ -195,6 +196,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 		inMockUserTag     string
 		inMockGitTag      string
 		inDockerBuildArgs map[string]*manifest.DockerBuildArgs
+		inRemoteBuild     bool
 
 		mock                func(t *testing.T, m *deployMocks)
 		mockServiceDeployer func(deployer *workloadDeployer) artifactsUploader
@@ -219,6 +221,60 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			},
 			wantErr: fmt.Errorf("check if docker engine is running: some error"),
 		},
+		"build and push image remotely if docker engine is not running": {
+			inMockUserTag: "v1.0",
+			inRemoteBuild: true,
+			inDockerBuildArgs: map[string]*manifest.DockerBuildArgs{
+				"mockWkld": {
+					Dockerfile: aws.String("mockDockerfile"),
+					Context:    aws.String("mockContext"),
+				},
+			},
+			mock: func(t *testing.T, m *deployMocks) {
+				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(errors.New("some error"))
+				m.mockRepositoryService.EXPECT().URI().Return(mockURI, nil)
+				m.mockRemoteImageBuilder.EXPECT().BuildAndPush(gomock.Any(), &dockerengine.BuildArguments{
+					URI:        mockURI,
+					Dockerfile: "mockDockerfile",
+					Context:    "mockContext",
+					Platform:   "mockContainerPlatform",
+					Tags:       []string{"latest", "v1.0"},
+					Labels: map[string]string{
+						"com.aws.copilot.image.builder":        "copilot-cli",
+						"com.aws.copilot.image.container.name": "mockWkld",
+					},
+				}, gomock.Any()).Return("mockDigest", nil)
+				m.mockAddons = nil
+			},
+			wantImages: map[string]ContainerImageIdentifier{
+				mockName: {
+					Digest:    "mockDigest",
+					CustomTag: "v1.0",
+				},
+			},
+		},
+		"build and push image locally if docker engine is running with a remote builder": {
+			inMockUserTag: "v1.0",
+			inRemoteBuild: true,
+			inDockerBuildArgs: map[string]*manifest.DockerBuildArgs{
+				"mockWkld": {
+					Dockerfile: aws.String("mockDockerfile"),
+					Context:    aws.String("mockContext"),
+				},
+			},
+			mock: func(t *testing.T, m *deployMocks) {
+				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(nil)
+				m.mockRepositoryService.EXPECT().Login().Return(mockURI, dockerengine.CredentialSourceLogin, nil)
+				m.mockRepositoryService.EXPECT().BuildAndPush(gomock.Any(), gomock.Any(), gomock.Any()).Return("mockDigest", nil)
+				m.mockAddons = nil
+			},
+			wantImages: map[string]ContainerImageIdentifier{
+				mockName: {
+					Digest:    "mockDigest",
+					CustomTag: "v1.0",
+				},
+			},
+		},
 		"error if failed to build and push image": {
 			inMockUserTag: "v1.0",
 			inDockerBuildArgs: map[string]*manifest.DockerBuildArgs{
@@ -229,7 +285,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			},
 			mock: func(t *testing.T, m *deployMocks) {
 				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(nil)
-				m.mockRepositoryService.EXPECT().Login().Return(mockURI, nil)
+				m.mockRepositoryService.EXPECT().Login().Return(mockURI, dockerengine.CredentialSourceLogin, nil)
 				m.mockRepositoryService.EXPECT().BuildAndPush(gomock.Any(), &dockerengine.BuildArguments{
 					URI:        mockURI,
 					Dockerfile: "mockDockerfile",
@@ -255,7 +311,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			},
 			mock: func(t *testing.T, m *deployMocks) {
 				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(nil)
-				m.mockRepositoryService.EXPECT().Login().Return(mockURI, nil)
+				m.mockRepositoryService.EXPECT().Login().Return(mockURI, dockerengine.CredentialSourceLogin, nil)
 				m.mockRepositoryService.EXPECT().BuildAndPush(gomock.Any(), &dockerengine.BuildArguments{
 					URI:        mockURI,
 					Dockerfile: "mockDockerfile",
@@ -287,7 +343,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			},
 			mock: func(t *testing.T, m *deployMocks) {
 				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(nil)
-				m.mockRepositoryService.EXPECT().Login().Return(mockURI, nil)
+				m.mockRepositoryService.EXPECT().Login().Return(mockURI, dockerengine.CredentialSourceLogin, nil)
 				m.mockRepositoryService.EXPECT().BuildAndPush(gomock.Any(), &dockerengine.BuildArguments{
 					URI:        mockURI,
 					Dockerfile: "mockDockerfile",
@@ -322,7 +378,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			inMockGitTag: "gitTag",
 			mock: func(t *testing.T, m *deployMocks) {
 				m.mockdockerEngineRunChecker.EXPECT().CheckDockerEngineRunning().Return(nil)
-				m.mockRepositoryService.EXPECT().Login().Return(mockURI, nil)
+				m.mockRepositoryService.EXPECT().Login().Return(mockURI, dockerengine.CredentialSourceLogin, nil)
 				m.mockRepositoryService.EXPECT().BuildAndPush(gomock.Any(), &dockerengine.BuildArguments{
 					URI:        mockURI,
 					Dockerfile: "sidecarMockDockerfile",
@@ -632,6 +688,7 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 				mockFileSystem:             afero.NewMemMapFs(),
 				mockLabeledTermPrinter:     mocks.NewMockLabeledTermPrinter(ctrl),
 				mockdockerEngineRunChecker: mocks.NewMockdockerEngineRunChecker(ctrl),
+				mockRemoteImageBuilder:     mocks.NewMockremoteImageBuilder(ctrl),
 			}
 			tc.mock(t, m)
 
@@ -676,6 +733,9 @@ func TestWorkloadDeployer_UploadArtifacts(t *testing.T) {
 			if m.mockAddons != nil {
 				wkldDeployer.addons = m.mockAddons
 			}
+			if tc.inRemoteBuild {
+				wkldDeployer.remoteBuilder = m.mockRemoteImageBuilder
+			}
 			var deployer artifactsUploader
 			deployer = &lbWebSvcDeployer{
 				svcDeployer: &svcDeployer{
@@ -1550,3 +1610,61 @@ func TestWorkloadDeployer_DeployDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistrySession(t *testing.T) {
+	defaultSess := &session.Session{Config: &aws.Config{Region: aws.String("us-west-2")}}
+	roleSess := &session.Session{Config: &aws.Config{Region: aws.String("us-west-2"), Endpoint: aws.String("role")}}
+
+	testCases := map[string]struct {
+		env        *config.Environment
+		setupMocks func(m *mocks.MockregistrySessionProvider)
+
+		wanted    *session.Session
+		wantedErr string
+	}{
+		"uses the default credentials if the environment has no registry role": {
+			env: &config.Environment{Region: "us-west-2"},
+			setupMocks: func(m *mocks.MockregistrySessionProvider) {
+				m.EXPECT().DefaultWithRegion("us-west-2").Return(defaultSess, nil)
+			},
+			wanted: defaultSess,
+		},
+		"assumes the registry role of the environment": {
+			env: &config.Environment{Region: "us-west-2", RegistryRoleARN: "arn:aws:iam::111111111111:role/images"},
+			setupMocks: func(m *mocks.MockregistrySessionProvider) {
+				m.EXPECT().FromRole("arn:aws:iam::111111111111:role/images", "us-west-2").Return(roleSess, nil)
+			},
+			wanted: roleSess,
+		},
+		"returns a wrapped error if the registry role can't be assumed": {
+			env: &config.Environment{Region: "eu-west-1", RegistryRoleARN: "arn:aws:iam::111111111111:role/images"},
+			setupMocks: func(m *mocks.MockregistrySessionProvider) {
+				m.EXPECT().FromRole("arn:aws:iam::111111111111:role/images", "eu-west-1").Return(nil, errors.New("access denied"))
+			},
+			wantedErr: "assume registry role arn:aws:iam::111111111111:role/images in region eu-west-1: access denied",
+		},
+		"returns a wrapped error if the default session can't be created": {
+			env: &config.Environment{Region: "eu-west-1"},
+			setupMocks: func(m *mocks.MockregistrySessionProvider) {
+				m.EXPECT().DefaultWithRegion("eu-west-1").Return(nil, errors.New("some error"))
+			},
+			wantedErr: "create default session with region eu-west-1: some error",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks.NewMockregistrySessionProvider(ctrl)
+			tc.setupMocks(m)
+
+			got, err := RegistrySession(m, tc.env)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Same(t, tc.wanted, got)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ssm"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/copilot-cli/internal/pkg/aws/cloudformation"
 	"github.com/aws/copilot-cli/internal/pkg/aws/ec2"
@@ -150,6 +151,9 @@ type initEnvVars struct {
 	importCerts        []string      // Additional existing ACM certificates to use.
 	internalALBSubnets []string      // Subnets to be used for internal ALB placement.
 	allowVPCIngress    bool          // True means the env stack will create ingress to the internal ALB from ports 80/443.
+	registryRoleARN    string        // Role assumed to push images to the repositories of a centralized image account.
+	remoteBuildProject string        // CodeBuild project that builds images when docker isn't running.
+	remoteBuildBucket  string        // Bucket that the build contexts of the CodeBuild project are uploaded to.
 
 	tempCreds tempCredsVars // Temporary credentials to initialize the environment. Mutually exclusive with the profile.
 	region    string        // The region to create the environment in.
@@ -249,6 +253,12 @@ func (o *initEnvOpts) Validate() error {
 	if err := o.validateCustomizedResources(); err != nil {
 		return err
 	}
+	if o.registryRoleARN != "" && !arn.IsARN(o.registryRoleARN) {
+		return fmt.Errorf("registry role %s must be an IAM role ARN", o.registryRoleARN)
+	}
+	if (o.remoteBuildProject == "") != (o.remoteBuildBucket == "") {
+		return fmt.Errorf("flags --%s and --%s must be specified together", remoteBuildProjectFlag, remoteBuildBucketFlag)
+	}
 	return o.validateCredentials()
 }
 
@@ -329,6 +339,13 @@ func (o *initEnvOpts) Execute() error {
 	if err != nil {
 		return fmt.Errorf("get environment struct for %s: %w", o.name, err)
 	}
+	env.RegistryRoleARN = o.registryRoleARN
+	if o.remoteBuildProject != "" {
+		env.RemoteBuild = &config.RemoteBuild{
+			Project: o.remoteBuildProject,
+			Bucket:  o.remoteBuildBucket,
+		}
+	}
 	if err := o.store.CreateEnvironment(env); err != nil {
 		return fmt.Errorf("store environment: %w", err)
 	}
@@ -954,6 +971,9 @@ func buildEnvInitCmd() *cobra.Command {
 	cmd.Flags().StringSliceVar(&vars.internalALBSubnets, internalALBSubnetsFlag, nil, internalALBSubnetsFlagDescription)
 	cmd.Flags().BoolVar(&vars.allowVPCIngress, allowVPCIngressFlag, false, allowVPCIngressFlagDescription)
 	cmd.Flags().BoolVar(&vars.defaultConfig, defaultConfigFlag, false, defaultConfigFlagDescription)
+	cmd.Flags().StringVar(&vars.registryRoleARN, registryRoleFlag, "", registryRoleFlagDescription)
+	cmd.Flags().StringVar(&vars.remoteBuildProject, remoteBuildProjectFlag, "", remoteBuildProjectFlagDescription)
+	cmd.Flags().StringVar(&vars.remoteBuildBucket, remoteBuildBucketFlag, "", remoteBuildBucketFlagDescription)
 
 	flags := pflag.NewFlagSet("Common", pflag.ContinueOnError)
 	flags.AddFlag(cmd.Flags().Lookup(appFlag))
@@ -965,6 +985,9 @@ func buildEnvInitCmd() *cobra.Command {
 	flags.AddFlag(cmd.Flags().Lookup(regionFlag))
 	flags.AddFlag(cmd.Flags().Lookup(defaultConfigFlag))
 	flags.AddFlag(cmd.Flags().Lookup(allowDowngradeFlag))
+	flags.AddFlag(cmd.Flags().Lookup(registryRoleFlag))
+	flags.AddFlag(cmd.Flags().Lookup(remoteBuildProjectFlag))
+	flags.AddFlag(cmd.Flags().Lookup(remoteBuildBucketFlag))
 
 	resourcesImportFlags := pflag.NewFlagSet("Import Existing Resources", pflag.ContinueOnError)
 	resourcesImportFlags.AddFlag(cmd.Flags().Lookup(vpcIDFlag))
@@ -61,6 +61,9 @@ func TestInitEnvOpts_Validate(t *testing.T) {
 		inSecretAccessKey string
 		inSessionToken    string
 
+		inRegistryRole       string
+		inRemoteBuildProject string
+
 		setupMocks func(m *initEnvMocks)
 
 		wantedErrMsg string
@@ -74,6 +77,28 @@ func TestInitEnvOpts_Validate(t *testing.T) {
 				m.store.EXPECT().GetEnvironment("phonetool", "test-pdx").Return(nil, &config.ErrNoSuchEnvironment{})
 			},
 		},
+		"fail if the registry role is not an ARN": {
+			inEnvName:      "test-pdx",
+			inAppName:      "phonetool",
+			inRegistryRole: "images",
+			setupMocks: func(m *initEnvMocks) {
+				m.wsAppName = "phonetool"
+				m.store.EXPECT().GetApplication("phonetool").Return(nil, nil)
+				m.store.EXPECT().GetEnvironment("phonetool", "test-pdx").Return(nil, &config.ErrNoSuchEnvironment{})
+			},
+			wantedErrMsg: "registry role images must be an IAM role ARN",
+		},
+		"fail if the remote build project is set without a bucket": {
+			inEnvName:            "test-pdx",
+			inAppName:            "phonetool",
+			inRemoteBuildProject: "copilot-remote-build",
+			setupMocks: func(m *initEnvMocks) {
+				m.wsAppName = "phonetool"
+				m.store.EXPECT().GetApplication("phonetool").Return(nil, nil)
+				m.store.EXPECT().GetEnvironment("phonetool", "test-pdx").Return(nil, &config.ErrNoSuchEnvironment{})
+			},
+			wantedErrMsg: "flags --remote-build-project and --remote-build-bucket must be specified together",
+		},
 		"fail if command not run under a workspace": {
 			wantedErrMsg: "could not find an application attached to this workspace, please run `app init` first",
 		},
@@ -299,6 +324,8 @@ func TestInitEnvOpts_Validate(t *testing.T) {
 						SecretAccessKey: tc.inSecretAccessKey,
 						SessionToken:    tc.inSessionToken,
 					},
+					registryRoleARN:    tc.inRegistryRole,
+					remoteBuildProject: tc.inRemoteBuildProject,
 				},
 				store:     m.store,
 				wsAppName: m.wsAppName,
@@ -967,6 +994,9 @@ func TestInitEnvOpts_Execute(t *testing.T) {
 	testCases := map[string]struct {
 		enableContainerInsights bool
 		allowDowngrade          bool
+		registryRole            string
+		remoteBuildProject      string
+		remoteBuildBucket       string
 		setupMocks              func(m *initEnvExecuteMocks)
 		wantedErrorS            string
 	}{
@@ -1158,6 +1188,47 @@ func TestInitEnvOpts_Execute(t *testing.T) {
 					}, nil)
 			},
 		},
+		"stores the registry role and remote build of the environment": {
+			registryRole:       "arn:aws:iam::111111111111:role/images",
+			remoteBuildProject: "copilot-remote-build",
+			remoteBuildBucket:  "copilot-remote-build-contexts",
+			setupMocks: func(m *initEnvExecuteMocks) {
+				m.appVersionGetter.EXPECT().Version().Return(mockAppVersion, nil)
+				m.store.EXPECT().GetApplication("phonetool").Return(&config.Application{Name: "phonetool"}, nil)
+				m.store.EXPECT().CreateEnvironment(&config.Environment{
+					App:       "phonetool",
+					Name:      "test",
+					AccountID: "1234",
+					Region:    "mars-1",
+
+					RegistryRoleARN: "arn:aws:iam::111111111111:role/images",
+					RemoteBuild: &config.RemoteBuild{
+						Project: "copilot-remote-build",
+						Bucket:  "copilot-remote-build-contexts",
+					},
+				}).Return(nil)
+				m.identity.EXPECT().Get().Return(identity.Caller{RootUserARN: "some arn", Account: "1234"}, nil).Times(2)
+				m.manifestWriter.EXPECT().WriteEnvironmentManifest(gomock.Any(), "test").Return("", &workspace.ErrFileExists{
+					FileName: "/environments/test/manifest.yml",
+				})
+				m.iam.EXPECT().CreateECSServiceLinkedRole().Return(nil)
+				m.iam.EXPECT().ListRoleTags(gomock.Eq("phonetool-test-CFNExecutionRole")).Return(nil, errors.New("does not exist"))
+				m.iam.EXPECT().ListRoleTags(gomock.Eq("phonetool-test-EnvManagerRole")).Return(nil, errors.New("does not exist"))
+				m.cfn.EXPECT().Exists("phonetool-test").Return(false, nil)
+				m.deployer.EXPECT().CreateAndRenderEnvironment(gomock.Any(), gomock.Any()).Return(nil)
+				m.deployer.EXPECT().GetEnvironment("phonetool", "test").Return(&config.Environment{
+					AccountID: "1234",
+					Region:    "mars-1",
+					Name:      "test",
+					App:       "phonetool",
+				}, nil)
+				m.deployer.EXPECT().AddEnvToApp(gomock.Any()).Return(nil)
+				m.appCFN.EXPECT().GetAppResourcesByRegion(&config.Application{Name: "phonetool"}, "us-west-2").
+					Return(&stack.AppRegionalResources{
+						S3Bucket: "mockBucket",
+					}, nil)
+			},
+		},
 		"skips creating stack if environment stack already exists": {
 			setupMocks: func(m *initEnvExecuteMocks) {
 				m.appVersionGetter.EXPECT().Version().Return(mockAppVersion, nil)
@@ -1281,7 +1352,10 @@ func TestInitEnvOpts_Execute(t *testing.T) {
 					telemetry: telemetryVars{
 						EnableContainerInsights: tc.enableContainerInsights,
 					},
-					allowAppDowngrade: tc.allowDowngrade,
+					allowAppDowngrade:  tc.allowDowngrade,
+					registryRoleARN:    tc.registryRole,
+					remoteBuildProject: tc.remoteBuildProject,
+					remoteBuildBucket:  tc.remoteBuildBucket,
 				},
 				store:       m.store,
 				envDeployer: m.deployer,
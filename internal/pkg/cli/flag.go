@@ -148,6 +148,9 @@ const (
 	scheduleFlag            = "schedule"
 	domainNameFlag          = "domain"
 	permissionsBoundaryFlag = "permissions-boundary"
+	registryRoleFlag        = "registry-role"
+	remoteBuildProjectFlag  = "remote-build-project"
+	remoteBuildBucketFlag   = "remote-build-bucket"
 	prodEnvFlag             = "prod"
 	deleteSecretFlag        = "delete-secret"
 )
@@ -408,5 +411,12 @@ are also accepted.`
 	secretOverwriteFlagDescription     = "Optional. Whether to overwrite an existing secret."
 	permissionsBoundaryFlagDescription = `Optional. The name or ARN of an existing IAM policy with which to set a
 permissions boundary for all roles generated within the application.`
+	registryRoleFlagDescription = `Optional. The ARN of a role to assume to push images
+for the environment, when the ECR repositories live in another account.`
+	remoteBuildProjectFlagDescription = `Optional. The name of a CodeBuild project that builds and pushes
+images when docker isn't running on the machine that deploys.
+Must be used with --remote-build-bucket.`
+	remoteBuildBucketFlagDescription = `Optional. The name of the S3 bucket that build contexts are uploaded to
+for the CodeBuild project of --remote-build-project.`
 	prodEnvFlagDescription = "If the environment contains production services."
 )
@@ -174,7 +174,7 @@ type imageBuilderPusher interface {
 }
 
 type repositoryLogin interface {
-	Login() (string, error)
+	Login() (string, dockerengine.CredentialSource, error)
 }
 
 type repositoryService interface {
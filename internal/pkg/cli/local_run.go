@@ -13,7 +13,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ssm"
-	"github.com/aws/copilot-cli/internal/pkg/aws/ecr"
 	"github.com/aws/copilot-cli/internal/pkg/aws/identity"
 	"github.com/aws/copilot-cli/internal/pkg/aws/sessions"
 	clideploy "github.com/aws/copilot-cli/internal/pkg/cli/deploy"
@@ -24,7 +23,6 @@ import (
 	"github.com/aws/copilot-cli/internal/pkg/ecs"
 	"github.com/aws/copilot-cli/internal/pkg/exec"
 	"github.com/aws/copilot-cli/internal/pkg/manifest"
-	"github.com/aws/copilot-cli/internal/pkg/repository"
 	"github.com/aws/copilot-cli/internal/pkg/term/prompt"
 	"github.com/aws/copilot-cli/internal/pkg/term/selector"
 	"github.com/aws/copilot-cli/internal/pkg/term/syncbuffer"
@@ -109,17 +107,14 @@ func newLocalRunOpts(vars localRunVars) (*localRunOpts, error) {
 		labeledTermPrinter: labeledTermPrinter,
 	}
 	opts.configureClients = func(o *localRunOpts) (repositoryService, error) {
-		defaultSessEnvRegion, err := o.sessProvider.DefaultWithRegion(o.targetEnv.Region)
-		if err != nil {
-			return nil, fmt.Errorf("create default session with region %s: %w", o.targetEnv.Region, err)
-		}
 		resources, err := cloudformation.New(o.sess, cloudformation.WithProgressTracker(os.Stderr)).GetAppResourcesByRegion(o.targetApp, o.targetEnv.Region)
 		if err != nil {
 			return nil, fmt.Errorf("get application %s resources from region %s: %w", o.appName, o.envName, err)
 		}
-		repoName := clideploy.RepoName(o.appName, o.wkldName)
-		repository := repository.NewWithURI(
-			ecr.New(defaultSessEnvRegion), repoName, resources.RepositoryURLs[o.wkldName])
+		repository, err := clideploy.RegistryRepository(o.sessProvider, o.targetEnv, clideploy.RepoName(o.appName, o.wkldName), resources.RepositoryURLs[o.wkldName])
+		if err != nil {
+			return nil, err
+		}
 		return repository, nil
 	}
 	opts.buildContainerImages = func(o *localRunOpts) error {
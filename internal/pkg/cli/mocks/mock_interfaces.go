@@ -1632,12 +1632,13 @@ func (m *MockrepositoryLogin) EXPECT() *MockrepositoryLoginMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockrepositoryLogin) Login() (string, error) {
+func (m *MockrepositoryLogin) Login() (string, dockerengine.CredentialSource, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login")
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dockerengine.CredentialSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Login indicates an expected call of Login.
@@ -1700,12 +1701,13 @@ func (mr *MockrepositoryServiceMockRecorder) BuildAndPush(ctx, args, w interface
 }
 
 // Login mocks base method.
-func (m *MockrepositoryService) Login() (string, error) {
+func (m *MockrepositoryService) Login() (string, dockerengine.CredentialSource, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login")
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dockerengine.CredentialSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Login indicates an expected call of Login.
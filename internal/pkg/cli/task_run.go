@@ -223,7 +223,7 @@ func newTaskRunOpts(vars runTaskVars) (*runTaskOpts, error) {
 
 	opts.configureRepository = func() error {
 		repoName := fmt.Sprintf(deploy.FmtTaskECRRepoName, opts.groupName)
-		opts.repository = repository.New(ecr.New(opts.sess), repoName)
+		opts.repository = repository.New(ecr.New(opts.sess), repoName).WithPublicRegistry(ecr.NewPublic(opts.sess))
 		return nil
 	}
 
@@ -721,7 +721,7 @@ func (o *runTaskOpts) Execute() error {
 
 	// NOTE: if image is not provided, then we build the image and push to ECR repo
 	if o.image == "" {
-		uri, err := o.repository.Login()
+		uri, _, err := o.repository.Login()
 		if err != nil {
 			return fmt.Errorf("login to docker: %w", err)
 		}
@@ -845,7 +845,7 @@ func TestTaskRunOpts_Execute(t *testing.T) {
 					Command:    []string{},
 					EntryPoint: []string{},
 				}).Return(nil)
-				m.repository.EXPECT().Login().Return(mockRepoURI, errors.New("some error"))
+				m.repository.EXPECT().Login().Return(mockRepoURI, dockerengine.CredentialSource(""), errors.New("some error"))
 				mockHasDefaultCluster(m)
 			},
 			wantedError: errors.New("login to docker: some error"),
@@ -860,7 +860,7 @@ func TestTaskRunOpts_Execute(t *testing.T) {
 					Command:    []string{},
 					EntryPoint: []string{},
 				}).Return(nil)
-				m.repository.EXPECT().Login().Return(mockRepoURI, nil)
+				m.repository.EXPECT().Login().Return(mockRepoURI, dockerengine.CredentialSourceLogin, nil)
 				m.repository.EXPECT().BuildAndPush(ctx, gomock.Any(), gomock.Any())
 				m.deployer.EXPECT().DeployTask(&deploy.CreateTaskResourcesInput{
 					Name:       inGroupName,
@@ -913,7 +913,7 @@ func TestTaskRunOpts_Execute(t *testing.T) {
 				m.provider.EXPECT().Default().Return(&session.Session{}, nil)
 				m.store.EXPECT().GetEnvironment(gomock.Any(), gomock.Any()).AnyTimes()
 				m.deployer.EXPECT().DeployTask(gomock.Any()).AnyTimes()
-				m.repository.EXPECT().Login().Return(mockRepoURI, nil)
+				m.repository.EXPECT().Login().Return(mockRepoURI, dockerengine.CredentialSourceLogin, nil)
 				m.repository.EXPECT().BuildAndPush(ctx, gomock.Eq(
 					&dockerengine.BuildArguments{
 						URI:     mockRepoURI,
@@ -931,7 +931,7 @@ func TestTaskRunOpts_Execute(t *testing.T) {
 				m.provider.EXPECT().Default().Return(&session.Session{}, nil)
 				m.store.EXPECT().GetEnvironment(gomock.Any(), gomock.Any()).AnyTimes()
 				m.deployer.EXPECT().DeployTask(gomock.Any()).AnyTimes()
-				m.repository.EXPECT().Login().Return(mockRepoURI, nil)
+				m.repository.EXPECT().Login().Return(mockRepoURI, dockerengine.CredentialSourceLogin, nil)
 				m.repository.EXPECT().BuildAndPush(ctx, gomock.Eq(
 					&dockerengine.BuildArguments{
 						URI:     mockRepoURI,
@@ -955,7 +955,7 @@ func TestTaskRunOpts_Execute(t *testing.T) {
 					Command:    []string{"/bin/sh", "-c", "curl $ECS_CONTAINER_METADATA_URI_V4"},
 					EntryPoint: []string{"exec", "some command"},
 				}).Times(1).Return(nil)
-				m.repository.EXPECT().Login().Return(mockRepoURI, nil)
+				m.repository.EXPECT().Login().Return(mockRepoURI, dockerengine.CredentialSourceLogin, nil)
 				m.repository.EXPECT().BuildAndPush(ctx, gomock.Eq(&defaultBuildArguments), gomock.Any())
 				m.deployer.EXPECT().DeployTask(&deploy.CreateTaskResourcesInput{
 					Name:       inGroupName,
@@ -23,11 +23,23 @@ type Environment struct {
 	ExecutionRoleARN string `json:"executionRoleARN"` // ARN used by CloudFormation to make modification to the environment stack.
 	ManagerRoleARN   string `json:"managerRoleARN"`   // ARN for the manager role assumed to manipulate the environment and its services.
 
+	// Optional. ARN of the role assumed to push images when the repositories of the application live in another account.
+	RegistryRoleARN string `json:"registryRoleARN,omitempty"`
+	// Optional. Builds images in CodeBuild when the docker engine isn't running on the machine that deploys.
+	RemoteBuild *RemoteBuild `json:"remoteBuild,omitempty"`
+
 	// Fields that store user configuration is no longer updated, but kept for retrofitting purpose.
 	CustomConfig *CustomizeEnv `json:"customConfig,omitempty"` // Deprecated. Custom environment configuration by users. This configuration is now available in the env manifest.
 	Telemetry    *Telemetry    `json:"telemetry,omitempty"`    // Deprecated. Optional environment telemetry features. This configuration is now available in the env manifest.
 }
 
+// RemoteBuild is the CodeBuild project that builds the images of the environment's workloads when docker isn't available.
+// The project and the bucket live in the account of the repositories, in the region of the environment.
+type RemoteBuild struct {
+	Project string `json:"project"` // Name of a CodeBuild project that runs docker in privileged mode and can push to the repositories.
+	Bucket  string `json:"bucket"`  // Name of the S3 bucket that build contexts are uploaded to.
+}
+
 // CustomizeEnv represents the custom environment config.
 type CustomizeEnv struct {
 	ImportVPC                   *ImportVPC `json:"importVPC,omitempty"`
@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// Save writes the image as a tarball to w, so that it can be archived, shipped to airgapped environments,
+// or scanned without going through a registry.
+func (c DockerCmdClient) Save(ctx context.Context, image string, w io.Writer) error {
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"save", image}, exec.Stdout(w)); err != nil {
+		return fmt.Errorf("docker save %s: %w", image, err)
+	}
+	return nil
+}
+
+// Load imports the images of the tarball read from r, for example one written by Save, and returns the loaded image references.
+func (c DockerCmdClient) Load(ctx context.Context, r io.Reader) ([]string, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"load"}, exec.Stdin(r), exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("docker load: %w", err)
+	}
+	return parseLoadedImages(buf.String()), nil
+}
@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Save(t *testing.T) {
+	ctx := context.Background()
+	t.Run("writes the tarball of the image", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"save", "web:latest"}, gomock.Any()).
+			Do(mockStdout("tarball")).Return(nil)
+		c := DockerCmdClient{
+			runner: m,
+		}
+		buf := &bytes.Buffer{}
+
+		err := c.Save(ctx, "web:latest", buf)
+
+		require.NoError(t, err)
+		require.Equal(t, "tarball", buf.String())
+	})
+	t.Run("returns a wrapped error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"save", "web:latest"}, gomock.Any()).Return(errors.New("some error"))
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.Save(ctx, "web:latest", &bytes.Buffer{})
+
+		require.EqualError(t, err, "docker save web:latest: some error")
+	})
+}
+
+func TestDockerCommand_Load(t *testing.T) {
+	ctx := context.Background()
+	t.Run("returns the loaded image references", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"load"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("Loaded image: web:latest\nLoaded image ID: sha256:abc\n")).Return(nil)
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		refs, err := c.Load(ctx, strings.NewReader("tarball"))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"web:latest", "sha256:abc"}, refs)
+	})
+	t.Run("returns a wrapped error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"load"}, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		_, err := c.Load(ctx, strings.NewReader("tarball"))
+
+		require.EqualError(t, err, "docker load: some error")
+	})
+}
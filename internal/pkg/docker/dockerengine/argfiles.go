@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// buildArgs returns the build args of the build: the args read from ArgFiles, in order, overridden by Args.
+func (in *BuildArguments) buildArgs() (map[string]string, error) {
+	if len(in.ArgFiles) == 0 {
+		return in.Args, nil
+	}
+	args := make(map[string]string)
+	for _, file := range in.ArgFiles {
+		fileArgs, err := parseDotenvFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileArgs {
+			args[k] = v
+		}
+	}
+	for k, v := range in.Args {
+		args[k] = v
+	}
+	return args, nil
+}
+
+// parseDotenvFile parses a file of KEY=VALUE lines. Empty lines and lines starting with "#" are skipped,
+// keys can be prefixed with "export", and values can be single-quoted (literal) or double-quoted (with escapes).
+// Unquoted values end at an inline " #" comment.
+func parseDotenvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open build args file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("parse build args file %s: line %d: expected KEY=VALUE", path, lineNum)
+		}
+		value, err := parseDotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("parse build args file %s: line %d: %w", path, lineNum, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read build args file %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+func parseDotenvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '\'', '"':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", fmt.Errorf("missing closing quote %c", quote)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected characters %q after closing quote", rest)
+		}
+		value = value[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return value, nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
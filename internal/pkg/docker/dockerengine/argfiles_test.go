@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildArguments_buildArgs(t *testing.T) {
+	dir := t.TempDir()
+	common := filepath.Join(dir, "common.env")
+	require.NoError(t, os.WriteFile(common, []byte(`# Shared build args.
+GO_VERSION=1.19
+export REGISTRY = public.ecr.aws # Inline comment.
+GREETING="hello\nworld"
+LITERAL='a\nb # not a comment'
+EMPTY=
+`), 0644))
+	override := filepath.Join(dir, "override.env")
+	require.NoError(t, os.WriteFile(override, []byte("GO_VERSION=1.20\nPORT=8080\n"), 0644))
+
+	t.Run("merges args files in order and lets Args take precedence", func(t *testing.T) {
+		in := &BuildArguments{
+			ArgFiles: []string{common, override},
+			Args:     map[string]string{"PORT": "80"},
+		}
+
+		got, err := in.buildArgs()
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"GO_VERSION": "1.20",
+			"REGISTRY":   "public.ecr.aws",
+			"GREETING":   "hello\nworld",
+			"LITERAL":    `a\nb # not a comment`,
+			"EMPTY":      "",
+			"PORT":       "80",
+		}, got)
+	})
+	t.Run("returns an error for malformed lines", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.env")
+		require.NoError(t, os.WriteFile(bad, []byte("GO_VERSION=1.20\nnot an assignment\n"), 0644))
+		in := &BuildArguments{ArgFiles: []string{bad}}
+
+		_, err := in.buildArgs()
+
+		require.EqualError(t, err, "parse build args file "+bad+": line 2: expected KEY=VALUE")
+	})
+	t.Run("returns an error for unterminated quotes", func(t *testing.T) {
+		bad := filepath.Join(dir, "quote.env")
+		require.NoError(t, os.WriteFile(bad, []byte(`KEY="value`), 0644))
+		in := &BuildArguments{ArgFiles: []string{bad}}
+
+		_, err := in.buildArgs()
+
+		require.EqualError(t, err, "parse build args file "+bad+": line 1: missing closing quote \"")
+	})
+	t.Run("returns an error if a file doesn't exist", func(t *testing.T) {
+		in := &BuildArguments{ArgFiles: []string{filepath.Join(dir, "missing.env")}}
+
+		_, err := in.buildArgs()
+
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const bakeDefaultGroup = "default"
+
+// bakeDefinition is the JSON representation of a `docker buildx bake` file.
+// See https://docs.docker.com/build/bake/reference/.
+type bakeDefinition struct {
+	Group  map[string]bakeGroup  `json:"group"`
+	Target map[string]bakeTarget `json:"target"`
+}
+
+type bakeGroup struct {
+	Targets []string `json:"targets"`
+}
+
+type bakeTarget struct {
+	Context    string            `json:"context"`
+	Contexts   map[string]string `json:"contexts,omitempty"`
+	Dockerfile string            `json:"dockerfile"`
+	Tags       []string          `json:"tags"`
+	Target     string            `json:"target,omitempty"`
+	CacheFrom  []string          `json:"cache-from,omitempty"`
+	Platforms  []string          `json:"platforms,omitempty"`
+	Args       map[string]string `json:"args,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// GenerateBakeDefinition returns the JSON bake file that builds each of the targets, keyed by target name, in a single group.
+func GenerateBakeDefinition(targets map[string]*BuildArguments) ([]byte, error) {
+	def := bakeDefinition{
+		Group:  make(map[string]bakeGroup),
+		Target: make(map[string]bakeTarget, len(targets)),
+	}
+	var names []string
+	for name, in := range targets {
+		if len(in.Tags) == 0 {
+			return nil, &errEmptyImageTags{
+				uri: in.URI,
+			}
+		}
+		dfDir := in.ContextDir()
+		// The dockerfile of a bake target is resolved relative to its context.
+		dockerfile, err := filepath.Rel(dfDir, in.Dockerfile)
+		if err != nil {
+			return nil, fmt.Errorf("get path of Dockerfile %s relative to context %s: %w", in.Dockerfile, dfDir, err)
+		}
+		args, err := in.buildArgs()
+		if err != nil {
+			return nil, err
+		}
+		target := bakeTarget{
+			Context:    filepath.ToSlash(dfDir),
+			Contexts:   in.ExtraContexts,
+			Dockerfile: filepath.ToSlash(dockerfile),
+			Target:     in.Target,
+			CacheFrom:  in.CacheFrom,
+			Args:       args,
+			Labels:     in.Labels,
+		}
+		for _, tag := range in.Tags {
+			target.Tags = append(target.Tags, imageName(in.URI, tag))
+		}
+		if in.Platform != "" {
+			target.Platforms = []string{in.Platform}
+		}
+		def.Target[name] = target
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	def.Group[bakeDefaultGroup] = bakeGroup{
+		Targets: names,
+	}
+	return json.MarshalIndent(def, "", "  ")
+}
+
+// Bake builds all the targets concurrently with a single `docker buildx bake` command so that they share the build cache.
+// The targets are keyed by name, for example the name of the container that the image is built for.
+func (c DockerCmdClient) Bake(ctx context.Context, targets map[string]*BuildArguments, w io.Writer) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	def, err := GenerateBakeDefinition(targets)
+	if err != nil {
+		return fmt.Errorf("generate bake definition: %w", err)
+	}
+	args := []string{"buildx", "bake", "--file", "-"}
+	if ci, _ := c.lookupEnv("CI"); ci == "true" {
+		args = append(args, "--progress", "plain")
+	}
+	args = append(args, bakeDefaultGroup)
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdin(bytes.NewReader(def)), exec.Stdout(w), exec.Stderr(w)); err != nil {
+		return fmt.Errorf("bake images: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"io"
+	osexec "os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateBakeDefinition(t *testing.T) {
+	t.Run("returns an error if a target has no tags", func(t *testing.T) {
+		_, err := GenerateBakeDefinition(map[string]*BuildArguments{
+			"web": {URI: "mockURI", Dockerfile: "web/Dockerfile"},
+		})
+		require.EqualError(t, err, "tags to reference an image should not be empty for building and pushing into the ECR repository mockURI")
+	})
+	t.Run("generates one target per container in the default group", func(t *testing.T) {
+		got, err := GenerateBakeDefinition(map[string]*BuildArguments{
+			"web": {
+				URI:        "mockURI",
+				Tags:       []string{"latest"},
+				Dockerfile: "web/Dockerfile",
+				Context:    ".",
+				Platform:   "linux/amd64",
+				Args:       map[string]string{"GOPROXY": "direct"},
+			},
+			"sidecar": {
+				URI:        "mockURI",
+				Tags:       []string{"sidecar-latest"},
+				Dockerfile: "sidecar/Dockerfile",
+				Target:     "prod",
+				CacheFrom:  []string{"mockURI:sidecar-latest"},
+				ExtraContexts: map[string]string{
+					"sharedlib": "lib",
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+  "group": {"default": {"targets": ["sidecar", "web"]}},
+  "target": {
+    "sidecar": {
+      "context": "sidecar",
+      "contexts": {"sharedlib": "lib"},
+      "dockerfile": "Dockerfile",
+      "tags": ["mockURI:sidecar-latest"],
+      "target": "prod",
+      "cache-from": ["mockURI:sidecar-latest"]
+    },
+    "web": {
+      "context": ".",
+      "dockerfile": "web/Dockerfile",
+      "tags": ["mockURI:latest"],
+      "platforms": ["linux/amd64"],
+      "args": {"GOPROXY": "direct"}
+    }
+  }
+}`, string(got))
+	})
+}
+
+func TestDockerCommand_Bake(t *testing.T) {
+	ctx := context.Background()
+	targets := map[string]*BuildArguments{
+		"web": {URI: "mockURI", Tags: []string{"latest"}, Dockerfile: "web/Dockerfile"},
+	}
+
+	t.Run("pipes the bake definition through stdin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var stdin string
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "bake", "--file", "-", "--progress", "plain", "default"}, gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+				cmd := &osexec.Cmd{}
+				for _, opt := range opts {
+					opt(cmd)
+				}
+				b, _ := io.ReadAll(cmd.Stdin)
+				stdin = string(b)
+			}).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(key string) (string, bool) {
+				return "true", key == "CI"
+			},
+		}
+
+		err := s.Bake(ctx, targets, &strings.Builder{})
+
+		require.NoError(t, err)
+		require.Contains(t, stdin, `"mockURI:latest"`)
+	})
+	t.Run("returns a wrapped error if bake fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+
+		err := s.Bake(ctx, targets, &strings.Builder{})
+
+		require.EqualError(t, err, "bake images: some error")
+	})
+}
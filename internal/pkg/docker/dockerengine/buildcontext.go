@@ -0,0 +1,215 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/dustin/go-humanize"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+)
+
+const (
+	dockerignoreFile = ".dockerignore"
+
+	// contextSizeWarningThreshold is the build context size above which a warning is emitted before building.
+	contextSizeWarningThreshold = 1 << 30 // 1 GiB
+)
+
+// BuildContextStats holds the number of files and the total size in bytes of a build context
+// after excluding the paths matched by its ignore file.
+type BuildContextStats struct {
+	Files int
+	Size  int64
+}
+
+// ContextDir returns the build context directory, which defaults to the Dockerfile's directory.
+func (in *BuildArguments) ContextDir() string {
+	if in.Context != "" {
+		return in.Context
+	}
+	return filepath.Dir(in.Dockerfile)
+}
+
+// dockerignorePath returns the path of the ignore file of the build: "<Dockerfile>.dockerignore" if it exists,
+// since it takes precedence, and "<context>/.dockerignore" otherwise.
+func (in *BuildArguments) dockerignorePath() string {
+	if in.Dockerfile != "" && in.Dockerfile != stdinPath {
+		if _, err := os.Stat(in.Dockerfile + dockerignoreFile); err == nil {
+			return in.Dockerfile + dockerignoreFile
+		}
+	}
+	return filepath.Join(in.ContextDir(), dockerignoreFile)
+}
+
+// ContextStats walks the build context directory and returns the files that would be sent to the daemon, honoring the ignore file of the build.
+func (in *BuildArguments) ContextStats() (*BuildContextStats, error) {
+	stats := &BuildContextStats{}
+	err := in.walkContext(func(_, _ string, d fs.DirEntry) error {
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// PackageContext writes the build context directory as a zip archive to w, honoring the ignore file of the build,
+// so that the image can be built somewhere else than on the docker daemon, for example in CodeBuild.
+// The Dockerfile must be inside the context directory, and is always included.
+func (in *BuildArguments) PackageContext(w io.Writer) error {
+	dir := in.ContextDir()
+	dockerfile, err := filepath.Rel(dir, in.Dockerfile)
+	if err != nil || strings.HasPrefix(dockerfile, "..") {
+		return fmt.Errorf("the Dockerfile %s must be inside the build context %s to package it", in.Dockerfile, dir)
+	}
+	zw := zip.NewWriter(w)
+	add := func(path, rel string, d fs.DirEntry) error {
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		} else {
+			hdr.Method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(fw, f)
+		return err
+	}
+	dockerfileAdded := false
+	err = in.walkContext(func(path, rel string, d fs.DirEntry) error {
+		dockerfileAdded = dockerfileAdded || rel == dockerfile
+		return add(path, rel, d)
+	})
+	if err != nil {
+		return err
+	}
+	if !dockerfileAdded {
+		// The Dockerfile is sent to the daemon even if .dockerignore excludes it.
+		info, err := os.Stat(in.Dockerfile)
+		if err != nil {
+			return fmt.Errorf("stat Dockerfile %s: %w", in.Dockerfile, err)
+		}
+		if err := add(in.Dockerfile, dockerfile, fs.FileInfoToDirEntry(info)); err != nil {
+			return fmt.Errorf("add Dockerfile %s to the build context: %w", in.Dockerfile, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close build context archive: %w", err)
+	}
+	return nil
+}
+
+// walkContext calls fn for the directories and files under the build context directory that are not excluded by the ignore file of the build.
+func (in *BuildArguments) walkContext(fn func(path, rel string, d fs.DirEntry) error) error {
+	dir := in.ContextDir()
+	matcher, err := dockerignoreMatcher(in.dockerignorePath())
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		excluded, err := matcher.Matches(rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			// Re-included paths ("!pattern") can live under an excluded directory, so we can only skip the directory if there are none.
+			if d.IsDir() && !matcher.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, rel, d)
+	})
+	if err != nil {
+		return fmt.Errorf("walk build context %s: %w", dir, err)
+	}
+	return nil
+}
+
+// checkContextSize estimates the size of the build context before it's sent to the daemon, unless the build
+// has SkipContextSize without a MaxContextSize. It returns ErrBuildContextTooLarge if the context exceeds MaxContextSize, and warns if it exceeds contextSizeWarningThreshold.
+// The estimation is best-effort: if the context can't be walked, the build is left to report the problem.
+func (in *BuildArguments) checkContextSize() error {
+	if in.Context == stdinPath || in.SkipContextSize && in.MaxContextSize <= 0 {
+		return nil
+	}
+	stats, err := in.ContextStats()
+	if err != nil {
+		return nil
+	}
+	if in.MaxContextSize > 0 && stats.Size > in.MaxContextSize {
+		return &ErrBuildContextTooLarge{
+			Dir:   in.ContextDir(),
+			Stats: *stats,
+			Limit: in.MaxContextSize,
+		}
+	}
+	if stats.Size > contextSizeWarningThreshold {
+		log.Warningf("Sending a build context of %s (%d files) from %s to the docker daemon. Consider adding a %s file to exclude unnecessary files.\n",
+			humanize.IBytes(uint64(stats.Size)), stats.Files, in.ContextDir(), dockerignoreFile)
+	}
+	return nil
+}
+
+// dockerignoreMatcher returns a matcher for the patterns of the ignore file at path, if any, followed by the extra patterns.
+func dockerignoreMatcher(path string, extra ...string) (*fileutils.PatternMatcher, error) {
+	var patterns []string
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		defer f.Close()
+		patterns, err = dockerignore.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	matcher, err := fileutils.NewPatternMatcher(append(patterns, extra...))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return matcher, nil
+}
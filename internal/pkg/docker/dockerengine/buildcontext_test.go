@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildArguments_ContextStats(t *testing.T) {
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+		return dir
+	}
+
+	tests := map[string]struct {
+		files map[string]string
+
+		wanted BuildContextStats
+	}{
+		"counts every file without a .dockerignore": {
+			files: map[string]string{
+				"Dockerfile":   "FROM scratch",
+				"src/main.go":  "package main",
+				"node/mod.txt": "12345",
+			},
+			wanted: BuildContextStats{Files: 3, Size: 29},
+		},
+		"excludes the paths matched by .dockerignore": {
+			files: map[string]string{
+				".dockerignore":           "node_modules\n*.log\n!keep.log\n",
+				"Dockerfile":              "FROM scratch",
+				"debug.log":               "123",
+				"keep.log":                "1234",
+				"node_modules/a/index.js": "12345",
+			},
+			wanted: BuildContextStats{Files: 3, Size: 45},
+		},
+		"prefers the ignore file of the Dockerfile to .dockerignore": {
+			files: map[string]string{
+				".dockerignore":           "*.log\n",
+				"Dockerfile":              "FROM scratch",
+				"Dockerfile.dockerignore": "node_modules\n",
+				"debug.log":               "123",
+				"node_modules/a/index.js": "12345",
+			},
+			wanted: BuildContextStats{Files: 4, Size: 34},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, tc.files)
+			in := BuildArguments{
+				Dockerfile: filepath.Join(dir, "Dockerfile"),
+			}
+
+			got, err := in.ContextStats()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, *got)
+		})
+	}
+}
+
+func TestBuildArguments_PackageContext(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".dockerignore":           "node_modules\nDockerfile\n",
+		"Dockerfile":              "FROM scratch",
+		"src/main.go":             "package main",
+		"node_modules/a/index.js": "12345",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	in := BuildArguments{
+		Dockerfile: filepath.Join(dir, "Dockerfile"),
+	}
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, in.PackageContext(buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		got[f.Name] = string(content)
+	}
+	require.Equal(t, map[string]string{
+		".dockerignore": "node_modules\nDockerfile\n",
+		"Dockerfile":    "FROM scratch",
+		"src/":          "",
+		"src/main.go":   "package main",
+	}, got)
+}
+
+func TestDockerCommand_Build_ContextTooLarge(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch"), 0644))
+	s := DockerCmdClient{
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}
+
+	err := s.Build(context.Background(), &BuildArguments{
+		URI:            "mockURI",
+		Tags:           []string{"latest"},
+		Dockerfile:     filepath.Join(dir, "Dockerfile"),
+		MaxContextSize: 10,
+	}, &strings.Builder{})
+
+	var target *ErrBuildContextTooLarge
+	require.True(t, errors.As(err, &target))
+	require.Equal(t, BuildContextStats{Files: 1, Size: 12}, target.Stats)
+}
+
+func TestBuildArguments_checkContextSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch"), 0644))
+	// A sparse file is enough for the size of the context to exceed the warning threshold.
+	f, err := os.Create(filepath.Join(dir, "data.bin"))
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(contextSizeWarningThreshold+1))
+	require.NoError(t, f.Close())
+	defer func(w io.Writer) { log.DiagnosticWriter = w }(log.DiagnosticWriter)
+
+	t.Run("warns about a large context without a limit", func(t *testing.T) {
+		buf := &strings.Builder{}
+		log.DiagnosticWriter = buf
+
+		err := (&BuildArguments{Dockerfile: filepath.Join(dir, "Dockerfile")}).checkContextSize()
+
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "Sending a build context of 1.0 GiB (2 files)")
+	})
+	t.Run("doesn't walk the context if the check is skipped", func(t *testing.T) {
+		buf := &strings.Builder{}
+		log.DiagnosticWriter = buf
+
+		err := (&BuildArguments{Dockerfile: filepath.Join(dir, "Dockerfile"), SkipContextSize: true}).checkContextSize()
+
+		require.NoError(t, err)
+		require.Empty(t, buf.String())
+	})
+}
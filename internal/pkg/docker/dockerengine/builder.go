@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// DriverDockerContainer is the buildx driver that runs BuildKit in a container.
+// Unlike the default docker driver, it supports multi-platform builds and exporting the build cache.
+const DriverDockerContainer = "docker-container"
+
+// BuilderOptions holds the options to create a buildx builder instance.
+type BuilderOptions struct {
+	Name       string            // Required. Name of the builder instance.
+	Driver     string            // Optional. Defaults to DriverDockerContainer.
+	Platforms  []string          // Optional. Platforms supported by the builder, in addition to the ones detected automatically.
+	DriverOpts map[string]string // Optional. Driver specific options, for example "network": "host".
+	Use        bool              // Optional. Make the builder the current one.
+	Endpoint   string            // Optional. Docker context or daemon address that runs BuildKit, for example "ssh://user@buildbox". Defaults to the current context.
+}
+
+// Builder is a buildx builder instance.
+type Builder struct {
+	Name      string
+	Driver    string
+	Current   bool     // True if the builder is the one used by default.
+	Status    string   // Status of the first node of the builder, for example "running" or "inactive".
+	Platforms []string // Platforms supported by the nodes of the builder.
+}
+
+// CreateBuilder creates a buildx builder instance and starts it.
+func (c DockerCmdClient) CreateBuilder(ctx context.Context, opts *BuilderOptions) error {
+	driver := opts.Driver
+	if driver == "" {
+		driver = DriverDockerContainer
+	}
+	args := []string{"buildx", "create", "--name", opts.Name, "--driver", driver}
+	if len(opts.Platforms) > 0 {
+		args = append(args, "--platform", strings.Join(opts.Platforms, ","))
+	}
+	// Collect the keys in a slice to sort for test stability.
+	var keys []string
+	for k := range opts.DriverOpts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--driver-opt", fmt.Sprintf("%s=%s", k, opts.DriverOpts[k]))
+	}
+	if opts.Use {
+		args = append(args, "--use")
+	}
+	args = append(args, "--bootstrap")
+	if opts.Endpoint != "" {
+		args = append(args, opts.Endpoint)
+	}
+	if err := c.runner.RunWithContext(ctx, "docker", args); err != nil {
+		return fmt.Errorf("create builder %s: %w", opts.Name, err)
+	}
+	return nil
+}
+
+// ListBuilders returns the buildx builder instances. It requires buildx v0.13.0 or later.
+func (c DockerCmdClient) ListBuilders(ctx context.Context) ([]Builder, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"buildx", "ls", "--format", "json"}, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("list builders: %w", err)
+	}
+	type builderNode struct {
+		Status    string   `json:"Status"`
+		Platforms []string `json:"Platforms"`
+	}
+	var builders []Builder
+	// Builders are printed as one JSON object per line.
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var out struct {
+			Name    string        `json:"Name"`
+			Driver  string        `json:"Driver"`
+			Current bool          `json:"Current"`
+			Nodes   []builderNode `json:"Nodes"`
+		}
+		if err := json.Unmarshal([]byte(line), &out); err != nil {
+			return nil, fmt.Errorf("unmarshal builder %q: %w", line, err)
+		}
+		b := Builder{
+			Name:    out.Name,
+			Driver:  out.Driver,
+			Current: out.Current,
+		}
+		seen := make(map[string]bool)
+		for i, node := range out.Nodes {
+			if i == 0 {
+				b.Status = node.Status
+			}
+			for _, p := range node.Platforms {
+				if !seen[p] {
+					seen[p] = true
+					b.Platforms = append(b.Platforms, p)
+				}
+			}
+		}
+		builders = append(builders, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read builders: %w", err)
+	}
+	return builders, nil
+}
+
+// RemoveBuilder removes the buildx builder instance and its build cache.
+func (c DockerCmdClient) RemoveBuilder(ctx context.Context, name string) error {
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"buildx", "rm", name}); err != nil {
+		return fmt.Errorf("remove builder %s: %w", name, err)
+	}
+	return nil
+}
+
+// EnsureBuilder creates the builder instance if there is no builder with the same name.
+func (c DockerCmdClient) EnsureBuilder(ctx context.Context, opts *BuilderOptions) error {
+	builders, err := c.ListBuilders(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range builders {
+		if b.Name == opts.Name {
+			return nil
+		}
+	}
+	return c.CreateBuilder(ctx, opts)
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const mockBuildxLsOutput = `{"Name":"copilot","Driver":"docker-container","Current":true,"Nodes":[{"Name":"copilot0","Status":"running","Platforms":["linux/amd64","linux/arm64"]}]}
+{"Name":"default","Driver":"docker","Nodes":[{"Name":"default","Status":"running","Platforms":["linux/amd64"]}]}
+`
+
+func TestDockerCommand_CreateBuilder(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		opts       *BuilderOptions
+		setupMocks func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"creates a docker-container builder by default": {
+			opts: &BuilderOptions{Name: "copilot"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "create", "--name", "copilot", "--driver", "docker-container", "--bootstrap"}).Return(nil)
+			},
+		},
+		"creates a builder with all the options": {
+			opts: &BuilderOptions{
+				Name:       "copilot",
+				Driver:     "kubernetes",
+				Platforms:  []string{"linux/amd64", "linux/arm64"},
+				DriverOpts: map[string]string{"replicas": "2", "namespace": "builds"},
+				Use:        true,
+			},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "create", "--name", "copilot", "--driver", "kubernetes",
+					"--platform", "linux/amd64,linux/arm64", "--driver-opt", "namespace=builds", "--driver-opt", "replicas=2", "--use", "--bootstrap"}).Return(nil)
+			},
+		},
+		"creates a builder on a remote host": {
+			opts: &BuilderOptions{
+				Name:     "buildbox",
+				Endpoint: "ssh://ec2-user@buildbox",
+			},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "create", "--name", "buildbox", "--driver", "docker-container",
+					"--bootstrap", "ssh://ec2-user@buildbox"}).Return(nil)
+			},
+		},
+		"returns a wrapped error": {
+			opts: &BuilderOptions{Name: "copilot"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "create builder copilot: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			err := s.CreateBuilder(ctx, tc.opts)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDockerCommand_ListBuilders(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "ls", "--format", "json"}, gomock.Any()).
+		Do(mockStdout(mockBuildxLsOutput)).Return(nil)
+	s := DockerCmdClient{
+		runner: m,
+	}
+
+	got, err := s.ListBuilders(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, []Builder{
+		{Name: "copilot", Driver: "docker-container", Current: true, Status: "running", Platforms: []string{"linux/amd64", "linux/arm64"}},
+		{Name: "default", Driver: "docker", Status: "running", Platforms: []string{"linux/amd64"}},
+	}, got)
+}
+
+func TestDockerCommand_EnsureBuilder(t *testing.T) {
+	ctx := context.Background()
+	t.Run("does nothing if the builder exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "ls", "--format", "json"}, gomock.Any()).
+			Do(mockStdout(mockBuildxLsOutput)).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		require.NoError(t, s.EnsureBuilder(ctx, &BuilderOptions{Name: "copilot"}))
+	})
+	t.Run("creates the builder if it doesn't exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "ls", "--format", "json"}, gomock.Any()).
+			Do(mockStdout(mockBuildxLsOutput)).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "create", "--name", "multiarch", "--driver", "docker-container", "--bootstrap"}).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		require.NoError(t, s.EnsureBuilder(ctx, &BuilderOptions{Name: "multiarch"}))
+	})
+}
+
+func TestDockerCommand_RemoveBuilder(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "rm", "copilot"}).Return(errors.New("some error"))
+	s := DockerCmdClient{
+		runner: m,
+	}
+
+	require.EqualError(t, s.RemoveBuilder(ctx, "copilot"), "remove builder copilot: some error")
+}
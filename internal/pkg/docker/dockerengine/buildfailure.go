@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// buildOutputTailSize is the number of bytes at the end of the build output inspected to classify build failures.
+const buildOutputTailSize = 4096
+
+// ErrNoSpaceLeft means the build failed because the disk of the docker daemon is full.
+var ErrNoSpaceLeft = errors.New("no space left on the device of the docker daemon")
+
+var (
+	dockerfileSyntaxErrRegexp = regexp.MustCompile(`dockerfile parse error (?:on )?line (\d+): ([^\n]*)`)
+	buildStageErrRegexps      = []*regexp.Regexp{
+		regexp.MustCompile(`target stage "?([^"\s]+)"? could not be found`),
+		regexp.MustCompile(`failed to reach build target (\S+) in Dockerfile`),
+	}
+	baseImagePullErrRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`failed to resolve source metadata for (\S+?):\s`),
+		regexp.MustCompile(`pull access denied for ([^\s,]+)`),
+		regexp.MustCompile(`manifest for (\S+) not found`),
+	}
+)
+
+// ErrBaseImagePullFailed means the build failed because a base image could not be pulled.
+type ErrBaseImagePullFailed struct {
+	Image string
+	err   error
+}
+
+func (e *ErrBaseImagePullFailed) Error() string {
+	return fmt.Sprintf("pull base image %s: %v", e.Image, e.err)
+}
+
+func (e *ErrBaseImagePullFailed) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrBaseImagePullFailed) RecommendActions() string {
+	return fmt.Sprintf("Check that the image %s exists and that you are logged in to its registry with `docker login`.", e.Image)
+}
+
+// ErrRegistryRateLimited means an image could not be pulled because the registry, typically Docker Hub, throttled the pulls.
+type ErrRegistryRateLimited struct {
+	Image string // Empty if the image couldn't be identified from the output of docker.
+	err   error
+}
+
+func (e *ErrRegistryRateLimited) Error() string {
+	if e.Image == "" {
+		return fmt.Sprintf("registry rate limit reached: %v", e.err)
+	}
+	return fmt.Sprintf("pull image %s: registry rate limit reached: %v", e.Image, e.err)
+}
+
+func (e *ErrRegistryRateLimited) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrRegistryRateLimited) RecommendActions() string {
+	return "Log in to Docker Hub with `docker login` to raise your pull rate limit, " +
+		"or pull the image through an Amazon ECR pull through cache repository or from Amazon ECR Public instead."
+}
+
+// isRateLimited returns true if the output of docker indicates that the registry throttled the pulls.
+func isRateLimited(output string) bool {
+	return strings.Contains(output, "toomanyrequests")
+}
+
+// ErrDockerfileSyntax means the build failed because the Dockerfile can't be parsed.
+type ErrDockerfileSyntax struct {
+	Line int
+	Msg  string
+	err  error
+}
+
+func (e *ErrDockerfileSyntax) Error() string {
+	return fmt.Sprintf("parse Dockerfile at line %d: %s: %v", e.Line, e.Msg, e.err)
+}
+
+func (e *ErrDockerfileSyntax) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrDockerfileSyntax) RecommendActions() string {
+	return fmt.Sprintf("Fix the instruction at line %d of the Dockerfile: %s.", e.Line, e.Msg)
+}
+
+// ErrBuildstageNotFound means the build failed because the target stage doesn't exist in the Dockerfile.
+type ErrBuildstageNotFound struct {
+	Stage string
+	err   error
+}
+
+func (e *ErrBuildstageNotFound) Error() string {
+	return fmt.Sprintf("build stage %s not found in Dockerfile: %v", e.Stage, e.err)
+}
+
+func (e *ErrBuildstageNotFound) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrBuildstageNotFound) RecommendActions() string {
+	return fmt.Sprintf("Update the build target to one of the stages named with `FROM <image> AS <stage>` in the Dockerfile, or add a stage named %s.", e.Stage)
+}
+
+// classifyBuildError returns a typed error describing why the build failed based on the end of its output.
+// If the failure isn't recognized, err is returned unchanged.
+func classifyBuildError(err error, output string) error {
+	if strings.Contains(output, "no space left on device") {
+		return fmt.Errorf("%w: %w", ErrNoSpaceLeft, err)
+	}
+	if m := dockerfileSyntaxErrRegexp.FindStringSubmatch(output); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &ErrDockerfileSyntax{
+			Line: line,
+			Msg:  strings.TrimSpace(m[2]),
+			err:  err,
+		}
+	}
+	for _, re := range buildStageErrRegexps {
+		if m := re.FindStringSubmatch(output); m != nil {
+			return &ErrBuildstageNotFound{
+				Stage: m[1],
+				err:   err,
+			}
+		}
+	}
+	if isRateLimited(output) {
+		rateLimited := &ErrRegistryRateLimited{
+			err: err,
+		}
+		for _, re := range baseImagePullErrRegexps {
+			if m := re.FindStringSubmatch(output); m != nil {
+				rateLimited.Image = m[1]
+				break
+			}
+		}
+		return rateLimited
+	}
+	for _, re := range baseImagePullErrRegexps {
+		if m := re.FindStringSubmatch(output); m != nil {
+			return &ErrBaseImagePullFailed{
+				Image: m[1],
+				err:   err,
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyBuildError(t *testing.T) {
+	exitErr := &mockExitError{code: 1}
+	testCases := map[string]struct {
+		output string
+
+		wanted error
+	}{
+		"unrecognized failure": {
+			output: "RUN go build ./...\n#8 ERROR: process \"/bin/sh -c go build ./...\" did not complete successfully: exit code: 2\n",
+			wanted: exitErr,
+		},
+		"BuildKit base image not found": {
+			output: "ERROR: failed to solve: golang:1.99: failed to resolve source metadata for docker.io/library/golang:1.99: docker.io/library/golang:1.99: not found\n",
+			wanted: &ErrBaseImagePullFailed{Image: "docker.io/library/golang:1.99", err: exitErr},
+		},
+		"legacy builder pull access denied": {
+			output: "Step 1/4 : FROM private/base\nError response from daemon: pull access denied for private/base, repository does not exist or may require 'docker login'\n",
+			wanted: &ErrBaseImagePullFailed{Image: "private/base", err: exitErr},
+		},
+		"BuildKit Docker Hub rate limit": {
+			output: "ERROR: failed to solve: golang:1.20: failed to resolve source metadata for docker.io/library/golang:1.20: " +
+				"failed to copy: httpReadSeeker: failed open: unexpected status code https://registry-1.docker.io/v2/library/golang/manifests/sha256:abc: " +
+				"429 Too Many Requests - Server message: toomanyrequests: You have reached your pull rate limit.\n",
+			wanted: &ErrRegistryRateLimited{Image: "docker.io/library/golang:1.20", err: exitErr},
+		},
+		"legacy builder Docker Hub rate limit": {
+			output: "Step 1/4 : FROM golang:1.20\nError response from daemon: toomanyrequests: You have reached your pull rate limit.\n",
+			wanted: &ErrRegistryRateLimited{err: exitErr},
+		},
+		"BuildKit Dockerfile syntax error": {
+			output: "ERROR: failed to solve: dockerfile parse error on line 5: unknown instruction: RUNN\n",
+			wanted: &ErrDockerfileSyntax{Line: 5, Msg: "unknown instruction: RUNN", err: exitErr},
+		},
+		"legacy builder Dockerfile syntax error": {
+			output: "Error response from daemon: dockerfile parse error line 12: unknown instruction: CPY\n",
+			wanted: &ErrDockerfileSyntax{Line: 12, Msg: "unknown instruction: CPY", err: exitErr},
+		},
+		"BuildKit target stage not found": {
+			output: "ERROR: failed to solve: target stage \"prod\" could not be found\n",
+			wanted: &ErrBuildstageNotFound{Stage: "prod", err: exitErr},
+		},
+		"legacy builder target stage not found": {
+			output: "failed to reach build target prod in Dockerfile\n",
+			wanted: &ErrBuildstageNotFound{Stage: "prod", err: exitErr},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.wanted, classifyBuildError(exitErr, tc.output))
+		})
+	}
+	t.Run("no space left on device", func(t *testing.T) {
+		err := classifyBuildError(exitErr, "ERROR: failed to copy: write /var/lib/docker/tmp/layer: no space left on device\n")
+
+		require.ErrorIs(t, err, ErrNoSpaceLeft)
+		var target exitCoder
+		require.True(t, errors.As(err, &target))
+	})
+}
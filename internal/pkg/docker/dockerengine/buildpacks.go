@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// DefaultBuildpacksBuilder is the builder image used to build with Cloud Native Buildpacks if none is specified.
+const DefaultBuildpacksBuilder = "paketobuildpacks/builder-jammy-base"
+
+// ErrPackCommandNotFound means the pack command is not found.
+var ErrPackCommandNotFound = errors.New("pack: command not found")
+
+// BuildpacksOptions holds the options to build an image with Cloud Native Buildpacks instead of a Dockerfile.
+type BuildpacksOptions struct {
+	Builder    string   // Optional. Builder image providing the buildpacks and the base images. Defaults to DefaultBuildpacksBuilder.
+	Buildpacks []string // Optional. Buildpacks to run, for example "paketo-buildpacks/go". Defaults to the ones detected from the source code.
+}
+
+// BuildpacksResult holds the outcome of a build with Cloud Native Buildpacks.
+type BuildpacksResult struct {
+	Buildpacks []string // Buildpacks that participated in the build, for example "paketo-buildpacks/go-dist 2.3.4".
+	Digest     string   // Digest of the image if it was pushed with PushDirect.
+}
+
+// ErrNoBuildpackDetected means none of the buildpacks of the builder recognized the source code.
+type ErrNoBuildpackDetected struct {
+	Path    string
+	Builder string
+}
+
+func (e *ErrNoBuildpackDetected) Error() string {
+	return fmt.Sprintf("no buildpack of builder %s detected a runtime for the source code in %s", e.Builder, e.Path)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrNoBuildpackDetected) RecommendActions() string {
+	return fmt.Sprintf("Specify the buildpacks to run, use a builder that supports the language of %s, or build the image with a Dockerfile.", e.Path)
+}
+
+// packBuilder builds images with the pack CLI.
+type packBuilder struct {
+	runner   Cmd
+	lookPath func(file string) (string, error) // Override in unit tests.
+}
+
+// BuildWithBuildpacks builds the image from the source code in the build context with Cloud Native Buildpacks, using the pack CLI.
+// The runtime is detected from the source code unless buildpacks are listed in in.Buildpacks. in.Dockerfile is ignored.
+// Only the URI, Tags, Context, Args and Output options apply: the build args are passed to the buildpacks as environment variables,
+// and the image is pushed by pack with PushDirect.
+func (c DockerCmdClient) BuildWithBuildpacks(ctx context.Context, in *BuildArguments, w io.Writer) (*BuildpacksResult, error) {
+	b := &packBuilder{
+		runner:   c.runner,
+		lookPath: osexec.LookPath,
+	}
+	return b.build(ctx, in, w)
+}
+
+func (b *packBuilder) build(ctx context.Context, in *BuildArguments, w io.Writer) (*BuildpacksResult, error) {
+	if _, err := b.lookPath("pack"); err != nil {
+		return nil, ErrPackCommandNotFound
+	}
+	args, err := in.packArgs()
+	if err != nil {
+		return nil, err
+	}
+	parser := &packOutputParser{}
+	lw := newLineWriter(parser, in.displayName())
+	out := io.MultiWriter(w, lw)
+	err = b.runner.RunWithContext(ctx, "pack", args, exec.Stdout(out), exec.Stderr(out))
+	lw.Flush()
+	if err != nil {
+		if parser.noneDetected {
+			return nil, &ErrNoBuildpackDetected{
+				Path:    in.ContextDir(),
+				Builder: in.buildpacksBuilder(),
+			}
+		}
+		return nil, fmt.Errorf("build %s with buildpacks: %w", in.displayName(), err)
+	}
+	return &parser.result, nil
+}
+
+func (in *BuildArguments) buildpacksBuilder() string {
+	if in.Buildpacks.Builder != "" {
+		return in.Buildpacks.Builder
+	}
+	return DefaultBuildpacksBuilder
+}
+
+// packArgs returns the arguments of the `pack build` command.
+func (in *BuildArguments) packArgs() ([]string, error) {
+	if len(in.Tags) == 0 {
+		return nil, &errEmptyImageTags{
+			uri: in.URI,
+		}
+	}
+	args := []string{"build", imageName(in.URI, in.Tags[0]), "--path", in.ContextDir(), "--builder", in.buildpacksBuilder()}
+	for _, bp := range in.Buildpacks.Buildpacks {
+		args = append(args, "--buildpack", bp)
+	}
+	for _, tag := range in.Tags[1:] {
+		args = append(args, "--tag", imageName(in.URI, tag))
+	}
+	buildArgs, err := in.buildArgs()
+	if err != nil {
+		return nil, err
+	}
+	// Collect the keys in a slice to sort for test stability.
+	var keys []string
+	for k := range buildArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--env", fmt.Sprintf("%s=%s", k, buildArgs[k]))
+	}
+	if in.Output == PushDirect {
+		args = append(args, "--publish")
+	}
+	return args, nil
+}
+
+// packOutputParser extracts the participating buildpacks, the digest of the image and detection failures from the output of `pack build`.
+type packOutputParser struct {
+	inDetect     bool
+	noneDetected bool
+	result       BuildpacksResult
+}
+
+// WriteLine implements LineSink.
+func (p *packOutputParser) WriteLine(_, line string) {
+	// Older versions of pack prefix the output of each lifecycle phase, for example "[detector] ".
+	if strings.HasPrefix(line, "[") {
+		if _, rest, ok := strings.Cut(line, "] "); ok {
+			line = rest
+		}
+	}
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "===>"):
+		p.inDetect = false
+	case strings.HasSuffix(line, "buildpacks participating"):
+		p.inDetect = true
+	case strings.Contains(line, "No buildpack groups passed detection"):
+		p.noneDetected = true
+	case strings.HasPrefix(line, "*** Digest:"):
+		p.result.Digest = strings.TrimSpace(strings.TrimPrefix(line, "*** Digest:"))
+	case p.inDetect && line != "":
+		p.result.Buildpacks = append(p.result.Buildpacks, strings.Join(strings.Fields(line), " "))
+	}
+}
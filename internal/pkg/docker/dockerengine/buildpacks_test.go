@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackBuilder_Build(t *testing.T) {
+	ctx := context.Background()
+	in := &BuildArguments{
+		URI:        "mockURI",
+		Tags:       []string{"latest", "v1"},
+		Context:    "web",
+		Args:       map[string]string{"BP_GO_VERSION": "1.20"},
+		Output:     PushDirect,
+		Buildpacks: &BuildpacksOptions{},
+	}
+	wantedArgs := []string{"build", "mockURI:latest", "--path", "web", "--builder", DefaultBuildpacksBuilder,
+		"--tag", "mockURI:v1", "--env", "BP_GO_VERSION=1.20", "--publish"}
+
+	tests := map[string]struct {
+		lookPathErr error
+		setupMocks  func(m *MockCmd)
+
+		wanted    *BuildpacksResult
+		wantedErr error
+	}{
+		"returns the detected buildpacks and the digest of the image": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "pack", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`===> DETECTING
+[detector] 2 of 9 buildpacks participating
+[detector] paketo-buildpacks/ca-certificates 3.6.3
+[detector] paketo-buildpacks/go-dist         2.3.4
+===> ANALYZING
+===> EXPORTING
+*** Digest: sha256:a1b2c3
+Successfully built image mockURI:latest
+`)).Return(nil)
+			},
+			wanted: &BuildpacksResult{
+				Buildpacks: []string{"paketo-buildpacks/ca-certificates 3.6.3", "paketo-buildpacks/go-dist 2.3.4"},
+				Digest:     "sha256:a1b2c3",
+			},
+		},
+		"returns ErrNoBuildpackDetected if no buildpack recognizes the source code": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "pack", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout("===> DETECTING\nERROR: No buildpack groups passed detection.\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: &ErrNoBuildpackDetected{Path: "web", Builder: DefaultBuildpacksBuilder},
+		},
+		"returns a wrapped error if the build fails": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "pack", wantedArgs, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1"))
+			},
+			wantedErr: errors.New("build mockURI:latest with buildpacks: exit status 1"),
+		},
+		"returns ErrPackCommandNotFound if pack isn't installed": {
+			lookPathErr: errors.New("not found"),
+			setupMocks:  func(m *MockCmd) {},
+			wantedErr:   ErrPackCommandNotFound,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			b := &packBuilder{
+				runner: m,
+				lookPath: func(string) (string, error) {
+					return "/usr/local/bin/pack", tc.lookPathErr
+				},
+			}
+
+			got, err := b.build(ctx, in, io.Discard)
+
+			if tc.wantedErr != nil {
+				require.EqualError(t, err, tc.wantedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
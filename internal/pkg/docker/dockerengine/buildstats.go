@@ -0,0 +1,294 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const defaultBuildStatsLimit = 100
+
+var (
+	// BuildKit prints "#5 [build 2/4] RUN go mod download" when a step starts and "#5 CACHED" when it's reused from the cache.
+	buildKitStepRegexp   = regexp.MustCompile(`^#(\d+) \[([^\]]+)\] `)
+	buildKitCachedRegexp = regexp.MustCompile(`^#(\d+) CACHED`)
+	// The legacy builder prints "Step 2/4 : RUN go mod download" followed by " ---> Using cache" if the step is reused.
+	legacyStepRegexp   = regexp.MustCompile(`^Step \d+/\d+ : `)
+	legacyCachedRegexp = regexp.MustCompile(`^ ---> Using cache`)
+	// BuildKit prints "#3 transferring context: 2.05kB done" while it sends the context, and the legacy builder
+	// prints "Sending build context to Docker daemon  2.048kB" once.
+	buildKitContextRegexp = regexp.MustCompile(`^#\d+ transferring context: (\d+(?:\.\d+)?[kMGT]?B)`)
+	legacyContextRegexp   = regexp.MustCompile(`^Sending build context to Docker daemon\s+(\d+(?:\.\d+)?[kMGT]?B)`)
+)
+
+// BuildRecord holds the statistics of a successful build.
+type BuildRecord struct {
+	Service     string        `json:"service"`
+	StartedAt   time.Time     `json:"startedAt"`
+	Duration    time.Duration `json:"duration"`
+	Steps       int           `json:"steps"`
+	CachedSteps int           `json:"cachedSteps"`
+	ContextSize int64         `json:"contextSize"`
+}
+
+// BuildStats aggregates the recorded builds of a service.
+type BuildStats struct {
+	Service            string
+	Builds             int
+	CacheHitRate       float64 // Ratio of the steps reused from the build cache, between 0 and 1.
+	AverageDuration    time.Duration
+	AverageContextSize int64
+	LastBuild          time.Time
+}
+
+// BuildStatsStore keeps the most recent build records of each service in a local JSON file,
+// so that the impact of changes to Dockerfiles or caching settings can be measured over time.
+type BuildStatsStore struct {
+	path  string
+	limit int
+
+	mu sync.Mutex
+}
+
+// NewBuildStatsStore returns a BuildStatsStore backed by the file at path, which is created on the first record.
+func NewBuildStatsStore(path string) *BuildStatsStore {
+	return &BuildStatsStore{
+		path:  path,
+		limit: defaultBuildStatsLimit,
+	}
+}
+
+// Record adds the build to the store, dropping the oldest records of the service beyond the store's limit.
+func (s *BuildStatsStore) Record(rec BuildRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+	var kept []BuildRecord
+	count := 1 // The new record.
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Service == rec.Service {
+			if count >= s.limit {
+				continue
+			}
+			count++
+		}
+		kept = append(kept, records[i])
+	}
+	// Restore chronological order.
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return s.write(append(kept, rec))
+}
+
+// Report returns the statistics of each service, sorted by service name.
+func (s *BuildStatsStore) Report() ([]BuildStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	type totals struct {
+		builds, steps, cached int
+		duration              time.Duration
+		contextSize           int64
+		last                  time.Time
+	}
+	byService := make(map[string]*totals)
+	for _, rec := range records {
+		t, ok := byService[rec.Service]
+		if !ok {
+			t = &totals{}
+			byService[rec.Service] = t
+		}
+		t.builds++
+		t.steps += rec.Steps
+		t.cached += rec.CachedSteps
+		t.duration += rec.Duration
+		t.contextSize += rec.ContextSize
+		if rec.StartedAt.After(t.last) {
+			t.last = rec.StartedAt
+		}
+	}
+	var report []BuildStats
+	for service, t := range byService {
+		stats := BuildStats{
+			Service:            service,
+			Builds:             t.builds,
+			AverageDuration:    t.duration / time.Duration(t.builds),
+			AverageContextSize: t.contextSize / int64(t.builds),
+			LastBuild:          t.last,
+		}
+		if t.steps > 0 {
+			stats.CacheHitRate = float64(t.cached) / float64(t.steps)
+		}
+		report = append(report, stats)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Service < report[j].Service
+	})
+	return report, nil
+}
+
+func (s *BuildStatsStore) read() ([]BuildRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read build stats file %s: %w", s.path, err)
+	}
+	var records []BuildRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("unmarshal build stats file %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+func (s *BuildStatsStore) write(records []BuildRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("marshal build stats: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create directory of build stats file %s: %w", s.path, err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("write build stats file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// BuildAndRecord builds the image like Build and records the statistics of the build for the service in the store.
+// Only successful builds are recorded. Failing to record the statistics doesn't fail the build.
+func (c DockerCmdClient) BuildAndRecord(ctx context.Context, in *BuildArguments, w io.Writer, service string, store *BuildStatsStore) error {
+	counter := &buildStepCounter{}
+	start := time.Now()
+	if err := c.Build(ctx, in, io.MultiWriter(w, counter)); err != nil {
+		return err
+	}
+	counter.flush()
+	rec := BuildRecord{
+		Service:     service,
+		StartedAt:   start,
+		Duration:    time.Since(start),
+		Steps:       counter.steps(),
+		CachedSteps: counter.cachedSteps(),
+		ContextSize: counter.buildContextSize(in),
+	}
+	_ = store.Record(rec)
+	return nil
+}
+
+// buildStepCounter counts the steps of a build and the ones reused from the cache from the build output.
+type buildStepCounter struct {
+	partial []byte
+
+	buildKitSteps map[string]bool // Step IDs keyed to true if the step is cached.
+	legacySteps   int
+	legacyCached  int
+	contextSize   int64 // Size in bytes of the transferred build context, if printed.
+}
+
+func (c *buildStepCounter) Write(p []byte) (int, error) {
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		c.countLine(string(bytes.TrimRight(c.partial[:i], "\r")))
+		c.partial = c.partial[i+1:]
+	}
+}
+
+// buildContextSize returns the size of the build context printed by the builder,
+// or an estimate from the context directory if the builder didn't print it.
+func (c *buildStepCounter) buildContextSize(in *BuildArguments) int64 {
+	if c.contextSize != 0 || in.Context == stdinPath {
+		return c.contextSize
+	}
+	if stats, err := in.ContextStats(); err == nil {
+		return stats.Size
+	}
+	return 0
+}
+
+func (c *buildStepCounter) flush() {
+	if len(c.partial) > 0 {
+		c.countLine(string(c.partial))
+		c.partial = nil
+	}
+}
+
+func (c *buildStepCounter) countLine(line string) {
+	if c.buildKitSteps == nil {
+		c.buildKitSteps = make(map[string]bool)
+	}
+	switch {
+	case buildKitStepRegexp.MatchString(line):
+		m := buildKitStepRegexp.FindStringSubmatch(line)
+		if m[2] == "internal" {
+			// Loading the build definition and the base image metadata are not steps of the Dockerfile.
+			return
+		}
+		if _, ok := c.buildKitSteps[m[1]]; !ok {
+			c.buildKitSteps[m[1]] = false
+		}
+	case buildKitCachedRegexp.MatchString(line):
+		// Only count the steps that were announced, which excludes the internal ones.
+		id := buildKitCachedRegexp.FindStringSubmatch(line)[1]
+		if _, ok := c.buildKitSteps[id]; ok {
+			c.buildKitSteps[id] = true
+		}
+	case legacyStepRegexp.MatchString(line):
+		c.legacySteps++
+	case legacyCachedRegexp.MatchString(line):
+		c.legacyCached++
+	case buildKitContextRegexp.MatchString(line):
+		c.recordContextSize(buildKitContextRegexp.FindStringSubmatch(line)[1])
+	case legacyContextRegexp.MatchString(line):
+		c.recordContextSize(legacyContextRegexp.FindStringSubmatch(line)[1])
+	}
+}
+
+// recordContextSize keeps the largest size of the context, since BuildKit reports the progress of the transfer.
+func (c *buildStepCounter) recordContextSize(size string) {
+	n, err := humanize.ParseBytes(size)
+	if err == nil && int64(n) > c.contextSize {
+		c.contextSize = int64(n)
+	}
+}
+
+func (c *buildStepCounter) steps() int {
+	return len(c.buildKitSteps) + c.legacySteps
+}
+
+func (c *buildStepCounter) cachedSteps() int {
+	cached := c.legacyCached
+	for _, ok := range c.buildKitSteps {
+		if ok {
+			cached++
+		}
+	}
+	return cached
+}
@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatsStore(t *testing.T) {
+	start := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	store := NewBuildStatsStore(filepath.Join(t.TempDir(), "stats", "builds.json"))
+	store.limit = 2
+
+	t.Run("reports nothing before any build is recorded", func(t *testing.T) {
+		report, err := store.Report()
+		require.NoError(t, err)
+		require.Empty(t, report)
+	})
+	t.Run("aggregates the most recent builds of each service", func(t *testing.T) {
+		require.NoError(t, store.Record(BuildRecord{Service: "web", StartedAt: start, Duration: 10 * time.Minute, Steps: 4, CachedSteps: 0, ContextSize: 100}))
+		require.NoError(t, store.Record(BuildRecord{Service: "web", StartedAt: start.Add(time.Hour), Duration: 2 * time.Minute, Steps: 4, CachedSteps: 2, ContextSize: 200}))
+		require.NoError(t, store.Record(BuildRecord{Service: "api", StartedAt: start, Duration: time.Minute, Steps: 2, CachedSteps: 2, ContextSize: 50}))
+		require.NoError(t, store.Record(BuildRecord{Service: "web", StartedAt: start.Add(2 * time.Hour), Duration: 4 * time.Minute, Steps: 4, CachedSteps: 4, ContextSize: 400}))
+
+		report, err := store.Report()
+
+		require.NoError(t, err)
+		require.Equal(t, []BuildStats{
+			{Service: "api", Builds: 1, CacheHitRate: 1, AverageDuration: time.Minute, AverageContextSize: 50, LastBuild: start},
+			{Service: "web", Builds: 2, CacheHitRate: 0.75, AverageDuration: 3 * time.Minute, AverageContextSize: 300, LastBuild: start.Add(2 * time.Hour)},
+		}, report)
+	})
+}
+
+func TestDockerCommand_BuildAndRecord(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0644))
+	store := NewBuildStatsStore(filepath.Join(t.TempDir(), "builds.json"))
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(mockStdout(`#1 [internal] load build definition from Dockerfile
+#5 [build 1/3] FROM docker.io/library/golang:1.20
+#5 CACHED
+#6 [build 2/3] COPY go.mod go.sum ./
+#6 CACHED
+#7 [build 3/3] RUN go build -o /app
+#7 DONE 12.3s
+`)).Return(nil)
+	s := DockerCmdClient{
+		runner: m,
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}
+
+	err := s.BuildAndRecord(ctx, &BuildArguments{
+		URI:        "mockURI",
+		Tags:       []string{"latest"},
+		Dockerfile: filepath.Join(dir, "Dockerfile"),
+	}, &strings.Builder{}, "web", store)
+
+	require.NoError(t, err)
+	report, err := store.Report()
+	require.NoError(t, err)
+	require.Len(t, report, 1)
+	require.Equal(t, "web", report[0].Service)
+	require.Equal(t, int64(13), report[0].AverageContextSize)
+	require.InDelta(t, 2.0/3, report[0].CacheHitRate, 0.001)
+}
+
+func TestBuildStepCounter_buildContextSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0644))
+	in := &BuildArguments{Dockerfile: filepath.Join(dir, "Dockerfile")}
+
+	t.Run("prefers the size printed by the builder", func(t *testing.T) {
+		c := &buildStepCounter{}
+		_, _ = c.Write([]byte("Sending build context to Docker daemon  2.048kB\n"))
+
+		require.Equal(t, int64(2048), c.buildContextSize(in))
+	})
+	t.Run("falls back to the size of the context directory", func(t *testing.T) {
+		require.Equal(t, int64(13), (&buildStepCounter{}).buildContextSize(in))
+	})
+}
+
+func TestBuildStepCounter_legacyBuilder(t *testing.T) {
+	c := &buildStepCounter{}
+
+	_, _ = c.Write([]byte("Sending build context to Docker daemon  2.048kB\nStep 1/3 : FROM golang:1.20\n ---> 2d1e5b4a\nStep 2/3 : COPY . .\n ---> Using cache\n"))
+	_, _ = c.Write([]byte("Step 3/3 : RUN go build\n ---> Running in 8f"))
+	c.flush()
+
+	require.Equal(t, 3, c.steps())
+	require.Equal(t, 1, c.cachedSteps())
+	require.Equal(t, int64(2048), c.contextSize)
+}
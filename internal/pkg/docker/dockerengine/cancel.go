@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// cancelGracePeriod is how long the processes of a canceled command have to clean up, for example to close
+// the BuildKit session of a build, before they're killed.
+const cancelGracePeriod = 10 * time.Second
+
+// processGroupRunner runs the commands with a context in their own process group, so that canceling the context
+// stops the docker CLI along with its plugins and BuildKit sessions instead of leaving them orphaned.
+type processGroupRunner struct {
+	Cmd
+}
+
+func (r processGroupRunner) RunWithContext(ctx context.Context, name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.RunWithContext(ctx, name, args, append(opts, exec.KillProcessGroup(cancelGracePeriod))...)
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"io"
+	osexec "os/exec"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessGroupRunner_RunWithContext(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"build", "."}, gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+			cmd := &osexec.Cmd{}
+			for _, opt := range opts {
+				opt(cmd)
+			}
+			require.Equal(t, io.Discard, cmd.Stdout)
+			require.Equal(t, cancelGracePeriod, cmd.WaitDelay)
+		}).Return(nil)
+
+	err := New(m).runner.RunWithContext(ctx, "docker", []string{"build", "."}, exec.Stdout(io.Discard))
+
+	require.NoError(t, err)
+}
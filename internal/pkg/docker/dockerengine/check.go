@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// CheckFinding is a violation of a Dockerfile rule reported by BuildKit's build checks.
+type CheckFinding struct {
+	Rule        string // Name of the rule, for example "FromAsCasing".
+	Description string // What the rule checks.
+	Detail      string // What is wrong in the Dockerfile.
+	URL         string // Documentation of the rule.
+	Line        int    // Line of the Dockerfile where the violation starts, 0 if unknown.
+}
+
+// CheckBuild lints the Dockerfile with the build checks of BuildKit without running the build, and returns the rule violations.
+// It requires buildx v0.15.0 or later.
+func (c DockerCmdClient) CheckBuild(ctx context.Context, in *BuildArguments) ([]CheckFinding, error) {
+	args, err := in.checkArgs()
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	// buildx exits with a non-zero status if there are violations, so the output is parsed before looking at the error.
+	runErr := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(stdout), exec.Stderr(stderr))
+	var out struct {
+		Warnings []struct {
+			RuleName    string `json:"ruleName"`
+			Description string `json:"description"`
+			Detail      string `json:"detail"`
+			URL         string `json:"url"`
+			Location    struct {
+				Ranges []struct {
+					Start struct {
+						Line int `json:"line"`
+					} `json:"start"`
+				} `json:"ranges"`
+			} `json:"location"`
+		} `json:"warnings"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &out); err != nil {
+		if runErr == nil {
+			return nil, fmt.Errorf("unmarshal build check result: %w", err)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("check build of %s: %w: %s", in.Dockerfile, runErr, msg)
+		}
+		return nil, fmt.Errorf("check build of %s: %w", in.Dockerfile, runErr)
+	}
+	var findings []CheckFinding
+	for _, w := range out.Warnings {
+		finding := CheckFinding{
+			Rule:        w.RuleName,
+			Description: w.Description,
+			Detail:      w.Detail,
+			URL:         w.URL,
+		}
+		if len(w.Location.Ranges) > 0 {
+			finding.Line = w.Location.Ranges[0].Start.Line
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// checkArgs returns the arguments of the `docker buildx build` command that checks the Dockerfile.
+// Only the options that affect how the Dockerfile is interpreted are passed.
+func (in *BuildArguments) checkArgs() ([]string, error) {
+	args := []string{"buildx", "build", "--call=check,format=json"}
+	if in.Target != "" {
+		args = append(args, "--target", in.Target)
+	}
+	if in.Platform != "" {
+		args = append(args, "--platform", in.Platform)
+	}
+	if in.Builder != "" {
+		args = append(args, "--builder", in.Builder)
+	}
+	buildArgs, err := in.buildArgs()
+	if err != nil {
+		return nil, err
+	}
+	// Collect the keys in a slice to sort for test stability.
+	var keys []string
+	for k := range buildArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, buildArgs[k]))
+	}
+	return append(args, in.ContextDir(), "-f", in.Dockerfile), nil
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_CheckBuild(t *testing.T) {
+	ctx := context.Background()
+	in := &BuildArguments{
+		Dockerfile: "web/Dockerfile",
+		Target:     "prod",
+		Args:       map[string]string{"GO_VERSION": "1.20"},
+	}
+	wantedArgs := []string{"buildx", "build", "--call=check,format=json", "--target", "prod",
+		"--build-arg", "GO_VERSION=1.20", "web", "-f", "web/Dockerfile"}
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wanted    []CheckFinding
+		wantedErr string
+	}{
+		"returns nothing if the Dockerfile passes the checks": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{}`)).Return(nil)
+			},
+		},
+		"reports the rule violations": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"warnings":[{"ruleName":"FromAsCasing","description":"The 'as' keyword should match the case of the 'from' keyword","url":"https://docs.docker.com/go/dockerfile/rule/from-as-casing/","detail":"'as' and 'FROM' keywords' casing do not match","location":{"ranges":[{"start":{"line":1},"end":{"line":1}}]}}]}`)).
+					Return(&mockExitError{code: 1})
+			},
+			wanted: []CheckFinding{
+				{
+					Rule:        "FromAsCasing",
+					Description: "The 'as' keyword should match the case of the 'from' keyword",
+					Detail:      "'as' and 'FROM' keywords' casing do not match",
+					URL:         "https://docs.docker.com/go/dockerfile/rule/from-as-casing/",
+					Line:        1,
+				},
+			},
+		},
+		"returns a wrapped error if the check can't run": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Return(errors.New("unknown flag: --call"))
+			},
+			wantedErr: "check build of web/Dockerfile: unknown flag: --call",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.CheckBuild(ctx, in)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// CollapsedBuild is a build that produces the images of one or more builds with identical inputs.
+type CollapsedBuild struct {
+	Names []string        // Names of the builds that the build stands for, sorted.
+	Args  *BuildArguments // Arguments of the build, tagged with the tags of all the builds.
+}
+
+// CollapseIdenticalBuilds groups the builds, keyed by name, whose inputs are identical apart from their tags
+// and the ignoredLabels, so that identical images are built and pushed once with all their tags instead of once per build.
+// The ignored labels are left off a build that stands for more than one build, since no single value describes its image.
+// Builds that read from stdin are never collapsed.
+func CollapseIdenticalBuilds(builds map[string]*BuildArguments, ignoredLabels ...string) []CollapsedBuild {
+	var names []string
+	for name := range builds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var collapsed []CollapsedBuild
+	indexByKey := make(map[string]int)
+	for _, name := range names {
+		in := builds[name]
+		key, ok := in.collapseKey(ignoredLabels)
+		if idx, found := indexByKey[key]; ok && found {
+			cb := &collapsed[idx]
+			cb.Names = append(cb.Names, name)
+			cb.Args.Tags = append(cb.Args.Tags, in.Tags...)
+			for _, label := range ignoredLabels {
+				delete(cb.Args.Labels, label)
+			}
+			continue
+		}
+		args := *in
+		args.Tags = append([]string(nil), in.Tags...)
+		args.Labels = make(map[string]string, len(in.Labels))
+		for k, v := range in.Labels {
+			args.Labels[k] = v
+		}
+		collapsed = append(collapsed, CollapsedBuild{
+			Names: []string{name},
+			Args:  &args,
+		})
+		if ok {
+			indexByKey[key] = len(collapsed) - 1
+		}
+	}
+	return collapsed
+}
+
+// collapseKey returns a key that is identical for builds producing identical images, and false if the build can't be compared.
+func (in *BuildArguments) collapseKey(ignoredLabels []string) (string, bool) {
+	if in.readsStdin() {
+		return "", false
+	}
+	args := *in
+	args.Context = in.ContextDir()
+	args.Tags = nil
+	args.Retry = nil
+	args.SecretStores = nil
+	args.Labels = make(map[string]string, len(in.Labels))
+	for k, v := range in.Labels {
+		args.Labels[k] = v
+	}
+	for _, label := range ignoredLabels {
+		delete(args.Labels, label)
+	}
+	// Maps are marshaled with sorted keys, so identical arguments always produce the same key.
+	key, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseIdenticalBuilds(t *testing.T) {
+	sidecar := func(name string) *BuildArguments {
+		return &BuildArguments{
+			Dockerfile: "sidecars/Dockerfile",
+			Args:       map[string]string{"VERSION": "1"},
+			Tags:       []string{name + "-latest"},
+			Labels:     map[string]string{"com.aws.copilot.image.container.name": name, "com.aws.copilot.image.builder": "copilot-cli"},
+		}
+	}
+	builds := map[string]*BuildArguments{
+		"web": {
+			Dockerfile: "Dockerfile",
+			Tags:       []string{"latest"},
+			Labels:     map[string]string{"com.aws.copilot.image.container.name": "web"},
+		},
+		"logs":    sidecar("logs"),
+		"metrics": sidecar("metrics"),
+		"proxy": func() *BuildArguments {
+			in := sidecar("proxy")
+			in.Args = map[string]string{"VERSION": "2"}
+			return in
+		}(),
+		"stdin": func() *BuildArguments {
+			in := sidecar("stdin")
+			in.Dockerfile = "-"
+			return in
+		}(),
+	}
+
+	got := CollapseIdenticalBuilds(builds, "com.aws.copilot.image.container.name")
+
+	require.Len(t, got, 4)
+	require.Equal(t, []string{"logs", "metrics"}, got[0].Names)
+	require.Equal(t, []string{"logs-latest", "metrics-latest"}, got[0].Args.Tags)
+	require.Equal(t, map[string]string{
+		"com.aws.copilot.image.builder": "copilot-cli",
+	}, got[0].Args.Labels, "the image of a collapsed build must not be labeled with the name of a single container")
+	require.Equal(t, []string{"proxy"}, got[1].Names)
+	require.Equal(t, "proxy", got[1].Args.Labels["com.aws.copilot.image.container.name"], "a build that isn't collapsed keeps its labels")
+	require.Equal(t, []string{"stdin"}, got[2].Names, "builds reading from stdin must never be collapsed")
+	require.Equal(t, []string{"web"}, got[3].Names)
+	require.Equal(t, []string{"logs-latest"}, builds["logs"].Tags, "the input builds must not be modified")
+	require.Equal(t, "logs", builds["logs"].Labels["com.aws.copilot.image.container.name"])
+}
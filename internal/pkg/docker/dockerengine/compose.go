@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"gopkg.in/yaml.v3"
+)
+
+// Categories of sidecar containers, used as compose profiles.
+const (
+	SidecarCategoryLogging       = "logging"
+	SidecarCategoryProxy         = "proxy"
+	SidecarCategoryObservability = "observability"
+)
+
+// wellKnownSidecarImages maps substrings of well-known sidecar images to their category.
+var wellKnownSidecarImages = []struct {
+	substr   string
+	category string
+}{
+	{"aws-for-fluent-bit", SidecarCategoryLogging},
+	{"fluent-bit", SidecarCategoryLogging},
+	{"fluentd", SidecarCategoryLogging},
+	{"aws-appmesh-envoy", SidecarCategoryProxy},
+	{"envoy", SidecarCategoryProxy},
+	{"nginx", SidecarCategoryProxy},
+	{"aws-otel-collector", SidecarCategoryObservability},
+	{"aws-xray-daemon", SidecarCategoryObservability},
+	{"datadog/agent", SidecarCategoryObservability},
+	{"newrelic", SidecarCategoryObservability},
+	{"cwagent", SidecarCategoryObservability},
+}
+
+// SidecarCategory returns the category of a well-known sidecar image, or an empty string if the image isn't recognized.
+func SidecarCategory(image string) string {
+	for _, known := range wellKnownSidecarImages {
+		if strings.Contains(image, known.substr) {
+			return known.category
+		}
+	}
+	return ""
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image         string            `yaml:"image"`
+	ContainerName string            `yaml:"container_name,omitempty"`
+	Command       []string          `yaml:"command,omitempty"`
+	Environment   map[string]string `yaml:"environment,omitempty"`
+	Ports         []string          `yaml:"ports,omitempty"`
+	NetworkMode   string            `yaml:"network_mode,omitempty"`
+	Profiles      []string          `yaml:"profiles,omitempty"`
+}
+
+// GenerateComposeFile returns a compose file running the containers of the task.
+// Sidecars are assigned a profile named after their category, so that they start only if the profile is enabled.
+func GenerateComposeFile(spec *TaskSpec) ([]byte, error) {
+	file := composeFile{
+		Services: make(map[string]composeService, len(spec.Containers)),
+	}
+	for _, container := range spec.Containers {
+		if container.ContainerName == "" {
+			return nil, fmt.Errorf("container running image %s must have a name to be exported to compose", container.ImageURI)
+		}
+		svc := composeService{
+			Image:         container.ImageURI,
+			ContainerName: container.ContainerName,
+			Command:       container.Command,
+		}
+		if len(container.EnvVars)+len(container.Secrets) > 0 {
+			svc.Environment = make(map[string]string)
+			for k, v := range container.EnvVars {
+				svc.Environment[k] = v
+			}
+			for k, v := range container.Secrets {
+				svc.Environment[k] = v
+			}
+		}
+		for hostPort, containerPort := range container.ContainerPorts {
+			svc.Ports = append(svc.Ports, fmt.Sprintf("%s:%s", hostPort, containerPort))
+		}
+		sort.Strings(svc.Ports)
+		if container.ContainerNetwork != "" {
+			svc.NetworkMode = "service:" + container.ContainerNetwork
+		}
+		if category := spec.SidecarCategories[container.ContainerName]; category != "" {
+			svc.Profiles = []string{category}
+		}
+		file.Services[container.ContainerName] = svc
+	}
+	out, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("marshal compose file: %w", err)
+	}
+	return out, nil
+}
+
+// ComposeUp starts the containers of the task with `docker compose` under the project name and waits until they exit.
+// The sidecars of all categories start, except for the categories listed in skip, for example to bring up the task
+// without its observability sidecars.
+// If the client has a trust policy, the containers only start if the signatures of their images are trusted.
+func (c DockerCmdClient) ComposeUp(ctx context.Context, project string, spec *TaskSpec, skip []string, w io.Writer) error {
+	spec, err := c.trustedTask(ctx, spec)
+	if err != nil {
+		return err
+	}
+	file, err := GenerateComposeFile(spec)
+	if err != nil {
+		return err
+	}
+	skipped := make(map[string]bool)
+	for _, category := range skip {
+		skipped[category] = true
+	}
+	var profiles []string
+	seen := make(map[string]bool)
+	for _, category := range spec.SidecarCategories {
+		if category == "" || skipped[category] || seen[category] {
+			continue
+		}
+		seen[category] = true
+		profiles = append(profiles, category)
+	}
+	sort.Strings(profiles)
+	args := []string{"compose", "--project-name", project, "--file", "-"}
+	for _, profile := range profiles {
+		args = append(args, "--profile", profile)
+	}
+	args = append(args, "up")
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdin(bytes.NewReader(file)), exec.Stdout(w), exec.Stderr(w)); err != nil {
+		return fmt.Errorf("compose up project %s: %w", project, err)
+	}
+	return nil
+}
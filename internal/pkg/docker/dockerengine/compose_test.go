@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"io"
+	osexec "os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSidecarCategory(t *testing.T) {
+	require.Equal(t, SidecarCategoryLogging, SidecarCategory("public.ecr.aws/aws-observability/aws-for-fluent-bit:stable"))
+	require.Equal(t, SidecarCategoryProxy, SidecarCategory("public.ecr.aws/appmesh/aws-appmesh-envoy:v1.25.1.0-prod"))
+	require.Equal(t, SidecarCategoryObservability, SidecarCategory("public.ecr.aws/aws-observability/aws-otel-collector:latest"))
+	require.Empty(t, SidecarCategory("mockURI:latest"))
+}
+
+func TestDockerCommand_ComposeUp(t *testing.T) {
+	ctx := context.Background()
+	spec := &TaskSpec{
+		Containers: []*RunOptions{
+			{ImageURI: "pause", ContainerName: "pause", ContainerPorts: map[string]string{"8080": "80"}},
+			{ImageURI: "mockURI:latest", ContainerName: "web", ContainerNetwork: "pause", EnvVars: map[string]string{"LOG_LEVEL": "debug"}},
+			{ImageURI: "fluent-bit", ContainerName: "firelens", ContainerNetwork: "pause"},
+			{ImageURI: "aws-otel-collector", ContainerName: "otel", ContainerNetwork: "pause", Command: []string{"--config", "/etc/otel.yaml"}},
+		},
+		SidecarCategories: map[string]string{
+			"firelens": SidecarCategoryLogging,
+			"otel":     SidecarCategoryObservability,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	var gotFile string
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"compose", "--project-name", "copilot-app-web", "--file", "-", "--profile", "logging", "up"}, gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+			cmd := &osexec.Cmd{}
+			for _, opt := range opts {
+				opt(cmd)
+			}
+			data, err := io.ReadAll(cmd.Stdin)
+			require.NoError(t, err)
+			gotFile = string(data)
+		}).Return(nil)
+	s := DockerCmdClient{
+		runner: m,
+	}
+
+	err := s.ComposeUp(ctx, "copilot-app-web", spec, []string{SidecarCategoryObservability}, &strings.Builder{})
+
+	require.NoError(t, err)
+	require.YAMLEq(t, `services:
+  pause:
+    image: pause
+    container_name: pause
+    ports: ["8080:80"]
+  web:
+    image: mockURI:latest
+    container_name: web
+    environment:
+      LOG_LEVEL: debug
+    network_mode: service:pause
+  firelens:
+    image: fluent-bit
+    container_name: firelens
+    network_mode: service:pause
+    profiles: [logging]
+  otel:
+    image: aws-otel-collector
+    container_name: otel
+    command: ["--config", "/etc/otel.yaml"]
+    network_mode: service:pause
+    profiles: [observability]
+`, gotFile)
+}
+
+func TestGenerateComposeFile_requiresContainerNames(t *testing.T) {
+	_, err := GenerateComposeFile(&TaskSpec{Containers: []*RunOptions{{ImageURI: "mockURI"}}})
+
+	require.EqualError(t, err, "container running image mockURI must have a name to be exported to compose")
+}
@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// dockerAuth is an entry of the "auths" section of the docker config file.
+type dockerAuth struct {
+	Auth          string `json:"auth,omitempty"`          // Base64 encoding of "<username>:<password>".
+	IdentityToken string `json:"identitytoken,omitempty"` // Refresh token of registries using OAuth.
+}
+
+// RegistryCredentials are the credentials of a registry stored in the docker config file by a previous login.
+type RegistryCredentials struct {
+	Username      string
+	Password      string
+	IdentityToken string
+}
+
+// InStore returns true if the docker config file only records the login, and the credentials are in the credential store.
+func (c RegistryCredentials) InStore() bool {
+	return c == RegistryCredentials{}
+}
+
+// StoredCredentials returns the credentials of the "auths" section of the docker config file keyed by registry host,
+// like "registry.example.com" or "index.docker.io", so that callers can decide whether a login is necessary.
+// The config file is read from DOCKER_CONFIG if it's set. Entries whose credentials can't be decoded are skipped.
+func (c DockerCmdClient) StoredCredentials() map[string]RegistryCredentials {
+	creds := make(map[string]RegistryCredentials)
+	for _, config := range c.dockerConfigs() {
+		for server, auth := range config.Auths {
+			cred, ok := auth.credentials()
+			if !ok {
+				continue
+			}
+			registry := registryHost(server)
+			if _, ok := creds[registry]; !ok {
+				creds[registry] = cred
+			}
+		}
+	}
+	return creds
+}
+
+// credentials decodes the credentials of the entry. It returns false if they're malformed.
+func (a dockerAuth) credentials() (RegistryCredentials, bool) {
+	cred := RegistryCredentials{
+		IdentityToken: a.IdentityToken,
+	}
+	if a.Auth == "" {
+		return cred, true
+	}
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return RegistryCredentials{}, false
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return RegistryCredentials{}, false
+	}
+	cred.Username, cred.Password = username, password
+	return cred, true
+}
+
+// registryHost returns the host of a server of the docker config file, like "index.docker.io" for "https://index.docker.io/v1/".
+func registryHost(server string) string {
+	if _, host, ok := strings.Cut(server, "://"); ok {
+		server = host
+	}
+	host, _, _ := strings.Cut(server, "/")
+	return host
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_StoredCredentials(t *testing.T) {
+	const config = `{
+  "credsStore": "desktop",
+  "auths": {
+    "registry.example.com": {"auth": "dXNlcjpwYXNzOndvcmQ="},
+    "https://index.docker.io/v1/": {"identitytoken": "token"},
+    "other.example.com": {},
+    "broken.example.com": {"auth": "not base64"}
+  }
+}`
+	wanted := map[string]RegistryCredentials{
+		"registry.example.com": {Username: "user", Password: "pass:word"},
+		"index.docker.io":      {IdentityToken: "token"},
+		"other.example.com":    {},
+	}
+
+	t.Run("reads the config file in DOCKER_CONFIG", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, dockerConfigFile), []byte(config), 0644))
+		s := DockerCmdClient{
+			homePath: t.TempDir(),
+			lookupEnv: func(key string) (string, bool) {
+				if key == dockerConfigEnv {
+					return dir, true
+				}
+				return "", false
+			},
+		}
+
+		got := s.StoredCredentials()
+
+		require.Equal(t, wanted, got)
+		require.True(t, got["other.example.com"].InStore())
+	})
+	t.Run("reads the config file in the home directory", func(t *testing.T) {
+		home := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(home, ".docker"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".docker", dockerConfigFile), []byte(config), 0644))
+		s := DockerCmdClient{
+			homePath: home,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+
+		require.Equal(t, wanted, s.StoredCredentials())
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/google/uuid"
+)
+
+const runOnceContainerPrefix = "copilot-run-once-"
+
+// ExecResult holds the output of a command executed in a container.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// exitCoder is implemented by errors of processes that exited with a non-zero status, such as *exec.ExitError.
+type exitCoder interface {
+	ExitCode() int
+}
+
+// ExecCapture runs cmd inside the running container without a TTY and captures its stdout, stderr and exit code.
+// A non-zero exit code is reported in the result rather than as an error.
+func (c DockerCmdClient) ExecCapture(ctx context.Context, container string, cmd []string) (*ExecResult, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"exec"}
+	if c.stdin != nil {
+		args = append(args, "--interactive")
+	}
+	args = append(append(args, container), cmd...)
+	err := c.runner.RunWithContext(ctx, "docker", args, c.withStdin(exec.Stdout(stdout), exec.Stderr(stderr))...)
+	result := &ExecResult{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+	if err == nil {
+		return result, nil
+	}
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	return nil, fmt.Errorf("docker exec in container %s: %w", container, err)
+}
+
+// RunOnceResult holds the outcome of a container that ran to completion.
+type RunOnceResult struct {
+	ContainerName string
+	ExitCode      int
+	Stdout        string
+	Stderr        string
+	Duration      time.Duration
+	TimedOut      bool // True if the container was stopped because it didn't complete before the timeout.
+}
+
+// RunOnce runs a container to completion and removes it afterwards, for example to run a database migration or a job.
+// If timeout is greater than zero and the container is still running after it, the container is removed and the result is marked as TimedOut.
+// The container is removed as well if ctx is canceled before it completes.
+// A non-zero exit code is reported in the result rather than as an error.
+// If the client has a trust policy, the container only runs if the signature of its image is trusted.
+// The container is removed when it exits, so it can't have a restart policy.
+func (c DockerCmdClient) RunOnce(ctx context.Context, options *RunOptions, timeout time.Duration) (*RunOnceResult, error) {
+	if options.RestartPolicy != "" && options.RestartPolicy != "no" {
+		// docker run rejects --rm with restart policies.
+		return nil, fmt.Errorf("restart policy %q is not supported for containers that run once", options.RestartPolicy)
+	}
+	trusted, err := c.trustedRunOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if trusted, err = c.withFakeTimeLib(ctx, trusted); err != nil {
+		return nil, err
+	}
+	opts := *trusted
+	if opts.ContainerName == "" {
+		opts.ContainerName = runOnceContainerPrefix + uuid.NewString()
+	}
+	args := append([]string{"run", "--rm"}, opts.generateRunArguments()[1:]...)
+
+	runCtx, cancel := ctx, func() {}
+	if timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	start := time.Now()
+	err = c.runner.RunWithContext(runCtx, "docker", args, exec.Stdout(stdout), exec.Stderr(stderr))
+	result := &RunOnceResult{
+		ContainerName: opts.ContainerName,
+		Stdout:        stdout.String(),
+		Stderr:        stderr.String(),
+		Duration:      time.Since(start),
+	}
+	if err == nil {
+		return result, nil
+	}
+	if runCtx.Err() != nil {
+		// Killing the docker CLI doesn't stop the container, so it has to be removed explicitly.
+		// ctx may be canceled already, so we use a fresh context to clean up.
+		if err := c.forceRemoveContainer(context.Background(), opts.ContainerName); err != nil {
+			return nil, fmt.Errorf("remove container %s: %w", opts.ContainerName, err)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("run container %s: %w", opts.ContainerName, ctx.Err())
+		}
+		result.TimedOut = true
+		return result, nil
+	}
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	return nil, fmt.Errorf("run container %s: %w", opts.ContainerName, err)
+}
+
+// forceRemoveContainer stops and removes the container.
+func (c DockerCmdClient) forceRemoveContainer(ctx context.Context, name string) error {
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"rm", "--force", name}); err != nil {
+		return fmt.Errorf("docker rm %s: %w", name, err)
+	}
+	return nil
+}
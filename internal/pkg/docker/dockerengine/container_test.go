@@ -0,0 +1,169 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	osexec "os/exec"
+	"testing"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExitError struct {
+	code int
+}
+
+func (e *mockExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e *mockExitError) ExitCode() int {
+	return e.code
+}
+
+func TestDockerCommand_ExecCapture(t *testing.T) {
+	ctx := context.Background()
+	writeOutput := func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+		cmd := &osexec.Cmd{}
+		for _, opt := range opts {
+			opt(cmd)
+		}
+		_, _ = cmd.Stdout.Write([]byte("out"))
+		_, _ = cmd.Stderr.Write([]byte("err"))
+	}
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wanted    *ExecResult
+		wantedErr string
+	}{
+		"captures stdout and stderr separately": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"exec", "mockContainer", "cat", "/etc/hosts"}, gomock.Any(), gomock.Any()).
+					Do(writeOutput).Return(nil)
+			},
+			wanted: &ExecResult{Stdout: "out", Stderr: "err"},
+		},
+		"reports a non-zero exit code as part of the result": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(writeOutput).Return(&mockExitError{code: 3})
+			},
+			wanted: &ExecResult{Stdout: "out", Stderr: "err", ExitCode: 3},
+		},
+		"returns a wrapped error if the command cannot be run": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "docker exec in container mockContainer: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.ExecCapture(ctx, "mockContainer", []string{"cat", "/etc/hosts"})
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerCommand_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	opts := &RunOptions{
+		ImageURI:      "mockImage",
+		ContainerName: "migrate",
+		Command:       []string{"./migrate"},
+	}
+	wantedArgs := []string{"run", "--rm", "--name", "migrate", "mockImage", "./migrate"}
+
+	tests := map[string]struct {
+		timeout       time.Duration
+		restartPolicy string
+		setupMocks    func(m *MockCmd)
+
+		wanted    *RunOnceResult
+		wantedErr string
+	}{
+		"captures the output of a successful run": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout("done")).Return(nil)
+			},
+			wanted: &RunOnceResult{ContainerName: "migrate", Stdout: "done"},
+		},
+		"reports the exit code of a failed run": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Return(&mockExitError{code: 1})
+			},
+			wanted: &RunOnceResult{ContainerName: "migrate", ExitCode: 1},
+		},
+		"removes the container when the timeout is reached": {
+			timeout: time.Millisecond,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ string, _ []string, _ ...exec.CmdOption) error {
+						<-ctx.Done()
+						return ctx.Err()
+					})
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"rm", "--force", "migrate"}).Return(nil)
+			},
+			wanted: &RunOnceResult{ContainerName: "migrate", TimedOut: true},
+		},
+		"returns a wrapped error if the container cannot be run": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Return(errors.New("some error"))
+			},
+			wantedErr: "run container migrate: some error",
+		},
+		"errors if the container has a restart policy": {
+			restartPolicy: "always",
+			setupMocks:    func(m *MockCmd) {},
+			wantedErr:     `restart policy "always" is not supported for containers that run once`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			in := *opts
+			in.RestartPolicy = tc.restartPolicy
+
+			got, err := s.RunOnce(ctx, &in, tc.timeout)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			got.Duration = 0
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
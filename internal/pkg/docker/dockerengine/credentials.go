@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// CredentialSource is where the credentials of a registry come from.
+type CredentialSource string
+
+// Sources of the credentials of a registry, in the order CredentialResolver consults them.
+const (
+	CredentialSourceHelper CredentialSource = "credHelpers" // Credential helper configured for the registry.
+	CredentialSourceStore  CredentialSource = "credsStore"  // Credential store configured for all the registries.
+	CredentialSourceAuths  CredentialSource = "auths"       // Credentials stored by a previous login.
+	CredentialSourceLogin  CredentialSource = "login"       // Credentials stored by a new login.
+)
+
+// ecrPrivateRegistryPattern matches the registries of private ECR repositories, like "123456789012.dkr.ecr.us-west-2.amazonaws.com".
+var ecrPrivateRegistryPattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr(-fips)?\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// CredentialResolver finds how docker gets the credentials of a registry from the docker config file,
+// and only logs in to the registry if none of the configured sources provides them.
+type CredentialResolver struct {
+	client DockerCmdClient
+}
+
+// NewCredentialResolver returns a CredentialResolver that reads the docker config file of the client and logs in with it.
+func NewCredentialResolver(client DockerCmdClient) *CredentialResolver {
+	return &CredentialResolver{
+		client: client,
+	}
+}
+
+// Resolve makes sure that docker has credentials for the registry of uri, and returns where they come from.
+// It consults, in order, the credential helper of the registry, the global credential store, and the credentials
+// stored by previous logins in the config file. If none of them applies, it logs in with the credentials returned by auth.
+// Credentials of ECR registries expire, so the ones stored by previous logins or by a generic helper
+// are never trusted for them: only the ecr-login helper is. A generic credential store is only trusted
+// for a registry if it holds credentials for it.
+func (r *CredentialResolver) Resolve(uri string, auth func() (username, password string, err error)) (CredentialSource, error) {
+	registry, _, _ := strings.Cut(uri, "/")
+	if source, ok := r.configured(registry); ok {
+		return source, nil
+	}
+	username, password, err := auth()
+	if err != nil {
+		return "", fmt.Errorf("get auth: %w", err)
+	}
+	if err := r.client.Login(uri, username, password); err != nil {
+		return "", err
+	}
+	return CredentialSourceLogin, nil
+}
+
+// configured returns the source in the docker config files that provides the credentials of the registry, if any.
+func (r *CredentialResolver) configured(registry string) (CredentialSource, bool) {
+	// Tokens of both private and public ECR registries expire after 12 hours.
+	expiring := ecrPrivateRegistryPattern.MatchString(registry) || registry == ECRPublicRegistry
+	configs := r.client.dockerConfigs()
+	for _, config := range configs {
+		if helper := config.CredHelpers[registry]; helper == credStoreECRLogin || helper != "" && !expiring {
+			return CredentialSourceHelper, true
+		}
+	}
+	if expiring {
+		for _, config := range configs {
+			if config.CredsStore == credStoreECRLogin {
+				return CredentialSourceStore, true
+			}
+		}
+		return "", false
+	}
+	_, stored := r.client.StoredCredentials()[registry]
+	for _, config := range configs {
+		if config.CredsStore == "" || config.CredsStore == credStoreECRLogin {
+			continue
+		}
+		// Credential stores like "desktop" or "osxkeychain" keep the credentials of previous logins,
+		// which leave an entry without credentials in "auths".
+		if stored || r.storeHasCredentials(config.CredsStore, registry) {
+			return CredentialSourceStore, true
+		}
+	}
+	if stored {
+		return CredentialSourceAuths, true
+	}
+	return "", false
+}
+
+// storeHasCredentials returns true if the credential store holds credentials for the registry.
+func (r *CredentialResolver) storeHasCredentials(store, registry string) bool {
+	stdout := &bytes.Buffer{}
+	if err := r.client.runner.Run("docker-credential-"+store, []string{"get"},
+		exec.Stdin(strings.NewReader(registry)), exec.Stdout(stdout), exec.Stderr(io.Discard)); err != nil {
+		// The helper exits with an error if it has no credentials for the registry.
+		return false
+	}
+	var creds struct {
+		Secret string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return false
+	}
+	return creds.Secret != ""
+}
@@ -0,0 +1,158 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"errors"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialResolver_Resolve(t *testing.T) {
+	const (
+		ecrURI     = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"
+		ecrHost    = "123456789012.dkr.ecr.us-west-2.amazonaws.com"
+		privateURI = "registry.example.com/team/svc"
+		publicURI  = "public.ecr.aws/alias/svc"
+	)
+	auth := func() (string, string, error) {
+		return "AWS", "password", nil
+	}
+	tests := map[string]struct {
+		uri       string
+		config    string
+		auth      func() (string, string, error)
+		setupMock func(m *MockCmd)
+
+		wanted    CredentialSource
+		wantedErr string
+	}{
+		"uses the ecr-login helper of the registry": {
+			uri:    ecrURI,
+			config: `{"credHelpers":{"` + ecrHost + `":"ecr-login"}}`,
+			wanted: CredentialSourceHelper,
+		},
+		"uses the helper of a registry whose credentials don't expire": {
+			uri:    privateURI,
+			config: `{"credHelpers":{"registry.example.com":"pass"}}`,
+			wanted: CredentialSourceHelper,
+		},
+		"uses the global ecr-login store for ECR registries": {
+			uri:    ecrURI,
+			config: `{"credsStore":"ecr-login"}`,
+			wanted: CredentialSourceStore,
+		},
+		"uses a generic store that holds the credentials of registries whose credentials don't expire": {
+			uri:    privateURI,
+			config: `{"credsStore":"desktop"}`,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker-credential-desktop", []string{"get"}, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ string, _ []string, opts ...exec.CmdOption) error {
+						cmd := &osexec.Cmd{}
+						for _, opt := range opts {
+							opt(cmd)
+						}
+						if stdin, _ := io.ReadAll(cmd.Stdin); string(stdin) != "registry.example.com" {
+							return errors.New("credentials not found in native keychain")
+						}
+						_, err := cmd.Stdout.Write([]byte(`{"ServerURL":"registry.example.com","Username":"user","Secret":"pass"}`))
+						return err
+					})
+			},
+			wanted: CredentialSourceStore,
+		},
+		"uses a generic store if a previous login left an entry in auths": {
+			uri:    privateURI,
+			config: `{"credsStore":"desktop","auths":{"registry.example.com":{}}}`,
+			wanted: CredentialSourceStore,
+		},
+		"logs in if the generic store has no credentials for the registry": {
+			uri:    privateURI,
+			config: `{"credsStore":"desktop"}`,
+			auth:   auth,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker-credential-desktop", []string{"get"}, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("credentials not found in native keychain"))
+				m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", privateURI}, gomock.Any()).Return(nil)
+			},
+			wanted: CredentialSourceLogin,
+		},
+		"logs in to ECR Public even with a generic store": {
+			uri:    publicURI,
+			config: `{"credsStore":"desktop","credHelpers":{"public.ecr.aws":"osxkeychain"},"auths":{"public.ecr.aws":{}}}`,
+			auth:   auth,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", "public.ecr.aws"}, gomock.Any()).Return(nil)
+			},
+			wanted: CredentialSourceLogin,
+		},
+		"uses the global ecr-login store for ECR Public": {
+			uri:    publicURI,
+			config: `{"credsStore":"ecr-login"}`,
+			wanted: CredentialSourceStore,
+		},
+		"doesn't use the global ecr-login store for other registries": {
+			uri:    privateURI,
+			config: `{"credsStore":"ecr-login"}`,
+			auth:   auth,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", privateURI}, gomock.Any()).Return(nil)
+			},
+			wanted: CredentialSourceLogin,
+		},
+		"uses the credentials of a previous login": {
+			uri:    privateURI,
+			config: `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+			wanted: CredentialSourceAuths,
+		},
+		"logs in to ECR even if a previous login stored credentials": {
+			uri:    ecrURI,
+			config: `{"credsStore":"desktop","credHelpers":{"` + ecrHost + `":"desktop"},"auths":{"` + ecrHost + `":{}}}`,
+			auth:   auth,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", ecrURI}, gomock.Any()).Return(nil)
+			},
+			wanted: CredentialSourceLogin,
+		},
+		"wraps errors from auth": {
+			uri:    ecrURI,
+			config: `{}`,
+			auth: func() (string, string, error) {
+				return "", "", errors.New("some error")
+			},
+			wantedErr: "get auth: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, dockerConfigFile), []byte(tc.config), 0644))
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			if tc.setupMock != nil {
+				tc.setupMock(m)
+			}
+			r := NewCredentialResolver(DockerCmdClient{
+				runner:    m,
+				configDir: dir,
+			})
+
+			got, err := r.Resolve(tc.uri, tc.auth)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// Files and directories written by CollectDebugArtifacts.
+const (
+	DebugLogsFile    = "logs.txt"
+	DebugInspectFile = "inspect.json"
+	DebugProcDir     = "proc"
+	DebugCoresDir    = "cores"
+)
+
+// ContainerCoreDumpDir is the directory that CollectDebugArtifacts copies core dumps from.
+// The container's kernel.core_pattern, or its ulimit and working directory, must point core dumps there.
+const ContainerCoreDumpDir = "/tmp/cores"
+
+// procSnapshots are the files of the /proc filesystem of a running container that are worth keeping after a failure.
+var procSnapshots = []string{
+	"/proc/1/cmdline",
+	"/proc/1/limits",
+	"/proc/1/status",
+	"/proc/loadavg",
+	"/proc/meminfo",
+}
+
+// CollectDebugArtifacts gathers what's needed to investigate the failure of a local container into destDir:
+// the logs of the container, its inspect output, snapshots of /proc if it's still running,
+// and the core dumps under ContainerCoreDumpDir if there are any.
+// Collection is best-effort: every artifact is attempted, and the errors of the mandatory ones are returned together.
+func (c DockerCmdClient) CollectDebugArtifacts(ctx context.Context, containerName, destDir string) error {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("create directory for debug artifacts: %w", err)
+	}
+	var errs []error
+	if err := c.saveContainerLogs(ctx, containerName, filepath.Join(destDir, DebugLogsFile)); err != nil {
+		errs = append(errs, err)
+	}
+	running, err := c.saveContainerInspect(ctx, containerName, filepath.Join(destDir, DebugInspectFile))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if running {
+		if err := c.saveProcSnapshots(ctx, containerName, filepath.Join(destDir, DebugProcDir)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// Core dumps are optional: most containers don't have any.
+	_ = c.runner.RunWithContext(ctx, "docker", []string{"cp", containerName + ":" + ContainerCoreDumpDir, filepath.Join(destDir, DebugCoresDir)})
+	return errors.Join(errs...)
+}
+
+func (c DockerCmdClient) saveContainerLogs(ctx context.Context, container, path string) error {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"logs", "--timestamps", container}, exec.Stdout(buf), exec.Stderr(buf)); err != nil {
+		return fmt.Errorf("get logs of container %s: %w", container, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write logs of container %s: %w", container, err)
+	}
+	return nil
+}
+
+// saveContainerInspect writes the inspect output of the container to path and returns whether the container is running.
+func (c DockerCmdClient) saveContainerInspect(ctx context.Context, container, path string) (bool, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"inspect", container}, exec.Stdout(buf)); err != nil {
+		return false, fmt.Errorf("inspect container %s: %w", container, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("write inspect output of container %s: %w", container, err)
+	}
+	var out []struct {
+		State struct {
+			Running bool
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil || len(out) == 0 {
+		return false, nil
+	}
+	return out[0].State.Running, nil
+}
+
+// saveProcSnapshots copies procSnapshots from the running container to dir.
+// Files that can't be read, for example because the image has no cat, are skipped.
+func (c DockerCmdClient) saveProcSnapshots(ctx context.Context, container, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory for /proc snapshots: %w", err)
+	}
+	for _, file := range procSnapshots {
+		buf := &bytes.Buffer{}
+		if err := c.runner.RunWithContext(ctx, "docker", []string{"exec", container, "cat", file}, exec.Stdout(buf)); err != nil {
+			continue
+		}
+		name := filepath.Join(dir, strings.ReplaceAll(strings.TrimPrefix(file, "/proc/"), "/", "_"))
+		if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("write snapshot of %s: %w", file, err)
+		}
+	}
+	return nil
+}
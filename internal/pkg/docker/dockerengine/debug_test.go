@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_CollectDebugArtifacts(t *testing.T) {
+	t.Run("collects the artifacts of a running container", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		dir := filepath.Join(t.TempDir(), "debug")
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"logs", "--timestamps", "web"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("panic: oops\n")).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"inspect", "web"}, gomock.Any()).
+			Do(mockStdout(`[{"State":{"Running":true}}]`)).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"exec", "web", "cat", "/proc/1/status"}, gomock.Any()).
+			Do(mockStdout("State: S (sleeping)")).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"exec", "web", "cat", "/proc/meminfo"}, gomock.Any()).
+			Return(errors.New("no cat"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"cp", "web:/tmp/cores", filepath.Join(dir, "cores")}).
+			Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", gomock.Any(), gomock.Any()).
+			Return(nil).AnyTimes()
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.CollectDebugArtifacts(context.Background(), "web", dir)
+
+		require.NoError(t, err)
+		logs, err := os.ReadFile(filepath.Join(dir, "logs.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "panic: oops\n", string(logs))
+		inspect, err := os.ReadFile(filepath.Join(dir, "inspect.json"))
+		require.NoError(t, err)
+		require.Equal(t, `[{"State":{"Running":true}}]`, string(inspect))
+		status, err := os.ReadFile(filepath.Join(dir, "proc", "1_status"))
+		require.NoError(t, err)
+		require.Equal(t, "State: S (sleeping)", string(status))
+		require.NoFileExists(t, filepath.Join(dir, "proc", "meminfo"))
+	})
+	t.Run("skips /proc for a stopped container and returns the errors together", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		dir := t.TempDir()
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"logs", "--timestamps", "web"}, gomock.Any(), gomock.Any()).
+			Return(errors.New("some error"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"inspect", "web"}, gomock.Any()).
+			Do(mockStdout(`[{"State":{"Running":false}}]`)).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"cp", "web:/tmp/cores", filepath.Join(dir, "cores")}).
+			Return(errors.New("no such directory"))
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.CollectDebugArtifacts(context.Background(), "web", dir)
+
+		require.EqualError(t, err, "get logs of container web: some error")
+		require.FileExists(t, filepath.Join(dir, "inspect.json"))
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+)
+
+// ImageDiffReport is a structured comparison between a base image and a target image.
+type ImageDiffReport struct {
+	Base   string // Reference of the base image, for example the currently deployed digest.
+	Target string // Reference of the image that is compared against the base.
+
+	BaseSize   int64 // Size of the base image in bytes. Zero if the image is only available remotely.
+	TargetSize int64 // Size of the target image in bytes. Zero if the image is only available remotely.
+
+	SharedLayers  []string // Layers present in both images.
+	AddedLayers   []string // Layers only present in the target image.
+	RemovedLayers []string // Layers only present in the base image.
+
+	Env    MapDiff // Differences between the environment variables of the images.
+	Labels MapDiff // Differences between the labels of the images.
+}
+
+// MapDiff holds the differences between two sets of key-value pairs.
+type MapDiff struct {
+	Added   map[string]string
+	Removed map[string]string
+	Changed map[string]ValueChange
+}
+
+// ValueChange holds the old and new value of a modified key.
+type ValueChange struct {
+	Old string
+	New string
+}
+
+// IsEmpty returns true if there are no differences.
+func (d MapDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// SizeDelta returns the difference in bytes between the target and the base image.
+func (d *ImageDiffReport) SizeDelta() int64 {
+	return d.TargetSize - d.BaseSize
+}
+
+// ImageDiff compares the layers, sizes, environment variables and labels of two image references.
+// Each reference is inspected from the local image store first, then from its registry.
+func (c DockerCmdClient) ImageDiff(ctx context.Context, base, target string) (*ImageDiffReport, error) {
+	baseImg, err := c.inspectImage(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("inspect base image %s: %w", base, err)
+	}
+	targetImg, err := c.inspectImage(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("inspect target image %s: %w", target, err)
+	}
+	diff := &ImageDiffReport{
+		Base:       base,
+		Target:     target,
+		BaseSize:   baseImg.Size,
+		TargetSize: targetImg.Size,
+		Env:        diffMaps(baseImg.env(), targetImg.env()),
+		Labels:     diffMaps(baseImg.Config.Labels, targetImg.Config.Labels),
+	}
+	diff.SharedLayers, diff.AddedLayers, diff.RemovedLayers = diffLayers(baseImg.layers(), targetImg.layers())
+	return diff, nil
+}
+
+func diffLayers(base, target []string) (shared, added, removed []string) {
+	inBase := make(map[string]bool, len(base))
+	for _, layer := range base {
+		inBase[layer] = true
+	}
+	inTarget := make(map[string]bool, len(target))
+	for _, layer := range target {
+		inTarget[layer] = true
+		if inBase[layer] {
+			shared = append(shared, layer)
+		} else {
+			added = append(added, layer)
+		}
+	}
+	for _, layer := range base {
+		if !inTarget[layer] {
+			removed = append(removed, layer)
+		}
+	}
+	return shared, added, removed
+}
+
+func diffMaps(base, target map[string]string) MapDiff {
+	diff := MapDiff{
+		Added:   make(map[string]string),
+		Removed: make(map[string]string),
+		Changed: make(map[string]ValueChange),
+	}
+	for k, newVal := range target {
+		oldVal, ok := base[k]
+		switch {
+		case !ok:
+			diff.Added[k] = newVal
+		case oldVal != newVal:
+			diff.Changed[k] = ValueChange{Old: oldVal, New: newVal}
+		}
+	}
+	for k, oldVal := range base {
+		if _, ok := target[k]; !ok {
+			diff.Removed[k] = oldVal
+		}
+	}
+	return diff
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_ImageDiff(t *testing.T) {
+	ctx := context.Background()
+	const (
+		baseInspect = `[{"Id":"sha256:aaa","Size":100,"Config":{"Env":["PATH=/bin","MODE=dev","OLD=1"],"Labels":{"version":"1"}},"RootFS":{"Layers":["sha256:l1","sha256:l2"]}}]`
+		targetImage = `{"os":"linux","architecture":"amd64","config":{"Env":["PATH=/bin","MODE=prod","NEW=1"],"Labels":{"version":"2","team":"a"}},"rootfs":{"type":"layers","diff_ids":["sha256:l1","sha256:l3"]}}`
+	)
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wantedDiff *ImageDiffReport
+		wantedErr  string
+	}{
+		"compares a local image against a remote image": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", "base"}, gomock.Any()).
+					Do(mockStdout(baseInspect)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", "target"}, gomock.Any()).
+					Return(errors.New("no such image"))
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "imagetools", "inspect", "--format", "{{json .Image}}", "target"}, gomock.Any()).
+					Do(mockStdout(targetImage)).Return(nil)
+			},
+			wantedDiff: &ImageDiffReport{
+				Base:          "base",
+				Target:        "target",
+				BaseSize:      100,
+				SharedLayers:  []string{"sha256:l1"},
+				AddedLayers:   []string{"sha256:l3"},
+				RemovedLayers: []string{"sha256:l2"},
+				Env: MapDiff{
+					Added:   map[string]string{"NEW": "1"},
+					Removed: map[string]string{"OLD": "1"},
+					Changed: map[string]ValueChange{"MODE": {Old: "dev", New: "prod"}},
+				},
+				Labels: MapDiff{
+					Added:   map[string]string{"team": "a"},
+					Removed: map[string]string{},
+					Changed: map[string]ValueChange{"version": {Old: "1", New: "2"}},
+				},
+			},
+		},
+		"picks linux/amd64 from a multi-platform image": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", "base"}, gomock.Any()).
+					Do(mockStdout(baseInspect)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", "target"}, gomock.Any()).
+					Return(errors.New("no such image"))
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "imagetools", "inspect", "--format", "{{json .Image}}", "target"}, gomock.Any()).
+					Do(mockStdout(`{"linux/arm64":{"os":"linux","rootfs":{"diff_ids":["sha256:arm"]}},"linux/amd64":{"os":"linux","rootfs":{"diff_ids":["sha256:l1","sha256:l2"]}}}`)).Return(nil)
+			},
+			wantedDiff: &ImageDiffReport{
+				Base:         "base",
+				Target:       "target",
+				BaseSize:     100,
+				SharedLayers: []string{"sha256:l1", "sha256:l2"},
+				Env: MapDiff{
+					Added:   map[string]string{},
+					Removed: map[string]string{"PATH": "/bin", "MODE": "dev", "OLD": "1"},
+					Changed: map[string]ValueChange{},
+				},
+				Labels: MapDiff{
+					Added:   map[string]string{},
+					Removed: map[string]string{"version": "1"},
+					Changed: map[string]ValueChange{},
+				},
+			},
+		},
+		"returns a wrapped error if an image cannot be inspected": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", "base"}, gomock.Any()).
+					Return(errors.New("no such image"))
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "imagetools", "inspect", "--format", "{{json .Image}}", "base"}, gomock.Any()).
+					Return(errors.New("not found"))
+			},
+			wantedErr: "inspect base image base: docker buildx imagetools inspect base: not found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.ImageDiff(ctx, "base", "target")
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedDiff, got)
+			require.Equal(t, -int64(100), got.SizeDelta())
+		})
+	}
+}
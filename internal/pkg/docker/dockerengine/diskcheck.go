@@ -0,0 +1,121 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+	"github.com/dustin/go-humanize"
+)
+
+// linuxDataRoot is the directory where a docker daemon running on the Linux host stores images and build cache.
+const linuxDataRoot = "/var/lib/docker"
+
+// DiskSpaceCheck configures the free disk space check that runs before a build.
+type DiskSpaceCheck struct {
+	MinFree  uint64 // Required. Minimum free space in bytes to build.
+	WarnOnly bool   // Optional. Warns instead of failing the build if there is less free space than MinFree.
+}
+
+// ErrLowDiskSpace means there is not enough free disk space to build an image.
+type ErrLowDiskSpace struct {
+	Path        string // Path on the filesystem that docker stores its data on.
+	Free        uint64
+	MinFree     uint64
+	Reclaimable uint64 // Space used by docker that can be reclaimed by pruning, if known.
+}
+
+func (e *ErrLowDiskSpace) Error() string {
+	return fmt.Sprintf("only %s of disk space free on %s, less than the %s required to build",
+		humanize.IBytes(e.Free), e.Path, humanize.IBytes(e.MinFree))
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrLowDiskSpace) RecommendActions() string {
+	if e.Reclaimable == 0 {
+		return fmt.Sprintf("Free up disk space on %s.", e.Path)
+	}
+	return fmt.Sprintf("Run `docker system prune` to reclaim up to %s used by unused images, containers and build cache, or free up disk space on %s.",
+		humanize.IBytes(e.Reclaimable), e.Path)
+}
+
+// checkDiskSpace returns ErrLowDiskSpace, or warns if the check is WarnOnly, when there is less free space than required to build.
+// The check is best-effort: if the free space can't be determined, the build is left to report the problem.
+func (c DockerCmdClient) checkDiskSpace(ctx context.Context, check *DiskSpaceCheck) error {
+	if check == nil || check.MinFree == 0 {
+		return nil
+	}
+	path := c.dataPath()
+	free, err := freeDiskSpace(path)
+	if err != nil || free >= check.MinFree {
+		return nil
+	}
+	lowErr := &ErrLowDiskSpace{
+		Path:    path,
+		Free:    free,
+		MinFree: check.MinFree,
+	}
+	if reclaimable, err := c.reclaimableSpace(ctx); err == nil {
+		lowErr.Reclaimable = reclaimable
+	}
+	if check.WarnOnly {
+		log.Warningf("%s. %s\n", lowErr.Error(), lowErr.RecommendActions())
+		return nil
+	}
+	return lowErr
+}
+
+// dataPath returns a path on the filesystem where docker stores its data: the data root of a daemon running on the host,
+// or else the home directory where Docker Desktop keeps the disk of its virtual machine.
+func (c DockerCmdClient) dataPath() string {
+	if _, err := os.Stat(linuxDataRoot); err == nil {
+		return linuxDataRoot
+	}
+	if c.homePath != "" {
+		return c.homePath
+	}
+	return os.TempDir()
+}
+
+// reclaimableSpace returns the disk space in bytes used by docker that can be reclaimed with `docker system prune`.
+func (c DockerCmdClient) reclaimableSpace(ctx context.Context) (uint64, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"system", "df", "--format", "{{json .}}"}, exec.Stdout(buf)); err != nil {
+		return 0, fmt.Errorf("get docker disk usage: %w", err)
+	}
+	var total uint64
+	// Each type of object is printed as a JSON object on its own line,
+	// for example {"Reclaimable":"1.2GB (50%)","Size":"2.4GB","Type":"Images"}.
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var usage struct {
+			Reclaimable string `json:"Reclaimable"`
+		}
+		if err := json.Unmarshal([]byte(line), &usage); err != nil {
+			return 0, fmt.Errorf("unmarshal docker disk usage %q: %w", line, err)
+		}
+		size, _, _ := strings.Cut(usage.Reclaimable, " ")
+		bytes, err := humanize.ParseBytes(size)
+		if err != nil {
+			return 0, fmt.Errorf("parse reclaimable size %q: %w", usage.Reclaimable, err)
+		}
+		total += bytes
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read docker disk usage: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_checkDiskSpace(t *testing.T) {
+	ctx := context.Background()
+	dfArgs := []string{"system", "df", "--format", "{{json .}}"}
+	dfOutput := `{"Active":"2","Reclaimable":"1.5GB (62%)","Size":"2.4GB","TotalCount":"5","Type":"Images"}
+{"Active":"0","Reclaimable":"0B","Size":"0B","TotalCount":"0","Type":"Containers"}
+{"Active":"0","Reclaimable":"500MB","Size":"500MB","TotalCount":"12","Type":"Build Cache"}
+`
+
+	tests := map[string]struct {
+		check      *DiskSpaceCheck
+		setupMocks func(m *MockCmd)
+
+		wantedReclaimable uint64
+		wantedErr         bool
+	}{
+		"skips the check if it's not configured": {
+			setupMocks: func(m *MockCmd) {},
+		},
+		"passes if there is enough free space": {
+			check:      &DiskSpaceCheck{MinFree: 1},
+			setupMocks: func(m *MockCmd) {},
+		},
+		"returns ErrLowDiskSpace with the reclaimable space": {
+			check: &DiskSpaceCheck{MinFree: 1 << 62},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", dfArgs, gomock.Any()).Do(mockStdout(dfOutput)).Return(nil)
+			},
+			wantedReclaimable: 2_000_000_000,
+			wantedErr:         true,
+		},
+		"returns ErrLowDiskSpace even if the disk usage is unknown": {
+			check: &DiskSpaceCheck{MinFree: 1 << 62},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", dfArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: true,
+		},
+		"only warns if the check is WarnOnly": {
+			check: &DiskSpaceCheck{MinFree: 1 << 62, WarnOnly: true},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", dfArgs, gomock.Any()).Do(mockStdout(dfOutput)).Return(nil)
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner:   m,
+				homePath: t.TempDir(),
+			}
+
+			err := s.checkDiskSpace(ctx, tc.check)
+
+			if !tc.wantedErr {
+				require.NoError(t, err)
+				return
+			}
+			var lowErr *ErrLowDiskSpace
+			require.True(t, errors.As(err, &lowErr))
+			require.Equal(t, uint64(1<<62), lowErr.MinFree)
+			require.Equal(t, tc.wantedReclaimable, lowErr.Reclaimable)
+		})
+	}
+}
+
+func TestErrLowDiskSpace_RecommendActions(t *testing.T) {
+	err := &ErrLowDiskSpace{
+		Path:        "/var/lib/docker",
+		Free:        1 << 30,
+		MinFree:     10 << 30,
+		Reclaimable: 4 << 30,
+	}
+
+	require.EqualError(t, err, "only 1.0 GiB of disk space free on /var/lib/docker, less than the 10 GiB required to build")
+	require.Equal(t, "Run `docker system prune` to reclaim up to 4.0 GiB used by unused images, containers and build cache, or free up disk space on /var/lib/docker.", err.RecommendActions())
+}
@@ -8,15 +8,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	osexec "os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
 )
 
 // Cmd is the interface implemented by external commands.
@@ -38,37 +45,119 @@ const (
 
 const (
 	credStoreECRLogin = "ecr-login" // set on `credStore` attribute in docker configuration file
+	stdinPath         = "-"         // Path used by docker commands to read from stdin.
 )
 
+// ECRPublicRegistry is the registry of ECR Public repositories.
+const ECRPublicRegistry = "public.ecr.aws"
+
+// IsECRPublicURI returns true if uri is a repository or an image of ECR Public, like "public.ecr.aws/nginx/nginx".
+func IsECRPublicURI(uri string) bool {
+	registry, _, _ := strings.Cut(uri, "/")
+	return registry == ECRPublicRegistry
+}
+
 // DockerCmdClient represents the docker client to interact with the server via external commands.
 type DockerCmdClient struct {
 	runner Cmd
 	// Override in unit tests.
 	buf       *bytes.Buffer
 	homePath  string
+	configDir string // Set if the docker commands run with an isolated config directory.
 	lookupEnv func(string) (string, bool)
+
+	// Set if the build context is sent to a remote daemon by the legacy builder, which can't sync it incrementally.
+	compressContext bool
+	// Set if the references of images must be validated before they're pushed.
+	policy ImagePolicy
+	// Maximum number of tags pushed at a time. Defaults to all of them.
+	pushConcurrency int
+	// Set if pushes that fail with transient errors must be retried.
+	pushRetry *RetryPolicy
+	// Set if tags that already point to the local image in the registry mustn't be pushed again.
+	skipUnchanged bool
+	// Set if pushed images must be signed.
+	signing *Signing
+	// Set if images must have a trusted signature to be pulled or run.
+	trustPolicy *TrustPolicy
+	// Set if the progress of the layers of pushed images must be reported.
+	pushProgress PushProgressFunc
+	// Set if the local tags of images must be removed once they're pushed.
+	removeAfterPush bool
+	// Registry mirror that Docker Hub images are pulled through, if the daemon isn't configured with it already.
+	mirror string
+	// Piped to the commands that read their standard input.
+	stdin io.Reader
 }
 
 // New returns CmdClient to make requests against the Docker daemon via external commands.
+// Commands run with a context stop with all the processes they spawn when the context is done.
 func New(cmd Cmd) DockerCmdClient {
 	return DockerCmdClient{
-		runner:    cmd,
+		runner:    processGroupRunner{Cmd: cmd},
 		homePath:  userHomeDirectory(),
 		lookupEnv: os.LookupEnv,
 	}
 }
 
+// BuildOutput is where the image is exported at the end of a build.
+type BuildOutput int
+
+// Outputs of a build.
+const (
+	// OutputDefault keeps the image in docker with the default builder, and loads it into docker with other builders.
+	OutputDefault BuildOutput = iota
+	// LoadToDaemon loads the image into docker. Multi-platform images can't be loaded.
+	LoadToDaemon
+	// PushDirect pushes the image to URI from the builder without loading it into docker.
+	// Use it for multi-platform images or builders that don't use the docker driver. Requires buildx.
+	PushDirect
+)
+
 // BuildArguments holds the arguments that can be passed while building a container.
 type BuildArguments struct {
-	URI        string            // Required. Location of ECR Repo. Used to generate image name in conjunction with tag.
-	Tags       []string          // Required. List of tags to apply to the image.
-	Dockerfile string            // Required. Dockerfile to pass to `docker build` via --file flag.
-	Context    string            // Optional. Build context directory to pass to `docker build`.
-	Target     string            // Optional. The target build stage to pass to `docker build`.
-	CacheFrom  []string          // Optional. Images to consider as cache sources to pass to `docker build`
-	Platform   string            // Optional. OS/Arch to pass to `docker build`.
-	Args       map[string]string // Optional. Build args to pass via `--build-arg` flags. Equivalent to ARG directives in dockerfile.
-	Labels     map[string]string // Required. Set metadata for an image.
+	URI              string            // Required. Location of ECR Repo. Used to generate image name in conjunction with tag.
+	Tags             []string          // Required. List of tags to apply to the image.
+	Dockerfile       string            // Required. Dockerfile to pass to `docker build` via --file flag.
+	Context          string            // Optional. Build context directory to pass to `docker build`.
+	ExtraContexts    map[string]string // Optional. Additional named build contexts keyed by name, for example "sharedlib": "../lib". Requires buildx.
+	Target           string            // Optional. The target build stage to pass to `docker build`.
+	CacheFrom        []string          // Optional. Images to consider as cache sources to pass to `docker build`
+	PullThroughCache *PullThroughCache // Optional. Pulls the CacheFrom images of upstream registries through the ECR pull through cache of the account.
+	InlineCache      bool              // Optional. Embeds the build cache metadata in the image, so that other machines can use the pushed image in CacheFrom.
+	Platform         string            // Optional. OS/Arch to pass to `docker build`.
+	InstallQEMU      bool              // Optional. Installs the QEMU emulator for Platform if the daemon runs on another architecture and has none.
+	Builder          string            // Optional. Name of the buildx builder instance to build with.
+	Output           BuildOutput       // Optional. Where to export the image. Defaults to OutputDefault.
+	Reproducible     bool              // Optional. Builds identical images from identical inputs, timestamped with SOURCE_DATE_EPOCH from the environment or 0. Requires buildx.
+	Args             map[string]string // Optional. Build args to pass via `--build-arg` flags. Equivalent to ARG directives in dockerfile.
+	ArgFiles         []string          // Optional. Dotenv files of build args. Later files override earlier ones, and Args override all of them.
+	ArgsFromEnv      []string          // Optional. Names of host environment variables to pass as build args. Docker reads the values from the environment.
+	Labels           map[string]string // Required. Set metadata for an image.
+	Annotations      map[string]string // Optional. OCI annotations of the image, for example "org.opencontainers.image.revision": "<commit>". Requires buildx.
+	Squash           bool              // Optional. Squash newly built layers into a single layer. Requires an experimental daemon.
+	Memory           string            // Optional. Memory limit for the build containers, for example "2g".
+	CPUPeriod        int64             // Optional. CPU CFS period in microseconds for the build containers.
+	CPUQuota         int64             // Optional. CPU CFS quota in microseconds for the build containers.
+	Ulimits          map[string]string // Optional. Ulimits for the build containers keyed by type, for example "nofile": "1024:2048".
+	CgroupParent     string            // Optional. Parent cgroup for the build containers.
+	MaxContextSize   int64             // Optional. Maximum size in bytes of the build context, after applying .dockerignore, to send to the daemon.
+	SkipContextSize  bool              // Optional. Skips walking the build context to warn about large contexts before building. Ignored if MaxContextSize is set.
+	DiskSpace        *DiskSpaceCheck   // Optional. Checks that there is enough free disk space before building.
+	Lint             *LintOptions      // Optional. Lints the Dockerfile before building, with hadolint if it is installed.
+	Retry            *RetryPolicy      // Optional. Retries the build if it fails with a transient daemon or network error. Ignored if the build reads from stdin.
+	SmokeTest        *SmokeTestOptions // Optional. Runs the built image before pushing it, to fail early if it can't start. Ignored with PushDirect.
+	ExtraFlags       []string          // Optional. Flags appended verbatim to `docker build`, for example []string{"--network", "host"}, for options that aren't modeled above.
+
+	// Optional. BuildKit secrets keyed by id, mounted with `RUN --mount=type=secret,id=<id>`.
+	// Values are paths to files on the host, or "ssm://<parameter name>" and "secretsmanager://<secret name or ARN>"
+	// references that are written to temporary files for the duration of the build.
+	Secrets      map[string]string
+	SecretStores *BuildSecretStores // Optional. Clients to resolve the Secrets that reference SSM or Secrets Manager.
+
+	// Optional. Builds the source code in Context with Cloud Native Buildpacks instead of the Dockerfile,
+	// for services without a Dockerfile. Requires the pack CLI.
+	Buildpacks *BuildpacksOptions
 }
 
 // RunOptions holds the options for running a Docker container.
@@ -77,9 +166,27 @@ type RunOptions struct {
 	Secrets          map[string]string // Optional. Secrets to pass to the container as environment variables.
 	EnvVars          map[string]string // Optional. Environment variables to pass to the container.
 	ContainerName    string            // Optional. The name for the container.
+	Platform         string            // Optional. OS/Arch of the image to run, for example "linux/amd64" to run an amd64 image under emulation on an arm64 host.
 	ContainerPorts   map[string]string // Optional. Contains host and container ports.
 	Command          []string          // Optional. The command to run in the container.
+	Entrypoint       []string          // Optional. Overrides the entrypoint of the image, for example []string{"/bin/sh", "-c"}.
+	WorkDir          string            // Optional. Overrides the working directory of the image.
+	HealthCheck      *HealthCheck      // Optional. Overrides the health check of the image.
+	Init             bool              // Optional. Runs an init process as PID 1 that forwards signals and reaps zombie processes, like initProcessEnabled in ECS.
+	StopSignal       string            // Optional. Signal sent to stop the container, for example "SIGINT". Defaults to the STOPSIGNAL of the image, or SIGTERM.
+	StopTimeout      time.Duration     // Optional. Time to wait for the container to exit after the stop signal before killing it, like stopTimeout in ECS.
+	RestartPolicy    string            // Optional. Restarts the container when it exits: "no", "on-failure[:max-retries]", "always" or "unless-stopped". Defaults to "no".
 	ContainerNetwork string            // Optional. Network mode for the container.
+	FakeTime         *FakeTime         // Optional. Shifts the clock observed by the processes in the container.
+	CPUs             float64           // Optional. Number of CPUs the container can use, for example 0.25 for a task with 256 CPU units.
+	Memory           int               // Optional. Memory limit of the container in MiB. The container is killed if it exceeds it, like in ECS.
+	CpusetCpus       string            // Optional. CPUs the container can run on, for example "0-3" or "1,3".
+	CpusetMems       string            // Optional. NUMA memory nodes the container can allocate from, for example "0". Only effective on NUMA systems.
+	Mounts           map[string]string // Optional. Host directories to bind-mount, mapped to their path in the container.
+	User             string            // Optional. User and group the processes of the container run as, for example "1000:1000" to match the user of the task in ECS.
+	UsernsHost       bool              // Optional. Runs the container in the user namespace of the host even if the daemon remaps users.
+	GroupAdd         []string          // Optional. Supplementary groups the user of the container joins, by name or ID, for example "docker" to use a mounted Docker socket.
+	Tmpfs            map[string]string // Optional. In-memory filesystems keyed by path in the container, mapped to their mount options, for example "/tmp": "rw,size=64m".
 }
 
 // GenerateDockerBuildArgs returns command line arguments to be passed to the Docker build command based on the provided BuildArguments.
@@ -91,11 +198,11 @@ func (in *BuildArguments) GenerateDockerBuildArgs(c DockerCmdClient) ([]string,
 			uri: in.URI,
 		}
 	}
-	dfDir := in.Context
-	// Context wasn't specified use the Dockerfile's directory as context.
-	if dfDir == "" {
-		dfDir = filepath.Dir(in.Dockerfile)
+	if in.readsStdin() && c.stdin == nil {
+		return nil, errMissingStdin
 	}
+	// If context wasn't specified, use the Dockerfile's directory as context.
+	dfDir := in.ContextDir()
 
 	args := []string{"build"}
 
@@ -106,9 +213,23 @@ func (in *BuildArguments) GenerateDockerBuildArgs(c DockerCmdClient) ([]string,
 
 	// Add cache from options.
 	for _, imageFrom := range in.CacheFrom {
+		imageFrom, err := in.PullThroughCache.rewrite(imageFrom)
+		if err != nil {
+			return nil, err
+		}
 		args = append(args, "--cache-from", imageFrom)
 	}
 
+	// Add inline cache option.
+	// Buildx builders export the cache with --cache-to, while the classic builder reads a build arg.
+	if in.InlineCache {
+		if in.Builder != "" {
+			args = append(args, "--cache-to", "type=inline")
+		} else {
+			args = append(args, "--build-arg", "BUILDKIT_INLINE_CACHE=1")
+		}
+	}
+
 	// Add target option.
 	if in.Target != "" {
 		args = append(args, "--target", in.Target)
@@ -119,20 +240,117 @@ func (in *BuildArguments) GenerateDockerBuildArgs(c DockerCmdClient) ([]string,
 		args = append(args, "--platform", in.Platform)
 	}
 
+	// Add builder option.
+	if in.Builder != "" {
+		args = append(args, "--builder", in.Builder)
+	}
+
+	// Add output option.
+	// Builders that don't use the docker driver keep the result in their own cache unless it's loaded into docker or pushed.
+	// Reproducible builds rewrite the timestamps of the layers with the exporter options, which --push and --load can't set.
+	switch {
+	case in.Output == PushDirect && in.URI == "":
+		return nil, &errInvalidBuildOutput{reason: "the image can't be pushed without a repository URI"}
+	case in.Output == PushDirect && in.Reproducible:
+		args = append(args, "--output", "type=image,push=true,rewrite-timestamp=true")
+	case in.Output == PushDirect:
+		args = append(args, "--push")
+	case in.Output == LoadToDaemon && strings.Contains(in.Platform, ","):
+		return nil, &errInvalidBuildOutput{reason: fmt.Sprintf("images for multiple platforms %s can't be loaded into docker", in.Platform)}
+	case in.Reproducible:
+		args = append(args, "--output", "type=docker,rewrite-timestamp=true")
+	case in.Output == LoadToDaemon, in.Builder != "":
+		args = append(args, "--load")
+	}
+	if in.Reproducible {
+		// Provenance attestations record when the build ran, so they change the digest of every build.
+		args = append(args, "--provenance=false")
+	}
+
+	// Add named build contexts.
+	// Collect the keys in a slice to sort for test stability.
+	var contextNames []string
+	for name := range in.ExtraContexts {
+		contextNames = append(contextNames, name)
+	}
+	sort.Strings(contextNames)
+	for _, name := range contextNames {
+		args = append(args, "--build-context", fmt.Sprintf("%s=%s", name, in.ExtraContexts[name]))
+	}
+
+	// Add build secrets.
+	args = append(args, in.secretArgs()...)
+
+	// Add squash option.
+	if in.Squash {
+		args = append(args, "--squash")
+	}
+
+	// Add resource limit options.
+	if in.Memory != "" {
+		args = append(args, "--memory", in.Memory)
+	}
+	if in.CPUPeriod != 0 {
+		args = append(args, "--cpu-period", strconv.FormatInt(in.CPUPeriod, 10))
+	}
+	if in.CPUQuota != 0 {
+		args = append(args, "--cpu-quota", strconv.FormatInt(in.CPUQuota, 10))
+	}
+	// Collect the keys in a slice to sort for test stability.
+	var ulimitKeys []string
+	for k := range in.Ulimits {
+		ulimitKeys = append(ulimitKeys, k)
+	}
+	sort.Strings(ulimitKeys)
+	for _, k := range ulimitKeys {
+		args = append(args, "--ulimit", fmt.Sprintf("%s=%s", k, in.Ulimits[k]))
+	}
+
+	// Add cgroup parent option.
+	if in.CgroupParent != "" {
+		args = append(args, "--cgroup-parent", in.CgroupParent)
+	}
+
+	// Compress the context sent to a remote daemon by the legacy builder.
+	if c.compressContext && in.Builder == "" {
+		args = append(args, "--compress")
+	}
+
 	// Plain display if we're in a CI environment.
 	if ci, _ := c.lookupEnv("CI"); ci == "true" {
 		args = append(args, "--progress", "plain")
 	}
 
-	// Add the "args:" override section from manifest to the docker build call.
+	// Add the "args:" override section from manifest, merged with the args files, to the docker build call.
+	buildArgs, err := in.buildArgs()
+	if err != nil {
+		return nil, err
+	}
+	if in.Reproducible {
+		buildArgs = withSourceDateEpoch(buildArgs, c.sourceDateEpoch())
+	}
 	// Collect the keys in a slice to sort for test stability.
 	var keys []string
-	for k := range in.Args {
+	for k := range buildArgs {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, in.Args[k]))
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, buildArgs[k]))
+	}
+
+	// Pass through the host environment variables that are set and not overridden by "args:".
+	// Only the key is passed so that the value is read by docker from its environment and stays out of the arguments.
+	envKeys := append([]string(nil), in.ArgsFromEnv...)
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		if _, ok := buildArgs[k]; ok {
+			continue
+		}
+		if _, ok := c.lookupEnv(k); !ok {
+			continue
+		}
+		args = append(args, "--build-arg", k)
 	}
 
 	// Add Labels to docker build call.
@@ -146,32 +364,144 @@ func (in *BuildArguments) GenerateDockerBuildArgs(c DockerCmdClient) ([]string,
 		args = append(args, "--label", fmt.Sprintf("%s=%s", k, in.Labels[k]))
 	}
 
+	// Add OCI annotations, which are stored in the image manifest rather than in the image config like labels.
+	// Collect the keys in a slice to sort for test stability.
+	var annotationKeys []string
+	for k := range in.Annotations {
+		annotationKeys = append(annotationKeys, k)
+	}
+	sort.Strings(annotationKeys)
+	for _, k := range annotationKeys {
+		args = append(args, "--annotation", fmt.Sprintf("%s=%s", k, in.Annotations[k]))
+	}
+
+	// Add the flags that copilot doesn't model.
+	if err := validateExtraFlags(in.ExtraFlags); err != nil {
+		return nil, err
+	}
+	args = append(args, in.ExtraFlags...)
+
 	args = append(args, dfDir, "-f", in.Dockerfile)
 	return args, nil
 }
 
+// reservedBuildFlags are the flags of `docker build` that are set from BuildArguments and can't be passed as extra flags.
+var reservedBuildFlags = map[string]bool{
+	"-t":     true,
+	"--tag":  true,
+	"-f":     true,
+	"--file": true,
+}
+
+// validateExtraFlags makes sure that the extra flags start with a flag and don't override the flags set from BuildArguments.
+func validateExtraFlags(flags []string) error {
+	if len(flags) > 0 && !strings.HasPrefix(flags[0], "-") {
+		return &errInvalidExtraFlag{flag: flags[0], reason: "it must start with a flag"}
+	}
+	for _, flag := range flags {
+		name, _, _ := strings.Cut(flag, "=")
+		if reservedBuildFlags[name] {
+			return &errInvalidExtraFlag{flag: flag, reason: "it is set from the image's tags and Dockerfile"}
+		}
+	}
+	return nil
+}
+
 type dockerConfig struct {
-	CredsStore  string            `json:"credsStore,omitempty"`
-	CredHelpers map[string]string `json:"credHelpers,omitempty"`
+	CredsStore  string                `json:"credsStore,omitempty"`
+	CredHelpers map[string]string     `json:"credHelpers,omitempty"`
+	Auths       map[string]dockerAuth `json:"auths,omitempty"`
 }
 
 // Build will run a `docker build` command for the given ecr repo URI and build arguments.
 func (c DockerCmdClient) Build(ctx context.Context, in *BuildArguments, w io.Writer) error {
+	if in.Output == PushDirect {
+		if err := c.checkPolicy(in.URI, in.Tags...); err != nil {
+			return err
+		}
+	}
+	if in.Buildpacks != nil {
+		_, err := c.BuildWithBuildpacks(ctx, in, w)
+		return err
+	}
+	in, cleanup, err := in.resolveSecrets()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			log.Warningf("Failed to remove the build secrets from the temporary directory: %v\n", err)
+		}
+	}()
 	args, err := in.GenerateDockerBuildArgs(c)
 	if err != nil {
 		return fmt.Errorf("generate docker build args: %w", err)
 	}
-	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(w), exec.Stderr(w)); err != nil {
+	if err := in.validateTarget(); err != nil {
+		return err
+	}
+	if err := c.lint(ctx, in); err != nil {
+		return err
+	}
+	if err := c.checkEmulation(ctx, in); err != nil {
+		return err
+	}
+	if err := in.checkContextSize(); err != nil {
+		return err
+	}
+	if err := c.checkDiskSpace(ctx, in.DiskSpace); err != nil {
+		return err
+	}
+	if in.Squash {
+		if err := c.checkSquashSupported(ctx); err != nil {
+			return err
+		}
+	}
+	run := func(tail *tailBuffer) error {
+		out := io.MultiWriter(w, tail)
+		if err := c.runner.RunWithContext(ctx, "docker", args, c.withStdin(exec.Stdout(out), exec.Stderr(out))...); err != nil {
+			return classifyBuildError(err, tail.String())
+		}
+		return nil
+	}
+	if in.Retry != nil && !in.readsStdin() {
+		// The content of stdin can't be sent twice, so builds that read from it are never retried.
+		err = in.Retry.withRetry(ctx, in.displayName(), run)
+	} else {
+		err = run(&tailBuffer{size: buildOutputTailSize})
+	}
+	if err != nil {
 		return fmt.Errorf("building image: %w", err)
 	}
 	return nil
 }
 
+// squashSupportArgs are the arguments of the `docker version` command printing whether the daemon runs with experimental features.
+var squashSupportArgs = []string{"version", "-f", "{{json .Server.Experimental}}"}
+
+// checkSquashSupported returns ErrSquashNotSupported if the daemon doesn't run with experimental features enabled.
+func (c DockerCmdClient) checkSquashSupported(ctx context.Context) error {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", squashSupportArgs, exec.Stdout(buf)); err != nil {
+		return fmt.Errorf("check if docker daemon supports squash: %w", err)
+	}
+	if strings.TrimSpace(buf.String()) != "true" {
+		return ErrSquashNotSupported
+	}
+	return nil
+}
+
 // Login will run a `docker login` command against the Service repository URI with the input uri and auth data.
+// If password is empty and the client has a stdin, the password is read from it instead, for example from a password file,
+// so that it's never held in memory as a string.
+// ECR Public credentials are stored for the whole registry, so that they're used to both push to the repository and pull other public images.
 func (c DockerCmdClient) Login(uri, username, password string) error {
+	if password != "" || c.stdin == nil {
+		c.stdin = strings.NewReader(password)
+	}
 	err := c.runner.Run("docker",
-		[]string{"login", "-u", username, "--password-stdin", uri},
-		exec.Stdin(strings.NewReader(password)))
+		[]string{"login", "-u", username, "--password-stdin", loginServer(uri)},
+		c.withStdin()...)
 
 	if err != nil {
 		return fmt.Errorf("authenticate to ECR: %w", err)
@@ -180,36 +510,212 @@ func (c DockerCmdClient) Login(uri, username, password string) error {
 	return nil
 }
 
+// Logout runs a `docker logout` command to remove the credentials stored by Login for the uri,
+// so that temporary credentials aren't left behind on shared build hosts.
+func (c DockerCmdClient) Logout(uri string) error {
+	if err := c.runner.Run("docker", []string{"logout", loginServer(uri)}, exec.Stdout(io.Discard)); err != nil {
+		return fmt.Errorf("log out of %s: %w", uri, err)
+	}
+	return nil
+}
+
+// loginServer returns the server that the credentials of the uri are stored for.
+func loginServer(uri string) string {
+	if IsECRPublicURI(uri) {
+		return ECRPublicRegistry
+	}
+	return uri
+}
+
 // Push pushes the images with the specified tags and ecr repository URI, and returns the image digest on success.
+// If the client signs images, the digest is signed once it's pushed.
 func (c DockerCmdClient) Push(ctx context.Context, uri string, w io.Writer, tags ...string) (digest string, err error) {
-	images := []string{}
+	img, err := c.PushSigned(ctx, uri, w, tags...)
+	if err != nil {
+		return "", err
+	}
+	return img.Digest, nil
+}
+
+func (c DockerCmdClient) push(ctx context.Context, uri string, w io.Writer, tags ...string) (digest string, err error) {
+	if err := c.checkPolicy(uri, tags...); err != nil {
+		return "", err
+	}
+	digest, err = c.pushTags(ctx, uri, w, tags)
+	if err != nil {
+		return "", err
+	}
+	if digest != "" {
+		return digest, nil
+	}
+	// The output of quiet pushes doesn't include the digest: read it from the repo digests of the image instead.
+	// The image can have repo digests from other registries, so pick the one of the repository.
+	buf := new(strings.Builder)
+	if err := c.runner.RunWithContext(ctx, "docker", repoDigestArgs(imageName(uri, tags[0])), exec.Stdout(buf)); err != nil {
+		return "", fmt.Errorf("inspect image digest for %s: %w", uri, err)
+	}
+	var repoDigests []string
+	out := strings.Trim(strings.TrimSpace(buf.String()), "'") // remove new lines and quotes from output
+	if err := json.Unmarshal([]byte(out), &repoDigests); err != nil {
+		return "", fmt.Errorf("unmarshal the repo digests of %s: %w", uri, err)
+	}
+	for _, repoDigest := range repoDigests {
+		if d, ok := strings.CutPrefix(repoDigest, uri+"@"); ok {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("find the digest of %s in the repo digests [%s]", uri, strings.Join(repoDigests, ", "))
+}
+
+// pushDigestPattern matches the line printed by docker push once the manifest is pushed, like "latest: digest: sha256:... size: 528".
+var pushDigestPattern = regexp.MustCompile(`digest: (sha256:[a-f0-9]+)`)
+
+// pushTags pushes the tags of the image concurrently, running at most pushConcurrency pushes at a time if it's set.
+// The layers are shared between the tags, so only the first push uploads them. It returns the errors of all the pushes that failed.
+// The image has the same digest regardless of its tag: the digest is returned if it's printed by any of the pushes.
+func (c DockerCmdClient) pushTags(ctx context.Context, uri string, w io.Writer, tags []string) (digest string, err error) {
+	concurrency := c.pushConcurrency
+	if concurrency <= 0 {
+		concurrency = len(tags)
+	}
+	if len(tags) > 1 {
+		w = &syncWriter{w: w}
+	}
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex // Guards errs and digest.
+		errs []error
+	)
 	for _, tag := range tags {
-		images = append(images, imageName(uri, tag))
+		img := imageName(uri, tag)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			d, err := c.pushTag(ctx, img, w)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("docker push %s: %w", img, err))
+				return
+			}
+			if digest == "" {
+				digest = d
+			}
+		}()
 	}
-	var args []string
-	if ci, _ := c.lookupEnv("CI"); ci == "true" {
-		args = append(args, "--quiet")
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return "", err
 	}
+	return digest, nil
+}
 
-	for _, img := range images {
-		if err := c.runner.RunWithContext(ctx, "docker", append([]string{"push", img}, args...), exec.Stdout(w), exec.Stderr(w)); err != nil {
-			return "", fmt.Errorf("docker push %s: %w", img, err)
+// pushTag pushes the image, retrying with pushRetry if it's set and the push fails with a transient error.
+// It returns ErrTagAlreadyExists if the repository has immutable tags and already has the tag.
+// It returns the digest printed by docker push, or an empty string if the push was quiet.
+// If skipUnchanged is set and the registry already has the image, it returns the digest in the registry without pushing.
+func (c DockerCmdClient) pushTag(ctx context.Context, img string, w io.Writer) (digest string, err error) {
+	if c.skipUnchanged {
+		if digest, ok := c.pushedDigest(ctx, img); ok {
+			fmt.Fprintf(w, "Image %s is already up to date in the registry, skipping the push.\n", img)
+			return digest, nil
 		}
 	}
-	buf := new(strings.Builder)
-	// The container image will have the same digest regardless of the associated tag.
-	// Pick the first tag and get the image's digest.
-	// For Main container we call  docker inspect --format '{{json (index .RepoDigests 0)}}' uri:latest
-	// For Sidecar container images we call docker inspect --format '{{json (index .RepoDigests 0)}}' uri:<sidecarname>-latest
-	if err := c.runner.RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json (index .RepoDigests 0)}}'", imageName(uri, tags[0])}, exec.Stdout(buf)); err != nil {
-		return "", fmt.Errorf("inspect image digest for %s: %w", uri, err)
+	push := func(tail *tailBuffer) error {
+		out := io.MultiWriter(w, tail)
+		if c.pushProgress != nil {
+			progress := newLineWriter(c.pushProgress, img)
+			defer progress.Flush()
+			out = io.MultiWriter(out, progress)
+		}
+		if err := c.runner.RunWithContext(ctx, "docker", c.pushArgs(img), exec.Stdout(out), exec.Stderr(out)); err != nil {
+			if isImmutableTagConflict(tail.String()) {
+				return &ErrTagAlreadyExists{
+					Image: img,
+					err:   err,
+				}
+			}
+			return err
+		}
+		if m := pushDigestPattern.FindStringSubmatch(tail.String()); m != nil {
+			digest = m[1]
+		}
+		return nil
+	}
+	if c.pushRetry == nil {
+		err = push(&tailBuffer{size: buildOutputTailSize})
+	} else {
+		err = c.pushRetry.withPushRetry(ctx, img, push)
+	}
+	return digest, err
+}
+
+// WithPushRetry returns a copy of the client that retries the push of a tag with p if it fails with a transient error,
+// such as throttling by the registry or a connection reset.
+func (c DockerCmdClient) WithPushRetry(p *RetryPolicy) DockerCmdClient {
+	c.pushRetry = p
+	return c
+}
+
+// WithPushConcurrency returns a copy of the client that pushes at most n tags of an image at a time.
+// By default, all the tags are pushed at once.
+func (c DockerCmdClient) WithPushConcurrency(n int) DockerCmdClient {
+	c.pushConcurrency = n
+	return c
+}
+
+// syncWriter serializes the writes of concurrent docker commands to w.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Tag creates the dst reference to the local src image, so that it can be pushed to other repositories
+// or with other tags without being rebuilt.
+func (c DockerCmdClient) Tag(ctx context.Context, src, dst string) error {
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"tag", src, dst}); err != nil {
+		return fmt.Errorf("tag image %s as %s: %w", src, dst, err)
 	}
-	repoDigest := strings.Trim(strings.TrimSpace(buf.String()), `"'`) // remove new lines and quotes from output
-	parts := strings.SplitAfter(repoDigest, "@")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("parse the digest from the repo digest '%s'", repoDigest)
+	return nil
+}
+
+// pushArgs returns the arguments of the `docker push` command for the image.
+func (c DockerCmdClient) pushArgs(img string) []string {
+	args := []string{"push", img}
+	if ci, _ := c.lookupEnv("CI"); ci == "true" {
+		args = append(args, "--quiet")
+	}
+	return args
+}
+
+// repoDigestArgs returns the arguments of the `docker inspect` command printing the repository digests of the image.
+func repoDigestArgs(img string) []string {
+	return []string{"inspect", "--format", "'{{json .RepoDigests}}'", img}
+}
+
+// validateRestartPolicy returns an error if policy isn't a restart policy of docker run, so that it fails before the image is pulled.
+func validateRestartPolicy(policy string) error {
+	name, retries, hasRetries := strings.Cut(policy, ":")
+	switch {
+	case policy == "", policy == "no", policy == "always", policy == "unless-stopped":
+		return nil
+	case name == "on-failure" && !hasRetries:
+		return nil
+	case name == "on-failure":
+		if n, err := strconv.Atoi(retries); err == nil && n >= 0 {
+			return nil
+		}
 	}
-	return parts[1], nil
+	return fmt.Errorf(`invalid restart policy %q: must be "no", "always", "unless-stopped" or "on-failure[:max-retries]"`, policy)
 }
 
 func (in *RunOptions) generateRunArguments() []string {
@@ -219,15 +725,66 @@ func (in *RunOptions) generateRunArguments() []string {
 		args = append(args, "--name", in.ContainerName)
 	}
 
+	if in.Platform != "" {
+		args = append(args, "--platform", in.Platform)
+	}
+
 	for hostPort, containerPort := range in.ContainerPorts {
 		args = append(args, "--publish", fmt.Sprintf("%s:%s", hostPort, containerPort))
 	}
 
 	// Add network option if it's not a "pause" container.
-	if !strings.HasPrefix(in.ContainerName, "pause") {
+	if in.ContainerNetwork != "" && !strings.HasPrefix(in.ContainerName, "pause") {
 		args = append(args, "--network", fmt.Sprintf("container:%s", in.ContainerNetwork))
 	}
 
+	// Add CPU and memory limits.
+	if in.CPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(in.CPUs, 'f', -1, 64))
+	}
+	if in.Memory > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", in.Memory))
+	}
+
+	// Add CPU and memory node pinning options.
+	if in.CpusetCpus != "" {
+		args = append(args, "--cpuset-cpus", in.CpusetCpus)
+	}
+	if in.CpusetMems != "" {
+		args = append(args, "--cpuset-mems", in.CpusetMems)
+	}
+
+	// Collect the host paths in a slice to sort for test stability.
+	var hostPaths []string
+	for hostPath := range in.Mounts {
+		hostPaths = append(hostPaths, hostPath)
+	}
+	sort.Strings(hostPaths)
+	for _, hostPath := range hostPaths {
+		args = append(args, "--volume", fmt.Sprintf("%s:%s", hostPath, in.Mounts[hostPath]))
+	}
+	var tmpfsPaths []string
+	for path := range in.Tmpfs {
+		tmpfsPaths = append(tmpfsPaths, path)
+	}
+	sort.Strings(tmpfsPaths)
+	for _, path := range tmpfsPaths {
+		if opts := in.Tmpfs[path]; opts != "" {
+			args = append(args, "--tmpfs", fmt.Sprintf("%s:%s", path, opts))
+			continue
+		}
+		args = append(args, "--tmpfs", path)
+	}
+	if in.User != "" {
+		args = append(args, "--user", in.User)
+	}
+	if in.UsernsHost {
+		args = append(args, "--userns", "host")
+	}
+	for _, group := range in.GroupAdd {
+		args = append(args, "--group-add", group)
+	}
+
 	for key, value := range in.Secrets {
 		args = append(args, "--env", fmt.Sprintf("%s=%s", key, value))
 	}
@@ -236,8 +793,39 @@ func (in *RunOptions) generateRunArguments() []string {
 		args = append(args, "--env", fmt.Sprintf("%s=%s", key, value))
 	}
 
+	if in.FakeTime != nil {
+		args = append(args, in.FakeTime.runArgs(in.Platform)...)
+	}
+
+	if in.HealthCheck != nil {
+		args = append(args, in.HealthCheck.runArgs()...)
+	}
+	if in.Init {
+		args = append(args, "--init")
+	}
+	if in.StopSignal != "" {
+		args = append(args, "--stop-signal", in.StopSignal)
+	}
+	if in.StopTimeout > 0 {
+		// docker run only takes whole seconds.
+		args = append(args, "--stop-timeout", strconv.Itoa(int(math.Ceil(in.StopTimeout.Seconds()))))
+	}
+	if in.RestartPolicy != "" {
+		args = append(args, "--restart", in.RestartPolicy)
+	}
+	if in.WorkDir != "" {
+		args = append(args, "--workdir", in.WorkDir)
+	}
+	// docker run only takes the executable as the entrypoint, so the rest of it goes before the command.
+	if len(in.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", in.Entrypoint[0])
+	}
+
 	args = append(args, in.ImageURI)
 
+	if len(in.Entrypoint) > 1 {
+		args = append(args, in.Entrypoint[1:]...)
+	}
 	if in.Command != nil && len(in.Command) > 0 {
 		args = append(args, in.Command...)
 	}
@@ -245,9 +833,22 @@ func (in *RunOptions) generateRunArguments() []string {
 }
 
 // Run runs a Docker container with the sepcified options.
+// If the client has a trust policy, the container only runs if the signature of its image is trusted,
+// and it runs the image by the digest that was verified.
 func (c DockerCmdClient) Run(ctx context.Context, options *RunOptions) error {
+	if err := validateRestartPolicy(options.RestartPolicy); err != nil {
+		return err
+	}
+	options, err := c.trustedRunOptions(ctx, options)
+	if err != nil {
+		return err
+	}
+	if options, err = c.withFakeTimeLib(ctx, options); err != nil {
+		return err
+	}
+	c.warnMountOwnership(ctx, options)
 	//Execute the Docker run command.
-	if err := c.runner.RunWithContext(ctx, "docker", options.generateRunArguments()); err != nil {
+	if err := c.runner.RunWithContext(ctx, "docker", c.runArgs(options), c.withStdin()...); err != nil {
 		return fmt.Errorf("running container: %w", err)
 	}
 	return nil
@@ -322,16 +923,37 @@ func imageName(uri, tag string) string {
 
 // IsEcrCredentialHelperEnabled return true if ecr-login is enabled either globally or registry level
 func (c DockerCmdClient) IsEcrCredentialHelperEnabled(uri string) bool {
+	registry, _, _ := strings.Cut(uri, "/")
+	for _, config := range c.dockerConfigs() {
+		if config.CredsStore == credStoreECRLogin || config.CredHelpers[registry] == credStoreECRLogin {
+			return true
+		}
+	}
+	return false
+}
+
+// dockerConfigs returns the docker config files that can be read, from the isolated config directory,
+// the directory set by DOCKER_CONFIG, or the default locations.
+func (c DockerCmdClient) dockerConfigs() []*dockerConfig {
+	configDir := c.configDir
+	if configDir == "" && c.lookupEnv != nil {
+		if dir, ok := c.lookupEnv(dockerConfigEnv); ok && dir != "" {
+			configDir = dir
+		}
+	}
 	// Make sure the program is able to obtain the home directory
-	splits := strings.Split(uri, "/")
-	if c.homePath == "" || len(splits) == 0 {
-		return false
+	if c.homePath == "" && configDir == "" {
+		return nil
 	}
 
 	// Look into the default locations
-	pathsToTry := []string{filepath.Join(".docker", "config.json"), ".dockercfg"}
+	pathsToTry := []string{filepath.Join(c.homePath, ".docker", "config.json"), filepath.Join(c.homePath, ".dockercfg")}
+	if configDir != "" {
+		pathsToTry = []string{filepath.Join(configDir, dockerConfigFile)}
+	}
+	var configs []*dockerConfig
 	for _, path := range pathsToTry {
-		content, err := os.ReadFile(filepath.Join(c.homePath, path))
+		content, err := os.ReadFile(path)
 		if err != nil {
 			// if we can't read the file keep going
 			continue
@@ -341,13 +963,9 @@ func (c DockerCmdClient) IsEcrCredentialHelperEnabled(uri string) bool {
 		if err != nil {
 			continue
 		}
-
-		if config.CredsStore == credStoreECRLogin || config.CredHelpers[splits[0]] == credStoreECRLogin {
-			return true
-		}
+		configs = append(configs, config)
 	}
-
-	return false
+	return configs
 }
 
 // PlatformString returns a specified of the format <os>/<arch>.
@@ -362,8 +980,14 @@ func parseCredFromDockerConfig(config []byte) (*dockerConfig, error) {
 		        "credsStore" : "ecr-login",
 		        "credHelpers": {
 		            "dummyaccountId.dkr.ecr.region.amazonaws.com": "ecr-login"
+		        },
+		        "auths": {
+		            "registry.example.com": { "auth": "<base64 of username:password>" },
+		            "https://index.docker.io/v1/": { "identitytoken": "<token>" },
+		            "other.example.com": {}
 		        }
 		    }
+		Registries of "auths" without credentials have them in the credential store.
 	*/
 	cred := dockerConfig{}
 	err := json.Unmarshal(config, &cred)
@@ -383,6 +1007,23 @@ func userHomeDirectory() string {
 	return home
 }
 
+type errInvalidExtraFlag struct {
+	flag   string
+	reason string
+}
+
+func (e *errInvalidExtraFlag) Error() string {
+	return fmt.Sprintf("invalid extra build flag %q: %s", e.flag, e.reason)
+}
+
+type errInvalidBuildOutput struct {
+	reason string
+}
+
+func (e *errInvalidBuildOutput) Error() string {
+	return fmt.Sprintf("invalid build output: %s", e.reason)
+}
+
 type errEmptyImageTags struct {
 	uri string
 }
@@ -8,10 +8,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	osexec "os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/copilot-cli/internal/pkg/exec"
 
@@ -42,6 +45,9 @@ func TestDockerCommand_Build(t *testing.T) {
 		args       map[string]string
 		target     string
 		cacheFrom  []string
+		squash     bool
+		buildArgs  BuildArguments // Additional options that are not covered by the other fields.
+		stdin      io.Reader
 		envVars    map[string]string
 		labels     map[string]string
 		setupMocks func(controller *gomock.Controller)
@@ -193,6 +199,320 @@ func TestDockerCommand_Build(t *testing.T) {
 					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
+		"runs with resource limits": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Memory:    "2g",
+				CPUPeriod: 100000,
+				CPUQuota:  50000,
+				Ulimits: map[string]string{
+					"nproc":  "512",
+					"nofile": "1024:2048",
+				},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--memory", "2g",
+					"--cpu-period", "100000",
+					"--cpu-quota", "50000",
+					"--ulimit", "nofile=1024:2048",
+					"--ulimit", "nproc=512",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"should error if the Dockerfile is read from stdin but no stdin is provided": {
+			path: "-",
+			tags: []string{"latest"},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+			},
+			wantedError: fmt.Errorf("generate docker build args: %w", errMissingStdin),
+		},
+		"reads the Dockerfile from stdin": {
+			path:    "-",
+			context: mockContext,
+			tags:    []string{"latest"},
+			stdin:   strings.NewReader("FROM scratch"),
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"mockPath",
+					"-f", "-"}, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"passes through host environment variables that are set": {
+			path: mockPath,
+			tags: []string{"latest"},
+			args: map[string]string{
+				"VERSION": "v1",
+			},
+			envVars: map[string]string{
+				"HTTP_PROXY": "http://proxy",
+				"VERSION":    "v2",
+			},
+			buildArgs: BuildArguments{
+				ArgsFromEnv: []string{"VERSION", "NO_PROXY", "HTTP_PROXY"},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--build-arg", "VERSION=v1",
+					"--build-arg", "HTTP_PROXY",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"appends the extra flags before the context": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				ExtraFlags: []string{"--network", "host", "--add-host=db:10.0.0.5"},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--network", "host", "--add-host=db:10.0.0.5",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"should error if an extra flag overrides the tags": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				ExtraFlags: []string{"--tag=other:latest"},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+			},
+			wantedError: errors.New(`generate docker build args: invalid extra build flag "--tag=other:latest": it is set from the image's tags and Dockerfile`),
+		},
+		"should error if the extra flags don't start with a flag": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				ExtraFlags: []string{"host"},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+			},
+			wantedError: errors.New(`generate docker build args: invalid extra build flag "host": it must start with a flag`),
+		},
+		"runs under a cgroup parent": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				CgroupParent: "/ci/builds",
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--cgroup-parent", "/ci/builds",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"builds with a builder instance and loads the image": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Builder: "copilot",
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--builder", "copilot", "--load",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"embeds the cache metadata in the image": {
+			path:      mockPath,
+			tags:      []string{"latest"},
+			cacheFrom: []string{"mockURI:latest"},
+			buildArgs: BuildArguments{
+				InlineCache: true,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--cache-from", "mockURI:latest",
+					"--build-arg", "BUILDKIT_INLINE_CACHE=1",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"exports the inline cache from a builder instance": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				InlineCache: true,
+				Builder:     "copilot",
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--cache-to", "type=inline",
+					"--builder", "copilot", "--load",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"builds a reproducible image timestamped with SOURCE_DATE_EPOCH": {
+			path:    mockPath,
+			tags:    []string{"latest"},
+			args:    map[string]string{"GOPROXY": "direct"},
+			envVars: map[string]string{"SOURCE_DATE_EPOCH": "1700000000"},
+			buildArgs: BuildArguments{
+				Reproducible: true,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--output", "type=docker,rewrite-timestamp=true", "--provenance=false",
+					"--build-arg", "GOPROXY=direct", "--build-arg", "SOURCE_DATE_EPOCH=1700000000",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"pushes a reproducible image from the builder": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Builder:      "copilot",
+				Output:       PushDirect,
+				Reproducible: true,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--builder", "copilot", "--output", "type=image,push=true,rewrite-timestamp=true", "--provenance=false",
+					"--build-arg", "SOURCE_DATE_EPOCH=0",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"pushes directly from the builder": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Builder: "copilot",
+				Output:  PushDirect,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--builder", "copilot", "--push",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"loads the image into docker with the default builder": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Output: LoadToDaemon,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--load",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"should error if a multi-platform image is loaded into docker": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Builder:  "copilot",
+				Platform: "linux/amd64,linux/arm64",
+				Output:   LoadToDaemon,
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+			},
+			wantedError: errors.New("generate docker build args: invalid build output: images for multiple platforms linux/amd64,linux/arm64 can't be loaded into docker"),
+		},
+		"builds with additional named contexts": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				ExtraContexts: map[string]string{
+					"sharedlib": "../lib",
+					"assets":    "docker-image://mockURI:assets",
+				},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--build-context", "assets=docker-image://mockURI:assets",
+					"--build-context", "sharedlib=../lib",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"builds with OCI annotations": {
+			path: mockPath,
+			tags: []string{"latest"},
+			buildArgs: BuildArguments{
+				Annotations: map[string]string{
+					"org.opencontainers.image.revision": "abc123",
+					"org.opencontainers.image.created":  "2023-08-01T12:00:00Z",
+				},
+			},
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--annotation", "org.opencontainers.image.created=2023-08-01T12:00:00Z",
+					"--annotation", "org.opencontainers.image.revision=abc123",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"runs with squash if the daemon supports it": {
+			path:   mockPath,
+			tags:   []string{"latest"},
+			squash: true,
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"version", "-f", "{{json .Server.Experimental}}"}, gomock.Any()).
+					Do(mockStdout("true\n")).Return(nil)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"build",
+					"-t", fmt.Sprintf("%s:%s", mockURI, "latest"),
+					"--squash",
+					filepath.FromSlash("mockPath/to"),
+					"-f", "mockPath/to/mockDockerfile"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"should error if squash is requested but the daemon is not experimental": {
+			path:   mockPath,
+			tags:   []string{"latest"},
+			squash: true,
+			setupMocks: func(c *gomock.Controller) {
+				mockCmd = NewMockCmd(c)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"version", "-f", "{{json .Server.Experimental}}"}, gomock.Any()).
+					Do(mockStdout("false\n")).Return(nil)
+			},
+			wantedError: ErrSquashNotSupported,
+		},
 	}
 
 	for name, tc := range tests {
@@ -207,17 +527,18 @@ func TestDockerCommand_Build(t *testing.T) {
 					}
 					return "", false
 				},
+				stdin: tc.stdin,
 			}
-			buildInput := BuildArguments{
-				Context:    tc.context,
-				Dockerfile: tc.path,
-				URI:        mockURI,
-				Args:       tc.args,
-				Target:     tc.target,
-				CacheFrom:  tc.cacheFrom,
-				Tags:       tc.tags,
-				Labels:     tc.labels,
-			}
+			buildInput := tc.buildArgs
+			buildInput.Context = tc.context
+			buildInput.Dockerfile = tc.path
+			buildInput.URI = mockURI
+			buildInput.Args = tc.args
+			buildInput.Target = tc.target
+			buildInput.CacheFrom = tc.cacheFrom
+			buildInput.Tags = tc.tags
+			buildInput.Labels = tc.labels
+			buildInput.Squash = tc.squash
 			buf := new(strings.Builder)
 			got := s.Build(ctx, &buildInput, buf)
 
@@ -240,6 +561,7 @@ func TestDockerCommand_Login(t *testing.T) {
 	var mockCmd *MockCmd
 
 	tests := map[string]struct {
+		uri        string
 		setupMocks func(controller *gomock.Controller)
 
 		want error
@@ -260,6 +582,15 @@ func TestDockerCommand_Login(t *testing.T) {
 			},
 			want: nil,
 		},
+		"logs in to the whole registry for ECR Public": {
+			uri: "public.ecr.aws/alias/web",
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+
+				mockCmd.EXPECT().Run("docker", []string{"login", "-u", mockUsername, "--password-stdin", "public.ecr.aws"}, gomock.Any()).Return(nil)
+			},
+			want: nil,
+		},
 	}
 
 	for name, test := range tests {
@@ -270,13 +601,64 @@ func TestDockerCommand_Login(t *testing.T) {
 				runner: mockCmd,
 			}
 
-			got := s.Login(mockURI, mockUsername, mockPassword)
+			uri := mockURI
+			if test.uri != "" {
+				uri = test.uri
+			}
+			got := s.Login(uri, mockUsername, mockPassword)
 
 			require.Equal(t, test.want, got)
 		})
 	}
 }
 
+func TestDockerCommand_Logout(t *testing.T) {
+	tests := map[string]struct {
+		uri       string
+		setupMock func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"logs out of the repository": {
+			uri: "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc",
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"logout", "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"}, gomock.Any()).Return(nil)
+			},
+		},
+		"logs out of the whole registry for ECR Public": {
+			uri: "public.ecr.aws/alias/web",
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"logout", "public.ecr.aws"}, gomock.Any()).Return(nil)
+			},
+		},
+		"wraps the error": {
+			uri: "public.ecr.aws/alias/web",
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().Run("docker", []string{"logout", "public.ecr.aws"}, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "log out of public.ecr.aws/alias/web: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			err := s.Logout(tc.uri)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestDockerCommand_Push(t *testing.T) {
 	emptyLookupEnv := func(key string) (string, bool) {
 		return "", false
@@ -289,11 +671,11 @@ func TestDockerCommand_Push(t *testing.T) {
 		m := NewMockCmd(ctrl)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any(), gomock.Any()).Return(nil)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:g123bfc"}, gomock.Any(), gomock.Any()).Return(nil)
-		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json (index .RepoDigests 0)}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
 			Do(func(ctx context.Context, _ string, _ []string, opt exec.CmdOption) {
 				cmd := &osexec.Cmd{}
 				opt(cmd)
-				_, _ = cmd.Stdout.Write([]byte("\"aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app@sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807\"\n"))
+				_, _ = cmd.Stdout.Write([]byte("[\"aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app@sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807\"]\n"))
 			}).Return(nil)
 
 		// WHEN
@@ -314,11 +696,11 @@ func TestDockerCommand_Push(t *testing.T) {
 		defer ctrl.Finish()
 		m := NewMockCmd(ctrl)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest", "--quiet"}, gomock.Any(), gomock.Any()).Return(nil)
-		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json (index .RepoDigests 0)}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
 			Do(func(ctx context.Context, _ string, _ []string, opt exec.CmdOption) {
 				cmd := &osexec.Cmd{}
 				opt(cmd)
-				_, _ = cmd.Stdout.Write([]byte("\"aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app@sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807\"\n"))
+				_, _ = cmd.Stdout.Write([]byte("[\"aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app@sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807\"]\n"))
 			}).Return(nil)
 
 		// WHEN
@@ -356,13 +738,89 @@ func TestDockerCommand_Push(t *testing.T) {
 		// THEN
 		require.EqualError(t, err, "docker push uri:latest: some error")
 	})
+	t.Run("returns ErrTagAlreadyExists if the repository has immutable tags", func(t *testing.T) {
+		// GIVEN
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:v1"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("tag invalid: The image tag 'v1' already exists in the 'app/svc' repository and cannot be overwritten because the repository is immutable.\n")).
+			Return(errors.New("exit status 1"))
+
+		// WHEN
+		cmd := DockerCmdClient{
+			runner:    m,
+			lookupEnv: emptyLookupEnv,
+		}
+		_, err := cmd.Push(ctx, "uri", new(strings.Builder), "v1")
+
+		// THEN
+		var tagErr *ErrTagAlreadyExists
+		require.ErrorAs(t, err, &tagErr)
+		require.Equal(t, "uri:v1", tagErr.Image)
+		require.EqualError(t, err, "docker push uri:v1: tag of image uri:v1 already exists in the repository and tags are immutable: exit status 1")
+	})
+	t.Run("returns the errors of all the failed pushes", func(t *testing.T) {
+		// GIVEN
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:v1"}, gomock.Any(), gomock.Any()).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:v2"}, gomock.Any(), gomock.Any()).Return(errors.New("other error"))
+
+		// WHEN
+		cmd := DockerCmdClient{
+			runner:    m,
+			lookupEnv: emptyLookupEnv,
+		}
+		_, err := cmd.Push(ctx, "uri", new(strings.Builder), "latest", "v1", "v2")
+
+		// THEN
+		require.ErrorContains(t, err, "docker push uri:latest: some error")
+		require.ErrorContains(t, err, "docker push uri:v2: other error")
+	})
+	t.Run("pushes at most the configured number of tags at a time", func(t *testing.T) {
+		// GIVEN
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := NewMockCmd(ctrl)
+		var inFlight, maxInFlight int32
+		m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					cur := atomic.LoadInt32(&maxInFlight)
+					if n <= cur || atomic.CompareAndSwapInt32(&maxInFlight, cur, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			}).Times(3)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "uri:latest"}, gomock.Any()).
+			Do(mockStdout(`["uri@sha256:abc"]`)).Return(nil)
+
+		// WHEN
+		cmd := DockerCmdClient{
+			runner:    m,
+			lookupEnv: emptyLookupEnv,
+		}.WithPushConcurrency(2)
+		digest, err := cmd.Push(ctx, "uri", new(strings.Builder), "latest", "v1", "v2")
+
+		// THEN
+		require.NoError(t, err)
+		require.Equal(t, "sha256:abc", digest)
+		require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	})
 	t.Run("returns a wrapped error on failure to retrieve image digest", func(t *testing.T) {
 		// GIVEN
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		m := NewMockCmd(ctrl)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).Return(nil)
-		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json (index .RepoDigests 0)}}'", "uri:latest"}, gomock.Any()).Return(errors.New("some error"))
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "uri:latest"}, gomock.Any()).Return(errors.New("some error"))
 
 		// WHEN
 		cmd := DockerCmdClient{
@@ -375,6 +833,45 @@ func TestDockerCommand_Push(t *testing.T) {
 		// THEN
 		require.EqualError(t, err, "inspect image digest for uri: some error")
 	})
+	t.Run("returns the digest printed by the push without inspecting the image", func(t *testing.T) {
+		// GIVEN
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("5f70bf18a086: Pushed\nlatest: digest: sha256:f1d4ae3f7261 size: 528\n")).Return(nil)
+
+		// WHEN
+		cmd := DockerCmdClient{
+			runner:    m,
+			lookupEnv: emptyLookupEnv,
+		}
+		digest, err := cmd.Push(ctx, "uri", new(strings.Builder), "latest")
+
+		// THEN
+		require.NoError(t, err)
+		require.Equal(t, "sha256:f1d4ae3f7261", digest)
+	})
+	t.Run("picks the repo digest of the repository among the ones of other registries", func(t *testing.T) {
+		// GIVEN
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "uri:latest"}, gomock.Any()).
+			Do(mockStdout(`["public.ecr.aws/web@sha256:other","uri@sha256:abc"]`)).Return(nil)
+
+		// WHEN
+		cmd := DockerCmdClient{
+			runner:    m,
+			lookupEnv: emptyLookupEnv,
+		}
+		digest, err := cmd.Push(ctx, "uri", new(strings.Builder), "latest")
+
+		// THEN
+		require.NoError(t, err)
+		require.Equal(t, "sha256:abc", digest)
+	})
 	t.Run("returns an error if the repo digest cannot be parsed for the pushed image", func(t *testing.T) {
 		// GIVEN
 		ctrl := gomock.NewController(t)
@@ -382,11 +879,11 @@ func TestDockerCommand_Push(t *testing.T) {
 		m := NewMockCmd(ctrl)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any(), gomock.Any()).Return(nil)
 		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:g123bfc"}, gomock.Any(), gomock.Any()).Return(nil)
-		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json (index .RepoDigests 0)}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"inspect", "--format", "'{{json .RepoDigests}}'", "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app:latest"}, gomock.Any()).
 			Do(func(ctx context.Context, _ string, _ []string, opt exec.CmdOption) {
 				cmd := &osexec.Cmd{}
 				opt(cmd)
-				_, _ = cmd.Stdout.Write([]byte(""))
+				_, _ = cmd.Stdout.Write([]byte(`["public.ecr.aws/my-web-app@sha256:abc"]`))
 			}).Return(nil)
 
 		// WHEN
@@ -398,7 +895,35 @@ func TestDockerCommand_Push(t *testing.T) {
 		_, err := cmd.Push(ctx, "aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app", buf, "latest", "g123bfc")
 
 		// THEN
-		require.EqualError(t, err, "parse the digest from the repo digest ''")
+		require.EqualError(t, err, "find the digest of aws_account_id.dkr.ecr.region.amazonaws.com/my-web-app in the repo digests [public.ecr.aws/my-web-app@sha256:abc]")
+	})
+}
+
+func TestDockerCommand_Tag(t *testing.T) {
+	ctx := context.Background()
+	t.Run("tags the image", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "web:latest", "aws_account_id.dkr.ecr.region.amazonaws.com/web:prod"}).Return(nil)
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.Tag(ctx, "web:latest", "aws_account_id.dkr.ecr.region.amazonaws.com/web:prod")
+
+		require.NoError(t, err)
+	})
+	t.Run("returns a wrapped error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "web:latest", "web:prod"}).Return(errors.New("some error"))
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.Tag(ctx, "web:latest", "web:prod")
+
+		require.EqualError(t, err, "tag image web:latest as web:prod: some error")
 	})
 }
 
@@ -658,6 +1183,7 @@ func TestDockerCommand_Run(t *testing.T) {
 		ports            map[string]string
 		command          []string
 		containerNetwork string
+		runOptions       RunOptions
 		setupMocks       func(controller *gomock.Controller)
 
 		wantedError error
@@ -696,6 +1222,238 @@ func TestDockerCommand_Run(t *testing.T) {
 					"--env", "COPILOT_SERVICE_NAME=mockSvcName", "--env", "COPILOT_ENVIRONMENT_NAME=mockEnvName", mockImageURI})).Return(nil)
 			},
 		},
+		"success with pinned CPUs and memory nodes": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				CpusetCpus: "0-3",
+				CpusetMems: "0",
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--cpuset-cpus", "0-3", "--cpuset-mems", "0", mockImageURI}).Return(nil)
+			},
+		},
+		"success with bind mounts in the user namespace of the host": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Mounts:     map[string]string{"/home/user/web": "/app", "/home/user/.aws": "/root/.aws"},
+				UsernsHost: true,
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--volume", "/home/user/.aws:/root/.aws", "--volume", "/home/user/web:/app",
+					"--userns", "host", mockImageURI}).Return(nil)
+			},
+		},
+		"success with bind mounts if the user namespace can't be probed": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Mounts: map[string]string{"/home/user/web": "/app"},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"info", "--format", "{{json .SecurityOptions}}"}, gomock.Any()).
+					Return(mockError)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--volume", "/home/user/web:/app", mockImageURI}).Return(nil)
+			},
+		},
+		"success with supplementary groups": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				GroupAdd: []string{"docker", "44"},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--group-add", "docker", "--group-add", "44", mockImageURI}).Return(nil)
+			},
+		},
+		"success with a user override": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				User:     "1000:1000",
+				GroupAdd: []string{"docker"},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--user", "1000:1000", "--group-add", "docker", mockImageURI}).Return(nil)
+			},
+		},
+		"success with entrypoint and working directory overrides": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			command:       []string{"ls -la"},
+			runOptions: RunOptions{
+				Entrypoint: []string{"/bin/sh", "-c"},
+				WorkDir:    "/app",
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--workdir", "/app", "--entrypoint", "/bin/sh", mockImageURI, "-c", "ls -la"}).Return(nil)
+			},
+		},
+		"success with a restart policy": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				RestartPolicy: "on-failure:3",
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--restart", "on-failure:3", mockImageURI}).Return(nil)
+			},
+		},
+		"should error if the restart policy is invalid": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				RestartPolicy: "on-failure:three",
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+			},
+			wantedError: errors.New(`invalid restart policy "on-failure:three": must be "no", "always", "unless-stopped" or "on-failure[:max-retries]"`),
+		},
+		"success with a health check": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				HealthCheck: &HealthCheck{
+					Command:  []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+					Interval: 5 * time.Second,
+					Retries:  3,
+				},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--health-cmd", "curl -f http://localhost/ || exit 1",
+					"--health-interval", "5s", "--health-retries", "3", mockImageURI}).Return(nil)
+			},
+		},
+		"success with cpu and memory limits": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				CPUs:   0.25,
+				Memory: 512,
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--cpus", "0.25", "--memory", "512m", mockImageURI}).Return(nil)
+			},
+		},
+		"success with a platform": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Platform: "linux/amd64",
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--platform", "linux/amd64", mockImageURI}).Return(nil)
+			},
+		},
+		"success with an init process and stop options": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Init:        true,
+				StopSignal:  "SIGINT",
+				StopTimeout: 30 * time.Second,
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--init", "--stop-signal", "SIGINT", "--stop-timeout", "30", mockImageURI}).Return(nil)
+			},
+		},
+		"success with tmpfs mounts": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Tmpfs: map[string]string{
+					"/tmp":     "rw,size=64m",
+					"/var/run": "",
+				},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--tmpfs", "/tmp:rw,size=64m", "--tmpfs", "/var/run", mockImageURI}).Return(nil)
+			},
+		},
+		"success with a fake time offset for the platform of the container": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				Platform: "linux/arm64",
+				FakeTime: &FakeTime{Offset: 30 * 24 * time.Hour},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--platform", "linux/arm64",
+					"--env", "LD_PRELOAD=/usr/lib/aarch64-linux-gnu/faketime/libfaketime.so.1", "--env", "FAKETIME=+2592000",
+					"--env", "FAKETIME_DONT_FAKE_MONOTONIC=1", mockImageURI}).Return(nil)
+			},
+		},
+		"success with a fake time offset for the architecture of the image": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				FakeTime: &FakeTime{Offset: 30 * 24 * time.Hour},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", mockImageURI}, gomock.Any()).
+					Do(mockStdout(`[{"Architecture":"amd64"}]`)).Return(nil)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName,
+					"--env", "LD_PRELOAD=/usr/lib/x86_64-linux-gnu/faketime/libfaketime.so.1", "--env", "FAKETIME=+2592000",
+					"--env", "FAKETIME_DONT_FAKE_MONOTONIC=1", mockImageURI}).Return(nil)
+			},
+		},
+		"should error if the path of libfaketime is unknown for the architecture of the image": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				FakeTime: &FakeTime{Offset: time.Hour},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"image", "inspect", mockImageURI}, gomock.Any()).
+					Do(mockStdout(`[{"Architecture":"s390x"}]`)).Return(nil)
+			},
+			wantedError: errors.New(`unknown path of libfaketime for architecture "s390x": set the path of the library in the container`),
+		},
+		"success with a fake time offset and a mounted library": {
+			containerName: mockContainerName,
+			uri:           mockImageURI,
+			runOptions: RunOptions{
+				FakeTime: &FakeTime{Offset: -time.Hour, HostLibPath: "/usr/local/lib/faketime/libfaketime.so.1"},
+			},
+			setupMocks: func(controller *gomock.Controller) {
+				mockCmd = NewMockCmd(controller)
+				mockCmd.EXPECT().RunWithContext(ctx, "docker", []string{"run",
+					"--name", mockContainerName, "--volume", "/usr/local/lib/faketime/libfaketime.so.1:/opt/copilot/libfaketime.so.1:ro",
+					"--env", "LD_PRELOAD=/opt/copilot/libfaketime.so.1", "--env", "FAKETIME=-3600",
+					"--env", "FAKETIME_DONT_FAKE_MONOTONIC=1", mockImageURI}).Return(nil)
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -710,15 +1468,14 @@ func TestDockerCommand_Run(t *testing.T) {
 					return "", false
 				},
 			}
-			runInput := RunOptions{
-				ImageURI:         tc.uri,
-				Secrets:          tc.secrets,
-				EnvVars:          tc.envVars,
-				ContainerName:    tc.containerName,
-				ContainerNetwork: tc.containerNetwork,
-				Command:          tc.command,
-				ContainerPorts:   tc.ports,
-			}
+			runInput := tc.runOptions
+			runInput.ImageURI = tc.uri
+			runInput.Secrets = tc.secrets
+			runInput.EnvVars = tc.envVars
+			runInput.ContainerName = tc.containerName
+			runInput.ContainerNetwork = tc.containerNetwork
+			runInput.Command = tc.command
+			runInput.ContainerPorts = tc.ports
 			err := s.Run(ctx, &runInput)
 
 			if tc.wantedError != nil {
@@ -730,6 +1487,15 @@ func TestDockerCommand_Run(t *testing.T) {
 	}
 }
 
+func TestValidateRestartPolicy(t *testing.T) {
+	for _, policy := range []string{"", "no", "always", "unless-stopped", "on-failure", "on-failure:0", "on-failure:5"} {
+		require.NoError(t, validateRestartPolicy(policy), policy)
+	}
+	for _, policy := range []string{"sometimes", "on-failure:", "on-failure:-1", "on-failure:five", "always:3"} {
+		require.Error(t, validateRestartPolicy(policy), policy)
+	}
+}
+
 func TestDockerCommand_IsContainerRunning(t *testing.T) {
 	mockError := errors.New("some error")
 	mockContainerName := "mockContainer"
@@ -775,3 +1541,30 @@ func TestDockerCommand_IsContainerRunning(t *testing.T) {
 		})
 	}
 }
+
+// mockStdout returns a function for gomock's Do that writes out to the command's stdout.
+func mockStdout(out string) func(context.Context, string, []string, ...exec.CmdOption) {
+	return func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+		cmd := &osexec.Cmd{}
+		for _, opt := range opts {
+			opt(cmd)
+		}
+		_, _ = cmd.Stdout.Write([]byte(out))
+	}
+}
+
+// mockStderr returns a function for gomock's Do that writes out to the command's stderr.
+func mockStderr(out string) func(context.Context, string, []string, ...exec.CmdOption) {
+	return func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+		cmd := &osexec.Cmd{}
+		for _, opt := range opts {
+			opt(cmd)
+		}
+		_, _ = cmd.Stderr.Write([]byte(out))
+	}
+}
+
+func TestIsECRPublicURI(t *testing.T) {
+	require.True(t, IsECRPublicURI("public.ecr.aws/nginx/nginx:latest"))
+	require.False(t, IsECRPublicURI("123456789012.dkr.ecr.us-west-2.amazonaws.com/web"))
+}
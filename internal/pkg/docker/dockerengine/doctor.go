@@ -0,0 +1,289 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/mod/semver"
+)
+
+// CheckStatus is the outcome of a doctor check.
+type CheckStatus string
+
+// Outcomes of a doctor check, from best to worst.
+const (
+	CheckStatusPass CheckStatus = "pass"
+	CheckStatusSkip CheckStatus = "skip" // The check couldn't run because a check it depends on failed.
+	CheckStatusWarn CheckStatus = "warn"
+	CheckStatusFail CheckStatus = "fail"
+)
+
+// Names of the doctor checks.
+const (
+	CheckDockerBinary      = "docker-binary"
+	CheckDaemon            = "daemon"
+	CheckServerVersion     = "server-version"
+	CheckBuildx            = "buildx"
+	CheckCompose           = "compose"
+	CheckDiskSpace         = "disk-space"
+	CheckEmulation         = "emulation"
+	CheckCredentialHelpers = "credential-helpers"
+	CheckECRConnectivity   = "ecr-connectivity"
+)
+
+const (
+	// minRecommendedServerVersion is the oldest docker version that supports all the build flags used by Copilot.
+	minRecommendedServerVersion = "v20.10.0"
+	// lowDiskSpaceThreshold is the free disk space below which builds are likely to fail.
+	lowDiskSpaceThreshold = 5 << 30 // 5 GiB
+	ecrDialTimeout        = 5 * time.Second
+)
+
+// doctorPlatforms are the platforms that Copilot workloads can run on.
+var doctorPlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// CheckResult is the outcome of a single doctor check.
+type CheckResult struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// DoctorReport holds the outcome of the doctor checks in the order they ran.
+// It can be marshaled to JSON to attach it to bug reports.
+type DoctorReport struct {
+	Status CheckStatus   `json:"status"` // Worst status of the checks.
+	Checks []CheckResult `json:"checks"`
+}
+
+func (r *DoctorReport) add(name string, status CheckStatus, format string, args ...any) {
+	r.Checks = append(r.Checks, CheckResult{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+	if severity(status) > severity(r.Status) {
+		r.Status = status
+	}
+}
+
+func (r *DoctorReport) skip(names ...string) {
+	for _, name := range names {
+		r.add(name, CheckStatusSkip, "docker daemon is not reachable")
+	}
+}
+
+func severity(s CheckStatus) int {
+	switch s {
+	case CheckStatusSkip:
+		return 1
+	case CheckStatusWarn:
+		return 2
+	case CheckStatusFail:
+		return 3
+	}
+	return 0
+}
+
+// doctor runs the doctor checks of a docker client.
+type doctor struct {
+	client DockerCmdClient
+
+	// Override in unit tests.
+	lookPath  func(file string) (string, error)
+	dial      func(ctx context.Context, network, address string) (net.Conn, error)
+	freeSpace func(path string) (uint64, error)
+}
+
+// Doctor runs a battery of checks against the local docker installation and returns a report of their outcome.
+// Checks that depend on the docker daemon are skipped if it is not reachable.
+func (c DockerCmdClient) Doctor(ctx context.Context) *DoctorReport {
+	d := &doctor{
+		client:    c,
+		lookPath:  osexec.LookPath,
+		dial:      (&net.Dialer{Timeout: ecrDialTimeout}).DialContext,
+		freeSpace: freeDiskSpace,
+	}
+	return d.run(ctx)
+}
+
+func (d *doctor) run(ctx context.Context) *DoctorReport {
+	report := &DoctorReport{Status: CheckStatusPass}
+	daemonChecks := []string{CheckDaemon, CheckServerVersion, CheckBuildx, CheckCompose, CheckEmulation}
+	if path, err := d.lookPath("docker"); err != nil {
+		report.add(CheckDockerBinary, CheckStatusFail, "docker command is not found in $PATH: %v", err)
+		report.skip(daemonChecks...)
+	} else {
+		report.add(CheckDockerBinary, CheckStatusPass, "found %s", path)
+		d.checkDaemon(ctx, report)
+	}
+	d.checkDiskSpace(report)
+	d.checkCredentialHelpers(report)
+	d.checkECRConnectivity(ctx, report)
+	return report
+}
+
+func (d *doctor) checkDaemon(ctx context.Context, report *DoctorReport) {
+	info, err := d.client.dockerInfoPlugins(ctx)
+	if err != nil || info.serverVersion == "" {
+		if err == nil {
+			err = fmt.Errorf("no server version reported")
+		}
+		report.add(CheckDaemon, CheckStatusFail, "docker daemon is not reachable: %v", err)
+		report.skip(CheckServerVersion, CheckBuildx, CheckCompose, CheckEmulation)
+		return
+	}
+	report.add(CheckDaemon, CheckStatusPass, "docker daemon is reachable")
+
+	if semver.Compare(canonicalVersion(info.serverVersion), minRecommendedServerVersion) < 0 {
+		report.add(CheckServerVersion, CheckStatusWarn, "docker %s is older than the recommended version %s", info.serverVersion, strings.TrimPrefix(minRecommendedServerVersion, "v"))
+	} else {
+		report.add(CheckServerVersion, CheckStatusPass, "docker %s", info.serverVersion)
+	}
+
+	features := d.client.features(ctx, info)
+	if features.Buildx {
+		report.add(CheckBuildx, CheckStatusPass, "buildx %s", features.BuildxVersion)
+	} else {
+		report.add(CheckBuildx, CheckStatusWarn, "buildx is not installed: multi-platform builds and build secrets are not available")
+	}
+	if features.Compose {
+		report.add(CheckCompose, CheckStatusPass, "compose %s", features.ComposeVersion)
+	} else {
+		report.add(CheckCompose, CheckStatusWarn, "compose is not installed: sidecars can't be run locally")
+	}
+	if !features.Buildx {
+		report.add(CheckEmulation, CheckStatusWarn, "buildx is required to build for other platforms")
+		return
+	}
+	d.checkEmulation(ctx, report)
+}
+
+func (d *doctor) checkEmulation(ctx context.Context, report *DoctorReport) {
+	builders, err := d.client.ListBuilders(ctx)
+	if err != nil {
+		report.add(CheckEmulation, CheckStatusWarn, "could not list the buildx builders: %v", err)
+		return
+	}
+	var current *Builder
+	for i := range builders {
+		if builders[i].Current {
+			current = &builders[i]
+			break
+		}
+	}
+	if current == nil {
+		report.add(CheckEmulation, CheckStatusWarn, "no buildx builder is in use")
+		return
+	}
+	supported := make(map[string]bool)
+	for _, p := range current.Platforms {
+		supported[p] = true
+	}
+	var missing []string
+	for _, p := range doctorPlatforms {
+		if !supported[p] {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		report.add(CheckEmulation, CheckStatusWarn, "builder %s can't build for %s: install emulators with `docker run --privileged --rm tonistiigi/binfmt --install all`",
+			current.Name, strings.Join(missing, ", "))
+		return
+	}
+	report.add(CheckEmulation, CheckStatusPass, "builder %s can build for %s", current.Name, strings.Join(doctorPlatforms, ", "))
+}
+
+// checkDiskSpace checks the free space of the filesystem where docker stores its data.
+func (d *doctor) checkDiskSpace(report *DoctorReport) {
+	path := d.client.dataPath()
+	free, err := d.freeSpace(path)
+	if err != nil {
+		report.add(CheckDiskSpace, CheckStatusWarn, "could not get the free disk space of %s: %v", path, err)
+		return
+	}
+	if free < lowDiskSpaceThreshold {
+		report.add(CheckDiskSpace, CheckStatusWarn, "only %s free on %s: builds may fail, consider running `docker system prune`", humanize.IBytes(free), path)
+		return
+	}
+	report.add(CheckDiskSpace, CheckStatusPass, "%s free on %s", humanize.IBytes(free), path)
+}
+
+// checkCredentialHelpers checks that the credential helpers configured in the docker config file are installed.
+func (d *doctor) checkCredentialHelpers(report *DoctorReport) {
+	path := filepath.Join(d.client.homePath, ".docker", dockerConfigFile)
+	if d.client.configDir != "" {
+		path = filepath.Join(d.client.configDir, dockerConfigFile)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			report.add(CheckCredentialHelpers, CheckStatusPass, "no credential helpers configured")
+			return
+		}
+		report.add(CheckCredentialHelpers, CheckStatusWarn, "could not read %s: %v", path, err)
+		return
+	}
+	config, err := parseCredFromDockerConfig(content)
+	if err != nil {
+		report.add(CheckCredentialHelpers, CheckStatusWarn, "could not parse %s: %v", path, err)
+		return
+	}
+	helpers := make(map[string]bool)
+	if config.CredsStore != "" {
+		helpers[config.CredsStore] = true
+	}
+	for _, helper := range config.CredHelpers {
+		helpers[helper] = true
+	}
+	if len(helpers) == 0 {
+		report.add(CheckCredentialHelpers, CheckStatusPass, "no credential helpers configured")
+		return
+	}
+	var names, missing []string
+	for helper := range helpers {
+		names = append(names, helper)
+	}
+	sort.Strings(names)
+	for _, helper := range names {
+		if _, err := d.lookPath("docker-credential-" + helper); err != nil {
+			missing = append(missing, "docker-credential-"+helper)
+		}
+	}
+	if len(missing) > 0 {
+		report.add(CheckCredentialHelpers, CheckStatusFail, "%s configured in %s but not found in $PATH", strings.Join(missing, ", "), path)
+		return
+	}
+	report.add(CheckCredentialHelpers, CheckStatusPass, "found %s", strings.Join(names, ", "))
+}
+
+// checkECRConnectivity checks that the ECR endpoint of the region in the environment is reachable.
+func (d *doctor) checkECRConnectivity(ctx context.Context, report *DoctorReport) {
+	region, ok := d.client.lookupEnv("AWS_REGION")
+	if !ok || region == "" {
+		region, ok = d.client.lookupEnv("AWS_DEFAULT_REGION")
+	}
+	if !ok || region == "" {
+		report.add(CheckECRConnectivity, CheckStatusSkip, "no region set in AWS_REGION or AWS_DEFAULT_REGION")
+		return
+	}
+	endpoint := fmt.Sprintf("api.ecr.%s.amazonaws.com:443", region)
+	conn, err := d.dial(ctx, "tcp", endpoint)
+	if err != nil {
+		report.add(CheckECRConnectivity, CheckStatusFail, "could not connect to %s: %v", endpoint, err)
+		return
+	}
+	_ = conn.Close()
+	report.add(CheckECRConnectivity, CheckStatusPass, "connected to %s", endpoint)
+}
@@ -0,0 +1,192 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Doctor(t *testing.T) {
+	ctx := context.Background()
+	infoArgs := []string{"info", "--format", `{"ServerVersion":{{json .ServerVersion}},"Plugins":{{json .ClientInfo.Plugins}}}`}
+	healthyDocker := func(m *MockCmd) {
+		m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+			Do(mockStdout(`{"ServerVersion":"24.0.5","Plugins":[{"Name":"buildx","Version":"v0.13.1"},{"Name":"compose","Version":"v2.20.2"}]}`)).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "version"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("github.com/docker/buildx v0.13.1 788433953af10f2a698f5c07611dddce2e08c7a0\n")).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "ls", "--format", "json"}, gomock.Any()).
+			Do(mockStdout(`{"Name":"default","Driver":"docker","Current":true,"Nodes":[{"Status":"running","Platforms":["linux/amd64","linux/arm64"]}]}` + "\n")).Return(nil)
+	}
+
+	tests := map[string]struct {
+		dockerConfig string
+		env          map[string]string
+		inPath       map[string]bool
+		freeSpace    uint64
+		dialErr      error
+		setupMocks   func(m *MockCmd)
+
+		wantedStatus CheckStatus
+		wantedChecks map[string]CheckStatus
+	}{
+		"passes on a healthy installation": {
+			dockerConfig: `{"credsStore":"ecr-login"}`,
+			env:          map[string]string{"AWS_REGION": "us-west-2"},
+			inPath:       map[string]bool{"docker": true, "docker-credential-ecr-login": true},
+			freeSpace:    20 << 30,
+			setupMocks:   healthyDocker,
+
+			wantedStatus: CheckStatusPass,
+			wantedChecks: map[string]CheckStatus{
+				CheckDockerBinary:      CheckStatusPass,
+				CheckDaemon:            CheckStatusPass,
+				CheckServerVersion:     CheckStatusPass,
+				CheckBuildx:            CheckStatusPass,
+				CheckCompose:           CheckStatusPass,
+				CheckEmulation:         CheckStatusPass,
+				CheckDiskSpace:         CheckStatusPass,
+				CheckCredentialHelpers: CheckStatusPass,
+				CheckECRConnectivity:   CheckStatusPass,
+			},
+		},
+		"skips daemon checks if the docker command is missing": {
+			freeSpace:  20 << 30,
+			setupMocks: func(m *MockCmd) {},
+
+			wantedStatus: CheckStatusFail,
+			wantedChecks: map[string]CheckStatus{
+				CheckDockerBinary:      CheckStatusFail,
+				CheckDaemon:            CheckStatusSkip,
+				CheckServerVersion:     CheckStatusSkip,
+				CheckBuildx:            CheckStatusSkip,
+				CheckCompose:           CheckStatusSkip,
+				CheckEmulation:         CheckStatusSkip,
+				CheckDiskSpace:         CheckStatusPass,
+				CheckCredentialHelpers: CheckStatusPass,
+				CheckECRConnectivity:   CheckStatusSkip,
+			},
+		},
+		"fails if the daemon is not reachable": {
+			inPath:    map[string]bool{"docker": true},
+			freeSpace: 20 << 30,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Return(errors.New("exit status 1"))
+			},
+
+			wantedStatus: CheckStatusFail,
+			wantedChecks: map[string]CheckStatus{
+				CheckDockerBinary:      CheckStatusPass,
+				CheckDaemon:            CheckStatusFail,
+				CheckServerVersion:     CheckStatusSkip,
+				CheckBuildx:            CheckStatusSkip,
+				CheckCompose:           CheckStatusSkip,
+				CheckEmulation:         CheckStatusSkip,
+				CheckDiskSpace:         CheckStatusPass,
+				CheckCredentialHelpers: CheckStatusPass,
+				CheckECRConnectivity:   CheckStatusSkip,
+			},
+		},
+		"warns on an old installation with little disk space": {
+			inPath:    map[string]bool{"docker": true},
+			freeSpace: 1 << 30,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`{"ServerVersion":"19.03.1","Plugins":null}`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "version"}, gomock.Any(), gomock.Any()).
+					Return(errors.New("'buildx' is not a docker command"))
+			},
+
+			wantedStatus: CheckStatusWarn,
+			wantedChecks: map[string]CheckStatus{
+				CheckDockerBinary:      CheckStatusPass,
+				CheckDaemon:            CheckStatusPass,
+				CheckServerVersion:     CheckStatusWarn,
+				CheckBuildx:            CheckStatusWarn,
+				CheckCompose:           CheckStatusWarn,
+				CheckEmulation:         CheckStatusWarn,
+				CheckDiskSpace:         CheckStatusWarn,
+				CheckCredentialHelpers: CheckStatusPass,
+				CheckECRConnectivity:   CheckStatusSkip,
+			},
+		},
+		"fails if a credential helper is missing or ECR is not reachable": {
+			dockerConfig: `{"credHelpers":{"123456789012.dkr.ecr.us-west-2.amazonaws.com":"ecr-login"}}`,
+			env:          map[string]string{"AWS_DEFAULT_REGION": "us-west-2"},
+			inPath:       map[string]bool{"docker": true},
+			freeSpace:    20 << 30,
+			dialErr:      errors.New("i/o timeout"),
+			setupMocks:   healthyDocker,
+
+			wantedStatus: CheckStatusFail,
+			wantedChecks: map[string]CheckStatus{
+				CheckDockerBinary:      CheckStatusPass,
+				CheckDaemon:            CheckStatusPass,
+				CheckServerVersion:     CheckStatusPass,
+				CheckBuildx:            CheckStatusPass,
+				CheckCompose:           CheckStatusPass,
+				CheckEmulation:         CheckStatusPass,
+				CheckDiskSpace:         CheckStatusPass,
+				CheckCredentialHelpers: CheckStatusFail,
+				CheckECRConnectivity:   CheckStatusFail,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			home := t.TempDir()
+			if tc.dockerConfig != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(home, ".docker"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(home, ".docker", "config.json"), []byte(tc.dockerConfig), 0644))
+			}
+			d := &doctor{
+				client: DockerCmdClient{
+					runner:   m,
+					homePath: home,
+					lookupEnv: func(key string) (string, bool) {
+						v, ok := tc.env[key]
+						return v, ok
+					},
+				},
+				lookPath: func(file string) (string, error) {
+					if tc.inPath[file] {
+						return "/usr/local/bin/" + file, nil
+					}
+					return "", errors.New("executable file not found in $PATH")
+				},
+				dial: func(_ context.Context, _, address string) (net.Conn, error) {
+					require.Equal(t, "api.ecr.us-west-2.amazonaws.com:443", address)
+					if tc.dialErr != nil {
+						return nil, tc.dialErr
+					}
+					client, server := net.Pipe()
+					_ = server.Close()
+					return client, nil
+				},
+				freeSpace: func(string) (uint64, error) {
+					return tc.freeSpace, nil
+				},
+			}
+
+			report := d.run(ctx)
+
+			require.Equal(t, tc.wantedStatus, report.Status)
+			got := make(map[string]CheckStatus)
+			for _, check := range report.Checks {
+				got[check.Name] = check.Status
+			}
+			require.Equal(t, tc.wantedChecks, got)
+		})
+	}
+}
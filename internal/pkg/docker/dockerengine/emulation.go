@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+)
+
+// binfmtImage is the image that reports and installs the QEMU emulators registered with the kernel.
+const binfmtImage = "tonistiigi/binfmt"
+
+// ErrEmulationNotSupported means the docker daemon can't build or run images for a platform other than its own.
+type ErrEmulationNotSupported struct {
+	Platform       string // Platform of the image, for example "linux/arm64".
+	DaemonPlatform string // Platform of the docker daemon, for example "linux/amd64".
+}
+
+func (e *ErrEmulationNotSupported) Error() string {
+	return fmt.Sprintf("docker daemon on %s can't build for %s: no QEMU emulator is registered for %s", e.DaemonPlatform, e.Platform, e.Platform)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrEmulationNotSupported) RecommendActions() string {
+	return fmt.Sprintf("Install the QEMU emulators with `docker run --privileged --rm %s --install all`, or build the image on a %s host.", binfmtImage, e.Platform)
+}
+
+// checkEmulation makes sure that the daemon can build for the platform of the image when it differs from its own.
+// If emulation is missing, it installs the emulator when in.InstallQEMU is set and returns ErrEmulationNotSupported otherwise.
+// The check is best-effort: if the daemon can't be probed, the build is left to report the problem.
+func (c DockerCmdClient) checkEmulation(ctx context.Context, in *BuildArguments) error {
+	if !strings.Contains(in.Platform, "/") {
+		return nil
+	}
+	daemon, err := c.serverPlatform(ctx)
+	if err != nil || samePlatform(daemon, in.Platform) {
+		return nil
+	}
+	supported, err := c.emulatedPlatforms(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, p := range supported {
+		if samePlatform(p, in.Platform) {
+			return nil
+		}
+	}
+	if !in.InstallQEMU {
+		return &ErrEmulationNotSupported{
+			Platform:       in.Platform,
+			DaemonPlatform: daemon,
+		}
+	}
+	log.Infof("Installing the QEMU emulator for %s to build on %s.\n", in.Platform, daemon)
+	arch := strings.Split(in.Platform, "/")[1]
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"run", "--privileged", "--rm", binfmtImage, "--install", arch},
+		exec.Stdout(&bytes.Buffer{}), exec.Stderr(&bytes.Buffer{})); err != nil {
+		return fmt.Errorf("install QEMU emulator for %s: %w", in.Platform, err)
+	}
+	return nil
+}
+
+// serverPlatform returns the platform of the docker daemon, for example "linux/amd64".
+func (c DockerCmdClient) serverPlatform(ctx context.Context) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"version", "--format", "{{.Server.Os}}/{{.Server.Arch}}"}, exec.Stdout(buf)); err != nil {
+		return "", fmt.Errorf("get docker server platform: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// emulatedPlatforms returns the platforms that the kernel of the docker daemon can run, natively or with an emulator.
+func (c DockerCmdClient) emulatedPlatforms(ctx context.Context) ([]string, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"run", "--privileged", "--rm", binfmtImage},
+		exec.Stdout(buf), exec.Stderr(&bytes.Buffer{})); err != nil {
+		return nil, fmt.Errorf("get emulators: %w", err)
+	}
+	var out struct {
+		Supported []string `json:"supported"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("unmarshal emulators: %w", err)
+	}
+	return out.Supported, nil
+}
+
+// samePlatform returns true if the platforms have the same OS and architecture, ignoring the variant.
+func samePlatform(a, b string) bool {
+	splitsA, splitsB := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(splitsA) < 2 || len(splitsB) < 2 {
+		return false
+	}
+	return splitsA[0] == splitsB[0] && splitsA[1] == splitsB[1]
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_checkEmulation(t *testing.T) {
+	ctx := context.Background()
+	versionArgs := []string{"version", "--format", "{{.Server.Os}}/{{.Server.Arch}}"}
+	probeArgs := []string{"run", "--privileged", "--rm", "tonistiigi/binfmt"}
+
+	tests := map[string]struct {
+		in         *BuildArguments
+		setupMocks func(m *MockCmd)
+
+		wantedErr error
+	}{
+		"skips the check without a platform": {
+			in:         &BuildArguments{},
+			setupMocks: func(m *MockCmd) {},
+		},
+		"passes if the daemon runs on the same platform": {
+			in: &BuildArguments{Platform: "linux/arm64/v8"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", versionArgs, gomock.Any()).Do(mockStdout("linux/arm64\n")).Return(nil)
+			},
+		},
+		"passes if an emulator is registered for the platform": {
+			in: &BuildArguments{Platform: "linux/arm64"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", versionArgs, gomock.Any()).Do(mockStdout("linux/amd64\n")).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", probeArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"supported":["linux/amd64","linux/arm64","linux/386"],"emulators":["qemu-aarch64"]}`)).Return(nil)
+			},
+		},
+		"returns ErrEmulationNotSupported if no emulator is registered": {
+			in: &BuildArguments{Platform: "linux/arm64"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", versionArgs, gomock.Any()).Do(mockStdout("linux/amd64\n")).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", probeArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"supported":["linux/amd64","linux/386"],"emulators":null}`)).Return(nil)
+			},
+			wantedErr: &ErrEmulationNotSupported{
+				Platform:       "linux/arm64",
+				DaemonPlatform: "linux/amd64",
+			},
+		},
+		"installs the emulator if requested": {
+			in: &BuildArguments{Platform: "linux/arm64", InstallQEMU: true},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", versionArgs, gomock.Any()).Do(mockStdout("linux/amd64\n")).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", probeArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"supported":["linux/amd64"],"emulators":null}`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"run", "--privileged", "--rm", "tonistiigi/binfmt", "--install", "arm64"}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"leaves it to the build if the daemon can't be probed": {
+			in: &BuildArguments{Platform: "linux/arm64"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", versionArgs, gomock.Any()).Do(mockStdout("linux/amd64\n")).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", probeArgs, gomock.Any(), gomock.Any()).Return(errors.New("pull access denied"))
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			err := s.checkEmulation(ctx, tc.in)
+
+			if tc.wantedErr != nil {
+				require.Equal(t, tc.wantedErr, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -6,11 +6,19 @@ package dockerengine
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
 )
 
 // ErrDockerCommandNotFound means the docker command is not found.
 var ErrDockerCommandNotFound = errors.New("docker: command not found")
 
+// ErrSquashNotSupported means the docker daemon cannot squash image layers.
+var ErrSquashNotSupported = errors.New("docker daemon does not support --squash: enable experimental features on the daemon to squash image layers")
+
+var errMissingStdin = errors.New(`stdin must be provided to read the Dockerfile or the build context from "-"`)
+
 // ErrDockerDaemonNotResponsive means the docker daemon is not responsive.
 type ErrDockerDaemonNotResponsive struct {
 	msg string
@@ -19,3 +27,77 @@ type ErrDockerDaemonNotResponsive struct {
 func (e ErrDockerDaemonNotResponsive) Error() string {
 	return fmt.Sprintf("docker daemon is not responsive: %s", e.msg)
 }
+
+type errNotOCILayout struct {
+	path string
+	err  error
+}
+
+func (e *errNotOCILayout) Error() string {
+	return fmt.Sprintf("%s is not an OCI image layout directory: %v", e.path, e.err)
+}
+
+func (e *errNotOCILayout) Unwrap() error {
+	return e.err
+}
+
+// ErrBuildContextTooLarge means the build context exceeds the maximum size allowed for a build.
+type ErrBuildContextTooLarge struct {
+	Dir   string
+	Stats BuildContextStats
+	Limit int64
+}
+
+func (e *ErrBuildContextTooLarge) Error() string {
+	return fmt.Sprintf("build context %s is %s (%d files) which exceeds the limit of %s",
+		e.Dir, humanize.IBytes(uint64(e.Stats.Size)), e.Stats.Files, humanize.IBytes(uint64(e.Limit)))
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrBuildContextTooLarge) RecommendActions() string {
+	return fmt.Sprintf("Add the files that are not needed to build the image to %s in %s.", dockerignoreFile, e.Dir)
+}
+
+// ErrInvalidTarget means the target build stage is not declared in the Dockerfile.
+type ErrInvalidTarget struct {
+	Target     string
+	Dockerfile string
+	Stages     []string // Names of the stages declared in the Dockerfile.
+}
+
+func (e *ErrInvalidTarget) Error() string {
+	if len(e.Stages) == 0 {
+		return fmt.Sprintf("target stage %q not found in %s: the Dockerfile has no named stages", e.Target, e.Dockerfile)
+	}
+	return fmt.Sprintf("target stage %q not found in %s: valid targets are %s", e.Target, e.Dockerfile, strings.Join(e.Stages, ", "))
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrInvalidTarget) RecommendActions() string {
+	return fmt.Sprintf("Set the target to one of the stages named with `FROM <image> AS <name>` in %s.", e.Dockerfile)
+}
+
+// ErrTagAlreadyExists means an image can't be pushed because its tag already exists in a repository with immutable tags.
+type ErrTagAlreadyExists struct {
+	Image string
+	err   error
+}
+
+func (e *ErrTagAlreadyExists) Error() string {
+	return fmt.Sprintf("tag of image %s already exists in the repository and tags are immutable: %v", e.Image, e.err)
+}
+
+func (e *ErrTagAlreadyExists) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrTagAlreadyExists) RecommendActions() string {
+	return "Push the image with a new tag, for example the commit of the build, or deploy the image by digest instead of by tag."
+}
+
+// isImmutableTagConflict returns true if the output of docker push indicates that the tag exists in a repository with immutable tags.
+func isImmutableTagConflict(output string) bool {
+	return strings.Contains(output, "cannot be overwritten because the repository is immutable") ||
+		strings.Contains(output, "ImageTagAlreadyExistsException")
+}
@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// PlannedFakeTimeLibPath stands for the path of libfaketime in the commands of a plan when it depends on the architecture of the image.
+	PlannedFakeTimeLibPath = "<libfaketime>"
+
+	fakeTimeMountPath = "/opt/copilot/libfaketime.so.1"
+)
+
+// fakeTimeLibDirs are the multiarch directories of libfaketime in Debian and Ubuntu based images with the faketime package installed,
+// keyed by architecture.
+var fakeTimeLibDirs = map[string]string{
+	ArchAMD64: "x86_64-linux-gnu",
+	ArchX86:   "x86_64-linux-gnu",
+	ArchARM64: "aarch64-linux-gnu",
+	"aarch64": "aarch64-linux-gnu",
+	ArchARM:   "arm-linux-gnueabihf",
+}
+
+// FakeTime configures libfaketime in a container so that processes observe a shifted clock,
+// for example to test certificate expiry or scheduled logic at a simulated future date.
+type FakeTime struct {
+	Offset time.Duration // Required. Offset applied to the clock of the container. Negative values move the clock to the past.
+
+	// Optional. Path of libfaketime on the host, mounted read-only into the container.
+	// If empty, the image must provide the library at ContainerLibPath.
+	HostLibPath string
+
+	// Optional. Path of libfaketime in the container. Defaults to a Copilot-managed path if HostLibPath is set.
+	// Otherwise, defaults to the path of the faketime package of Debian and Ubuntu for the architecture of the container,
+	// which is the one of its platform, or the one of its image if the platform isn't set.
+	ContainerLibPath string
+}
+
+// fakeTimeLibPath returns the path of libfaketime in Debian and Ubuntu based images for the architecture, or false if it's unknown.
+func fakeTimeLibPath(arch string) (string, bool) {
+	dir, ok := fakeTimeLibDirs[arch]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("/usr/lib/%s/faketime/libfaketime.so.1", dir), true
+}
+
+// withFakeTimeLib returns a copy of the options whose FakeTime has the path of libfaketime in the container,
+// if it must be derived from the architecture of the container.
+func (c DockerCmdClient) withFakeTimeLib(ctx context.Context, opts *RunOptions) (*RunOptions, error) {
+	ft := opts.FakeTime
+	if ft == nil || ft.ContainerLibPath != "" || ft.HostLibPath != "" {
+		return opts, nil
+	}
+	arch := platformArch(opts.Platform)
+	if arch == "" {
+		img, err := c.inspectImage(ctx, opts.ImageURI)
+		if err != nil {
+			return nil, fmt.Errorf("get the architecture of image %s for libfaketime: %w", opts.ImageURI, err)
+		}
+		arch = img.Architecture
+	}
+	path, ok := fakeTimeLibPath(arch)
+	if !ok {
+		return nil, fmt.Errorf("unknown path of libfaketime for architecture %q: set the path of the library in the container", arch)
+	}
+	resolved := *ft
+	resolved.ContainerLibPath = path
+	out := *opts
+	out.FakeTime = &resolved
+	return &out, nil
+}
+
+// platformArch returns the architecture of a platform like "linux/arm64/v8", or an empty string if it's not set.
+func platformArch(platform string) string {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// runArgs returns the docker run arguments to preload libfaketime with the offset.
+// If the path of libfaketime depends on the architecture of the image, it's derived from the platform of the container,
+// or rendered as PlannedFakeTimeLibPath if the platform isn't set.
+func (ft *FakeTime) runArgs(platform string) []string {
+	libPath := ft.ContainerLibPath
+	var args []string
+	if ft.HostLibPath != "" {
+		if libPath == "" {
+			libPath = fakeTimeMountPath
+		}
+		args = append(args, "--volume", fmt.Sprintf("%s:%s:ro", ft.HostLibPath, libPath))
+	}
+	if libPath == "" {
+		var ok bool
+		if libPath, ok = fakeTimeLibPath(platformArch(platform)); !ok {
+			libPath = PlannedFakeTimeLibPath
+		}
+	}
+	return append(args,
+		"--env", "LD_PRELOAD="+libPath,
+		"--env", fmt.Sprintf("FAKETIME=%+d", int64(ft.Offset/time.Second)),
+		// Faking the monotonic clock can hang runtimes that rely on it for timers.
+		"--env", "FAKETIME_DONT_FAKE_MONOTONIC=1",
+	)
+}
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// FanOutWriter streams the output of a docker operation to several destinations at once, for example the terminal,
+// a log file, and a JSON event stream.
+// A destination that fails to write is dropped and its error is recorded, while the others keep receiving the output.
+// Write never fails, so that a broken destination, such as a log file on a full disk, doesn't abort the docker operation.
+type FanOutWriter struct {
+	mu    sync.Mutex
+	dests []*fanOutDest
+}
+
+type fanOutDest struct {
+	name string
+	w    io.Writer
+	err  error
+}
+
+// NewFanOutWriter returns a FanOutWriter without any destination.
+func NewFanOutWriter() *FanOutWriter {
+	return &FanOutWriter{}
+}
+
+// Add adds a destination. The name identifies the destination in the errors.
+func (f *FanOutWriter) Add(name string, w io.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dests = append(f.dests, &fanOutDest{
+		name: name,
+		w:    w,
+	})
+}
+
+// Write writes p to each destination that hasn't failed yet.
+func (f *FanOutWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, dest := range f.dests {
+		if dest.err != nil {
+			continue
+		}
+		n, err := dest.w.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		dest.err = err
+	}
+	return len(p), nil
+}
+
+// Err returns the errors of the destinations that failed to write, or nil if all of them succeeded.
+func (f *FanOutWriter) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var errs []error
+	for _, dest := range f.dests {
+		if dest.err != nil {
+			errs = append(errs, fmt.Errorf("write output to %s: %w", dest.name, dest.err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OutputEvent is a line of output of a docker operation as written by JSONLineWriter.
+type OutputEvent struct {
+	Time  time.Time `json:"time"`
+	Image string    `json:"image"`
+	Line  string    `json:"line"`
+}
+
+// JSONLineWriter is an io.Writer that writes each complete line of output as an OutputEvent JSON object followed by a newline.
+type JSONLineWriter struct {
+	enc   *json.Encoder
+	image string
+	buf   bytes.Buffer
+
+	now func() time.Time // Override in unit tests.
+}
+
+// NewJSONLineWriter returns a JSONLineWriter that writes the events of the image to w.
+func NewJSONLineWriter(w io.Writer, image string) *JSONLineWriter {
+	return &JSONLineWriter{
+		enc:   json.NewEncoder(w),
+		image: image,
+		now:   time.Now,
+	}
+}
+
+// Write buffers p and writes an event for each complete line.
+func (w *JSONLineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			return len(p), nil
+		}
+		line := w.buf.Next(idx + 1)
+		if err := w.emit(string(bytes.TrimRight(line, "\r\n"))); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes an event for the remaining incomplete line, if any.
+func (w *JSONLineWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	defer w.buf.Reset()
+	return w.emit(w.buf.String())
+}
+
+func (w *JSONLineWriter) emit(line string) error {
+	return w.enc.Encode(OutputEvent{
+		Time:  w.now().UTC(),
+		Image: w.image,
+		Line:  line,
+	})
+}
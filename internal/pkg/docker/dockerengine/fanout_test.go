@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	writes int
+	failAt int // Fail from the nth write.
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes >= w.failAt {
+		return 0, errors.New("no space left on device")
+	}
+	return len(p), nil
+}
+
+func TestFanOutWriter(t *testing.T) {
+	terminal, events := &strings.Builder{}, &strings.Builder{}
+	logFile := &failingWriter{failAt: 2}
+	w := NewFanOutWriter()
+	w.Add("terminal", terminal)
+	w.Add("log file", logFile)
+	w.Add("events", events)
+
+	for _, line := range []string{"Step 1/2\n", "Step 2/2\n", "Done\n"} {
+		n, err := fmt.Fprint(w, line)
+		require.NoError(t, err, "a failing destination must not fail the writer")
+		require.Equal(t, len(line), n)
+	}
+
+	require.Equal(t, "Step 1/2\nStep 2/2\nDone\n", terminal.String())
+	require.Equal(t, "Step 1/2\nStep 2/2\nDone\n", events.String())
+	require.Equal(t, 2, logFile.writes, "the failed destination must not be written to again")
+	require.EqualError(t, w.Err(), "write output to log file: no space left on device")
+}
+
+func TestJSONLineWriter(t *testing.T) {
+	out := &strings.Builder{}
+	w := NewJSONLineWriter(out, "web:latest")
+	w.now = func() time.Time {
+		return time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	_, err := w.Write([]byte("Step 1/2\r\nStep "))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("2/2"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+
+	require.Equal(t, `{"time":"2023-07-01T12:00:00Z","image":"web:latest","line":"Step 1/2"}
+{"time":"2023-07-01T12:00:00Z","image":"web:latest","line":"Step 2/2"}
+`, out.String())
+}
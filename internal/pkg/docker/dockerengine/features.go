@@ -0,0 +1,118 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// minBuildKitDefaultServerVersion is the first docker version that builds with BuildKit by default.
+	minBuildKitDefaultServerVersion = "v23.0.0"
+	// minAttestationsBuildxVersion is the first buildx version that can attach provenance and SBOM attestations.
+	minAttestationsBuildxVersion = "v0.10.0"
+)
+
+// Features describes the capabilities of the docker installation, so that callers can degrade gracefully when one is missing.
+type Features struct {
+	BuildKit       bool   // True if `docker build` builds with BuildKit.
+	Buildx         bool   // True if the buildx plugin is installed.
+	BuildxVersion  string // For example "v0.11.2". Empty if buildx is not installed.
+	Compose        bool   // True if the compose plugin is installed.
+	ComposeVersion string // For example "v2.20.2". Empty if compose is not installed.
+	Attestations   bool   // True if builds can attach provenance and SBOM attestations.
+}
+
+// Features probes the docker CLI and daemon for the capabilities they support.
+func (c DockerCmdClient) Features(ctx context.Context) (*Features, error) {
+	info, err := c.dockerInfoPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.features(ctx, info), nil
+}
+
+// features returns the capabilities of the docker installation given the output of `docker info`.
+func (c DockerCmdClient) features(ctx context.Context, info *dockerInfo) *Features {
+	features := &Features{}
+	if buildx, ok := c.buildxVersion(ctx); ok {
+		features.Buildx = true
+		features.BuildxVersion = buildx
+	} else if v, ok := info.plugins["buildx"]; ok {
+		features.Buildx = true
+		features.BuildxVersion = v
+	}
+	if v, ok := info.plugins["compose"]; ok {
+		features.Compose = true
+		features.ComposeVersion = v
+	}
+	features.BuildKit = features.Buildx && semver.Compare(canonicalVersion(info.serverVersion), minBuildKitDefaultServerVersion) >= 0
+	if v, ok := c.lookupEnv("DOCKER_BUILDKIT"); ok {
+		// The environment variable overrides the default builder of the daemon.
+		features.BuildKit = v == "1" || strings.EqualFold(v, "true")
+	}
+	features.Attestations = features.Buildx && semver.Compare(canonicalVersion(features.BuildxVersion), minAttestationsBuildxVersion) >= 0
+	return features
+}
+
+type dockerInfo struct {
+	serverVersion string
+	plugins       map[string]string // Versions of the CLI plugins keyed by name.
+}
+
+func (c DockerCmdClient) dockerInfoPlugins(ctx context.Context) (*dockerInfo, error) {
+	buf := &bytes.Buffer{}
+	format := `{"ServerVersion":{{json .ServerVersion}},"Plugins":{{json .ClientInfo.Plugins}}}`
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"info", "--format", format}, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("get docker info: %w", err)
+	}
+	var out struct {
+		ServerVersion string `json:"ServerVersion"`
+		Plugins       []struct {
+			Name    string `json:"Name"`
+			Version string `json:"Version"`
+		} `json:"Plugins"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &out); err != nil {
+		return nil, fmt.Errorf("unmarshal docker info: %w", err)
+	}
+	info := &dockerInfo{
+		serverVersion: out.ServerVersion,
+		plugins:       make(map[string]string),
+	}
+	for _, p := range out.Plugins {
+		info.plugins[p.Name] = p.Version
+	}
+	return info, nil
+}
+
+// buildxVersion returns the version of buildx, for example "v0.11.2", and false if buildx is not installed.
+func (c DockerCmdClient) buildxVersion(ctx context.Context) (string, bool) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"buildx", "version"}, exec.Stdout(buf), exec.Stderr(&bytes.Buffer{})); err != nil {
+		return "", false
+	}
+	// The output looks like "github.com/docker/buildx v0.11.2 9872040b6626fb7d87ef7296fd5b832e8cc2ad17".
+	fields := strings.Fields(buf.String())
+	if len(fields) < 2 {
+		return "", true
+	}
+	return fields[1], true
+}
+
+// canonicalVersion adds the "v" prefix expected by the semver package and drops suffixes such as "+azure-1".
+func canonicalVersion(v string) string {
+	v, _, _ = strings.Cut(v, "+")
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
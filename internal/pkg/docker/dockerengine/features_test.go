@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Features(t *testing.T) {
+	ctx := context.Background()
+	infoArgs := []string{"info", "--format", `{"ServerVersion":{{json .ServerVersion}},"Plugins":{{json .ClientInfo.Plugins}}}`}
+
+	tests := map[string]struct {
+		env        map[string]string
+		setupMocks func(m *MockCmd)
+
+		wanted    *Features
+		wantedErr string
+	}{
+		"detects all features on a recent installation": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`{"ServerVersion":"24.0.5","Plugins":[{"Name":"buildx","Version":"v0.11.2"},{"Name":"compose","Version":"v2.20.2"}]}`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "version"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("github.com/docker/buildx v0.11.2 9872040b6626fb7d87ef7296fd5b832e8cc2ad17\n")).Return(nil)
+			},
+			wanted: &Features{
+				BuildKit:       true,
+				Buildx:         true,
+				BuildxVersion:  "v0.11.2",
+				Compose:        true,
+				ComposeVersion: "v2.20.2",
+				Attestations:   true,
+			},
+		},
+		"degrades on an old installation without plugins": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`{"ServerVersion":"20.10.24","Plugins":null}`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "version"}, gomock.Any(), gomock.Any()).
+					Return(errors.New("'buildx' is not a docker command"))
+			},
+			wanted: &Features{},
+		},
+		"honors DOCKER_BUILDKIT": {
+			env: map[string]string{"DOCKER_BUILDKIT": "1"},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`{"ServerVersion":"20.10.24","Plugins":[{"Name":"buildx","Version":"v0.9.1"}]}`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"buildx", "version"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("github.com/docker/buildx v0.9.1 ed00243\n")).Return(nil)
+			},
+			wanted: &Features{
+				BuildKit:      true,
+				Buildx:        true,
+				BuildxVersion: "v0.9.1",
+			},
+		},
+		"returns a wrapped error if docker info fails": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "get docker info: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(key string) (string, bool) {
+					v, ok := tc.env[key]
+					return v, ok
+				},
+			}
+
+			got, err := s.Features(ctx)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HealthCheck holds the health check of a container, in the format of the health check of an ECS container definition.
+type HealthCheck struct {
+	// Required. The command to run in the container, for example []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}.
+	// The first element is "CMD-SHELL" to run the rest of the command with the shell, "CMD" to run it directly,
+	// or "NONE" to disable the health check of the image.
+	Command     []string
+	Interval    time.Duration // Optional. Time between two checks.
+	Timeout     time.Duration // Optional. Time after which a check is considered failed.
+	Retries     int           // Optional. Number of consecutive failed checks for the container to be unhealthy.
+	StartPeriod time.Duration // Optional. Time for the container to start before failed checks count towards the retries.
+}
+
+// ErrNoHealthCheck means a container can't become healthy because it has no health check.
+type ErrNoHealthCheck struct {
+	Container string
+}
+
+func (e *ErrNoHealthCheck) Error() string {
+	return fmt.Sprintf("container %s has no health check", e.Container)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrNoHealthCheck) RecommendActions() string {
+	return "Add a HEALTHCHECK instruction to the Dockerfile of the image, or run the container with a health check."
+}
+
+func (h *HealthCheck) runArgs() []string {
+	if len(h.Command) == 0 {
+		return nil
+	}
+	command := h.Command
+	switch command[0] {
+	case "NONE":
+		return []string{"--no-healthcheck"}
+	case "CMD":
+		// docker run only takes a command that is run with the shell, so each argument is quoted to be passed as is.
+		quoted := make([]string, len(command)-1)
+		for i, arg := range command[1:] {
+			quoted[i] = shellQuote(arg)
+		}
+		command = quoted
+	case "CMD-SHELL":
+		command = command[1:]
+	}
+	args := []string{"--health-cmd", strings.Join(command, " ")}
+	if h.Interval != 0 {
+		args = append(args, "--health-interval", h.Interval.String())
+	}
+	if h.Timeout != 0 {
+		args = append(args, "--health-timeout", h.Timeout.String())
+	}
+	if h.Retries != 0 {
+		args = append(args, "--health-retries", fmt.Sprintf("%d", h.Retries))
+	}
+	if h.StartPeriod != 0 {
+		args = append(args, "--health-start-period", h.StartPeriod.String())
+	}
+	return args
+}
+
+// shellQuote quotes s for a POSIX shell if it contains characters other than letters, digits and a few safe punctuation marks.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// WaitForHealthy waits until the health check of the container passes, like the HEALTHY condition of a container dependency in ECS.
+// It returns an error if the container has no health check, becomes unhealthy, stops, or isn't healthy within the timeout.
+// If the timeout is zero, it waits for 1 minute.
+func (c DockerCmdClient) WaitForHealthy(ctx context.Context, container string, timeout time.Duration) error {
+	w := &healthWaiter{
+		client:             c,
+		pollInterval:       defaultHealthPollInterval,
+		requireHealthCheck: true,
+	}
+	if timeout == 0 {
+		timeout = defaultHealthTimeout
+	}
+	return w.wait(ctx, container, timeout)
+}
+
+type healthWaiter struct {
+	client             DockerCmdClient
+	pollInterval       time.Duration
+	requireHealthCheck bool // If false, a running container without a health check is considered healthy.
+}
+
+// wait polls the status of the container until it's healthy, or running if it has no health check.
+func (w *healthWaiter) wait(ctx context.Context, container string, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := w.client.containerStatus(waitCtx, container)
+		if err != nil && waitCtx.Err() == nil {
+			return err
+		}
+		switch status {
+		case containerStatusHealthy:
+			return nil
+		case containerStatusRunning:
+			// The status of the health check is reported instead of the state of the container if it has one.
+			if w.requireHealthCheck {
+				return &ErrNoHealthCheck{Container: container}
+			}
+			return nil
+		case containerStatusUnhealthy, "exited", "dead":
+			return &ErrContainerUnhealthy{Container: container, Status: status}
+		}
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("wait for container %s to become healthy: %w", container, ctx.Err())
+			}
+			return &ErrContainerUnhealthy{Container: container, Status: status}
+		case <-ticker.C:
+		}
+	}
+}
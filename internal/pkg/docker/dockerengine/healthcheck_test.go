@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck_runArgs(t *testing.T) {
+	tests := map[string]struct {
+		in     HealthCheck
+		wanted []string
+	}{
+		"runs a shell command with all the options": {
+			in: HealthCheck{
+				Command:     []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+				Interval:    10 * time.Second,
+				Timeout:     5 * time.Second,
+				Retries:     2,
+				StartPeriod: time.Minute,
+			},
+			wanted: []string{"--health-cmd", "curl -f http://localhost/ || exit 1", "--health-interval", "10s",
+				"--health-timeout", "5s", "--health-retries", "2", "--health-start-period", "1m0s"},
+		},
+		"joins the arguments of an exec command": {
+			in: HealthCheck{
+				Command: []string{"CMD", "pg_isready", "-U", "postgres"},
+			},
+			wanted: []string{"--health-cmd", "pg_isready -U postgres"},
+		},
+		"quotes the arguments of an exec command": {
+			in: HealthCheck{
+				Command: []string{"CMD", "curl", "-f", "http://localhost/?q=a b", "-H", "X-Name: it's"},
+			},
+			wanted: []string{"--health-cmd", `curl -f 'http://localhost/?q=a b' -H 'X-Name: it'\''s'`},
+		},
+		"disables the health check of the image": {
+			in: HealthCheck{
+				Command: []string{"NONE"},
+			},
+			wanted: []string{"--no-healthcheck"},
+		},
+		"keeps the health check of the image without a command": {
+			in: HealthCheck{
+				Interval: 10 * time.Second,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.wanted, tc.in.runArgs())
+		})
+	}
+}
+
+func TestHealthWaiter_Wait(t *testing.T) {
+	statusArgs := []string{"inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", "db"}
+	tests := map[string]struct {
+		requireHealthCheck bool
+		setupMocks         func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"waits until the container is healthy": {
+			requireHealthCheck: true,
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("starting\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("healthy\n")).Return(nil),
+				)
+			},
+		},
+		"errors if the container has no health check": {
+			requireHealthCheck: true,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil)
+			},
+			wantedErr: "container db has no health check",
+		},
+		"considers a running container without a health check healthy if it's not required": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil)
+			},
+		},
+		"errors if the container exits": {
+			requireHealthCheck: true,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("exited\n")).Return(nil)
+			},
+			wantedErr: `container db did not become healthy: status is "exited"`,
+		},
+		"times out if the container is still starting": {
+			requireHealthCheck: true,
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("starting\n")).Return(nil).AnyTimes()
+			},
+			wantedErr: `container db did not become healthy: status is "starting"`,
+		},
+		"errors if the container can't be inspected": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "inspect container db: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			w := &healthWaiter{
+				client:             DockerCmdClient{runner: m},
+				pollInterval:       time.Millisecond,
+				requireHealthCheck: tc.requireHealthCheck,
+			}
+
+			err := w.wait(context.Background(), "db", 10*time.Millisecond)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
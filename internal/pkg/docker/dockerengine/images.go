@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/dustin/go-humanize"
+)
+
+// imagesCreatedAtFormat is the format of the creation time of images printed by `docker images`.
+const imagesCreatedAtFormat = "2006-01-02 15:04:05 -0700 MST"
+
+// ImageFilters selects the images listed by ListImages.
+type ImageFilters struct {
+	// Optional. Labels that the images must have, keyed by name. An empty value matches any value of the label.
+	Labels map[string]string
+	// Optional. Only lists the untagged images that aren't the parent of other images.
+	Dangling bool
+}
+
+// ImageSummary describes an image of the local image store.
+type ImageSummary struct {
+	Repository string // "<none>" for untagged images.
+	Tag        string // "<none>" for untagged images.
+	Digest     string // "<none>" for images that weren't pushed or pulled.
+	ID         string
+	Size       int64 // Size in bytes, including the layers shared with other images.
+	Created    time.Time
+}
+
+// imagesOutput is a line of the JSON output of `docker images`.
+type imagesOutput struct {
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	Digest     string `json:"Digest"`
+	ID         string `json:"ID"`
+	Size       string `json:"Size"`
+	CreatedAt  string `json:"CreatedAt"`
+}
+
+// ListImages returns the images of the local image store that match the filters, for example the ones that copilot built.
+func (c DockerCmdClient) ListImages(ctx context.Context, filters ImageFilters) ([]ImageSummary, error) {
+	args := []string{"images", "--digests", "--no-trunc", "--format", "{{json .}}"}
+	labels := make([]string, 0, len(filters.Labels))
+	for k, v := range filters.Labels {
+		if v == "" {
+			labels = append(labels, k)
+			continue
+		}
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		args = append(args, "--filter", "label="+label)
+	}
+	if filters.Dangling {
+		args = append(args, "--filter", "dangling=true")
+	}
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("list docker images: %w", err)
+	}
+	var images []ImageSummary
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var out imagesOutput
+		if err := json.Unmarshal(line, &out); err != nil {
+			return nil, fmt.Errorf("unmarshal docker images output %q: %w", line, err)
+		}
+		img, err := out.summary()
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func (o imagesOutput) summary() (ImageSummary, error) {
+	size, err := humanize.ParseBytes(o.Size)
+	if err != nil {
+		return ImageSummary{}, fmt.Errorf("parse size %q of image %s: %w", o.Size, o.ID, err)
+	}
+	created, err := time.Parse(imagesCreatedAtFormat, o.CreatedAt)
+	if err != nil {
+		return ImageSummary{}, fmt.Errorf("parse creation time %q of image %s: %w", o.CreatedAt, o.ID, err)
+	}
+	return ImageSummary{
+		Repository: o.Repository,
+		Tag:        o.Tag,
+		Digest:     o.Digest,
+		ID:         o.ID,
+		Size:       int64(size),
+		Created:    created,
+	}, nil
+}
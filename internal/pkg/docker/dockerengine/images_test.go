@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_ListImages(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		filters   ImageFilters
+		setupMock func(m *MockCmd)
+
+		wanted    []ImageSummary
+		wantedErr string
+	}{
+		"lists the images with the labels": {
+			filters: ImageFilters{
+				Labels: map[string]string{
+					"com.aws.copilot.image.builder":        "copilot-cli",
+					"com.aws.copilot.image.container.name": "",
+				},
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"images", "--digests", "--no-trunc", "--format", "{{json .}}",
+					"--filter", "label=com.aws.copilot.image.builder=copilot-cli",
+					"--filter", "label=com.aws.copilot.image.container.name"}, gomock.Any()).
+					Do(mockStdout(`{"Containers":"N/A","CreatedAt":"2024-03-01 10:20:30 +0000 UTC","CreatedSince":"2 days ago","Digest":"sha256:aaaa","ID":"sha256:1111","Repository":"123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc","Size":"142MB","Tag":"latest"}
+{"CreatedAt":"2024-02-01 08:00:00 +0000 UTC","Digest":"<none>","ID":"sha256:2222","Repository":"<none>","Size":"1.5GB","Tag":"<none>"}
+`)).Return(nil)
+			},
+			wanted: []ImageSummary{
+				{
+					Repository: "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc",
+					Tag:        "latest",
+					Digest:     "sha256:aaaa",
+					ID:         "sha256:1111",
+					Size:       142000000,
+					Created:    time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+				},
+				{
+					Repository: "<none>",
+					Tag:        "<none>",
+					Digest:     "<none>",
+					ID:         "sha256:2222",
+					Size:       1500000000,
+					Created:    time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		"lists dangling images": {
+			filters: ImageFilters{Dangling: true},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"images", "--digests", "--no-trunc", "--format", "{{json .}}", "--filter", "dangling=true"}, gomock.Any()).Return(nil)
+			},
+		},
+		"wraps the error": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "list docker images: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.ListImages(ctx, tc.filters)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tc.wanted))
+			for i := range tc.wanted {
+				require.True(t, tc.wanted[i].Created.Equal(got[i].Created))
+				got[i].Created = tc.wanted[i].Created
+			}
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// imageInspect is the subset of `docker image inspect` output that we care about.
+// The same struct is used to decode OCI image configs fetched from a registry since
+// field names are matched case-insensitively.
+type imageInspect struct {
+	ID           string             `json:"Id"`
+	RepoTags     []string           `json:"RepoTags"`
+	RepoDigests  []string           `json:"RepoDigests"`
+	Created      string             `json:"Created"`
+	OS           string             `json:"Os"`
+	Architecture string             `json:"Architecture"`
+	Size         int64              `json:"Size"`
+	Config       imageInspectConfig `json:"Config"`
+	RootFS       imageInspectRootFS `json:"RootFS"`
+}
+
+type imageInspectConfig struct {
+	User         string              `json:"User"`
+	Env          []string            `json:"Env"`
+	Cmd          []string            `json:"Cmd"`
+	Entrypoint   []string            `json:"Entrypoint"`
+	WorkingDir   string              `json:"WorkingDir"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	Labels       map[string]string   `json:"Labels"`
+}
+
+type imageInspectRootFS struct {
+	Layers  []string `json:"Layers"`   // Set by `docker image inspect`.
+	DiffIDs []string `json:"diff_ids"` // Set in OCI image configs.
+}
+
+func (i *imageInspect) layers() []string {
+	if len(i.RootFS.Layers) != 0 {
+		return i.RootFS.Layers
+	}
+	return i.RootFS.DiffIDs
+}
+
+func (i *imageInspect) env() map[string]string {
+	env := make(map[string]string, len(i.Config.Env))
+	for _, kv := range i.Config.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	return env
+}
+
+// ImageExists returns true if the image is present in the local image store, so that callers can decide
+// whether to reuse it, pull it, or build it.
+func (c DockerCmdClient) ImageExists(ctx context.Context, ref string) (bool, error) {
+	stderr := &bytes.Buffer{}
+	err := c.runner.RunWithContext(ctx, "docker", []string{"image", "inspect", "--format", "{{.Id}}", ref}, exec.Stdout(io.Discard), exec.Stderr(stderr))
+	if err == nil {
+		return true, nil
+	}
+	if strings.Contains(strings.ToLower(stderr.String()), "no such image") {
+		return false, nil
+	}
+	return false, fmt.Errorf("docker image inspect %s: %w", ref, err)
+}
+
+// inspectLocalImage runs `docker image inspect` against an image present in the local image store.
+func (c DockerCmdClient) inspectLocalImage(ctx context.Context, ref string) (*imageInspect, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", imageInspectArgs(ref), exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("docker image inspect %s: %w", ref, err)
+	}
+	var images []imageInspect
+	if err := json.Unmarshal(buf.Bytes(), &images); err != nil {
+		return nil, fmt.Errorf("unmarshal docker image inspect output for %s: %w", ref, err)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no image found for %s", ref)
+	}
+	return &images[0], nil
+}
+
+func imageInspectArgs(ref string) []string {
+	return []string{"image", "inspect", ref}
+}
+
+// inspectRemoteImage fetches the image config of ref from its registry without pulling the image.
+// If ref points to a multi-platform image index, the linux/amd64 config is preferred.
+func (c DockerCmdClient) inspectRemoteImage(ctx context.Context, ref string) (*imageInspect, error) {
+	buf := &bytes.Buffer{}
+	args := []string{"buildx", "imagetools", "inspect", "--format", "{{json .Image}}", ref}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("docker buildx imagetools inspect %s: %w", ref, err)
+	}
+	out := bytes.TrimSpace(buf.Bytes())
+	var image imageInspect
+	if err := json.Unmarshal(out, &image); err == nil && (image.OS != "" || len(image.layers()) != 0) {
+		return &image, nil
+	}
+	// Multi-platform images are rendered as a map of platform to image config.
+	var images map[string]imageInspect
+	if err := json.Unmarshal(out, &images); err != nil {
+		return nil, fmt.Errorf("unmarshal image config for %s: %w", ref, err)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no image config found for %s", ref)
+	}
+	if img, ok := images[PlatformString(OSLinux, ArchAMD64)]; ok {
+		return &img, nil
+	}
+	platforms := make([]string, 0, len(images))
+	for platform := range images {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	img := images[platforms[0]]
+	return &img, nil
+}
+
+// ImageConfig is the configuration of an image that determines how its containers run.
+type ImageConfig struct {
+	Platform     string // Platform of the image, like "linux/amd64".
+	Entrypoint   []string
+	Cmd          []string
+	ExposedPorts []string          // Sorted ports exposed by the image, like "8080/tcp".
+	Env          map[string]string // Environment variables keyed by name.
+	User         string
+	WorkingDir   string
+	Labels       map[string]string
+}
+
+// RemoteInspect fetches the configuration of ref from its registry without pulling the image,
+// for example to validate a manifest against a prebuilt image before deploying it.
+// If ref points to a multi-platform image index, the linux/amd64 configuration is preferred.
+func (c DockerCmdClient) RemoteInspect(ctx context.Context, ref string) (*ImageConfig, error) {
+	img, err := c.inspectRemoteImage(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]string, 0, len(img.Config.ExposedPorts))
+	for port := range img.Config.ExposedPorts {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+	return &ImageConfig{
+		Platform:     PlatformString(img.OS, img.Architecture),
+		Entrypoint:   img.Config.Entrypoint,
+		Cmd:          img.Config.Cmd,
+		ExposedPorts: ports,
+		Env:          img.env(),
+		User:         img.Config.User,
+		WorkingDir:   img.Config.WorkingDir,
+		Labels:       img.Config.Labels,
+	}, nil
+}
+
+// inspectImage inspects ref from the local image store and falls back to the registry if it's not available locally.
+func (c DockerCmdClient) inspectImage(ctx context.Context, ref string) (*imageInspect, error) {
+	if img, err := c.inspectLocalImage(ctx, ref); err == nil {
+		return img, nil
+	}
+	return c.inspectRemoteImage(ctx, ref)
+}
+
+// RemoteDigest returns the digest of the manifest, or of the image index for multi-platform images, that ref points to in its registry.
+func (c DockerCmdClient) RemoteDigest(ctx context.Context, ref string) (string, error) {
+	buf := &bytes.Buffer{}
+	args := []string{"buildx", "imagetools", "inspect", "--format", "{{json .Manifest}}", ref}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return "", fmt.Errorf("docker buildx imagetools inspect %s: %w", ref, err)
+	}
+	var manifest struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &manifest); err != nil {
+		return "", fmt.Errorf("unmarshal manifest descriptor for %s: %w", ref, err)
+	}
+	if manifest.Digest == "" {
+		return "", fmt.Errorf("no digest found for %s", ref)
+	}
+	return manifest.Digest, nil
+}
@@ -0,0 +1,179 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_RemoteDigest(t *testing.T) {
+	ctx := context.Background()
+	ref := "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:latest"
+	wantedArgs := []string{"buildx", "imagetools", "inspect", "--format", "{{json .Manifest}}", ref}
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wanted    string
+		wantedErr string
+	}{
+		"returns the digest of the image index": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any()).
+					Do(mockStdout(`{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"sha256:a1b2c3","size":856}`)).Return(nil)
+			},
+			wanted: "sha256:a1b2c3",
+		},
+		"returns a wrapped error if the image can't be inspected": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any()).Return(errors.New("not found"))
+			},
+			wantedErr: "docker buildx imagetools inspect " + ref + ": not found",
+		},
+		"returns an error if the output has no digest": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any()).Do(mockStdout(`{}`)).Return(nil)
+			},
+			wantedErr: "no digest found for " + ref,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.RemoteDigest(ctx, ref)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerCommand_ImageExists(t *testing.T) {
+	ctx := context.Background()
+	args := []string{"image", "inspect", "--format", "{{.Id}}", "app:latest"}
+	tests := map[string]struct {
+		setupMock func(m *MockCmd)
+
+		wanted    bool
+		wantedErr string
+	}{
+		"returns true if the image is present": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wanted: true,
+		},
+		"returns false if the image is missing": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any(), gomock.Any()).
+					Do(mockStderr("Error: No such image: app:latest\n")).Return(errors.New("exit status 1"))
+			},
+		},
+		"returns other errors": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any(), gomock.Any()).
+					Do(mockStderr("Cannot connect to the Docker daemon\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "docker image inspect app:latest: exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.ImageExists(ctx, "app:latest")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerCommand_RemoteInspect(t *testing.T) {
+	ctx := context.Background()
+	args := []string{"buildx", "imagetools", "inspect", "--format", "{{json .Image}}", "public.ecr.aws/nginx/nginx:1.25"}
+	tests := map[string]struct {
+		setupMock func(m *MockCmd)
+
+		wanted    *ImageConfig
+		wantedErr string
+	}{
+		"returns the config of a single-platform image": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any()).
+					Do(mockStdout(`{"architecture":"arm64","os":"linux","config":{"ExposedPorts":{"443/tcp":{},"80/tcp":{}},"Env":["PATH=/usr/bin","NGINX_VERSION=1.25"],"Entrypoint":["/docker-entrypoint.sh"],"Cmd":["nginx","-g","daemon off;"],"WorkingDir":"/","User":"nginx"},"rootfs":{"type":"layers","diff_ids":["sha256:aaaa"]}}`)).Return(nil)
+			},
+			wanted: &ImageConfig{
+				Platform:     "linux/arm64",
+				Entrypoint:   []string{"/docker-entrypoint.sh"},
+				Cmd:          []string{"nginx", "-g", "daemon off;"},
+				ExposedPorts: []string{"443/tcp", "80/tcp"},
+				Env:          map[string]string{"PATH": "/usr/bin", "NGINX_VERSION": "1.25"},
+				User:         "nginx",
+				WorkingDir:   "/",
+			},
+		},
+		"prefers the linux/amd64 config of multi-platform images": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any()).
+					Do(mockStdout(`{"linux/amd64":{"architecture":"amd64","os":"linux","config":{"Cmd":["nginx"]}},"linux/arm64":{"architecture":"arm64","os":"linux","config":{"Cmd":["nginx"]}}}`)).Return(nil)
+			},
+			wanted: &ImageConfig{
+				Platform:     "linux/amd64",
+				Cmd:          []string{"nginx"},
+				ExposedPorts: []string{},
+				Env:          map[string]string{},
+			},
+		},
+		"wraps the error": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", args, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "docker buildx imagetools inspect public.ecr.aws/nginx/nginx:1.25: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.RemoteInspect(ctx, "public.ecr.aws/nginx/nginx:1.25")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
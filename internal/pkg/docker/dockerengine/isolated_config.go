@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	dockerConfigFile = "config.json"
+	dockerConfigEnv  = "DOCKER_CONFIG"
+)
+
+// isolatedConfig is the content of the config.json file of an isolated config directory.
+type isolatedConfig struct {
+	Auths       map[string]struct{} `json:"auths"`
+	CredHelpers map[string]string   `json:"credHelpers,omitempty"`
+	// CLIPluginsExtraDirs keeps the plugins installed in the user's config directory, such as buildx, available.
+	CLIPluginsExtraDirs []string `json:"cliPluginsExtraDirs,omitempty"`
+}
+
+// configDirRunner runs commands with DOCKER_CONFIG set to dir.
+type configDirRunner struct {
+	Cmd
+	dir string
+}
+
+func (r configDirRunner) Run(name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.Run(name, args, append(opts, exec.Env(dockerConfigEnv+"="+r.dir))...)
+}
+
+func (r configDirRunner) RunWithContext(ctx context.Context, name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.RunWithContext(ctx, name, args, append(opts, exec.Env(dockerConfigEnv+"="+r.dir))...)
+}
+
+// WithIsolatedConfig returns a copy of the client that runs docker commands with a new, temporary config directory
+// instead of the user's one, so that logins never modify the user's config and concurrent jobs don't race on it.
+// The directory only holds the credentials provisioned through the returned client, and the credential helpers
+// keyed by registry, for example "<account>.dkr.ecr.<region>.amazonaws.com": "ecr-login".
+// Buildx builder instances created by the user are not visible to the returned client.
+// The cleanup function removes the directory and the credentials it holds.
+func (c DockerCmdClient) WithIsolatedConfig(credHelpers map[string]string) (DockerCmdClient, func() error, error) {
+	dir, err := os.MkdirTemp("", "copilot-docker-config-")
+	if err != nil {
+		return DockerCmdClient{}, nil, fmt.Errorf("create isolated docker config directory: %w", err)
+	}
+	cleanup := func() error {
+		return os.RemoveAll(dir)
+	}
+	cfg := isolatedConfig{
+		Auths:       make(map[string]struct{}),
+		CredHelpers: credHelpers,
+	}
+	if plugins := c.userCLIPluginsDir(); plugins != "" {
+		cfg.CLIPluginsExtraDirs = []string{plugins}
+	}
+	data, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		_ = cleanup()
+		return DockerCmdClient{}, nil, fmt.Errorf("marshal isolated docker config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, dockerConfigFile), data, 0600); err != nil {
+		_ = cleanup()
+		return DockerCmdClient{}, nil, fmt.Errorf("write isolated docker config: %w", err)
+	}
+	isolated := c
+	isolated.runner = configDirRunner{
+		Cmd: c.runner,
+		dir: dir,
+	}
+	isolated.configDir = dir
+	return isolated, cleanup, nil
+}
+
+// userCLIPluginsDir returns the directory of the docker CLI plugins installed for the user, if any.
+func (c DockerCmdClient) userCLIPluginsDir() string {
+	configDir := c.configDir
+	if configDir == "" {
+		if dir, ok := c.lookupEnv(dockerConfigEnv); ok && dir != "" {
+			configDir = dir
+		} else if c.homePath != "" {
+			configDir = filepath.Join(c.homePath, ".docker")
+		}
+	}
+	if configDir == "" {
+		return ""
+	}
+	dir := filepath.Join(configDir, "cli-plugins")
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_WithIsolatedConfig(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".docker", "cli-plugins"), 0755))
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	s := DockerCmdClient{
+		runner:   m,
+		homePath: home,
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}
+
+	isolated, cleanup, err := s.WithIsolatedConfig(map[string]string{"123456789012.dkr.ecr.us-west-2.amazonaws.com": credStoreECRLogin})
+	require.NoError(t, err)
+	dir := isolated.configDir
+
+	t.Run("seeds the config with the credential helpers and the user's plugins", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, dockerConfigFile))
+		require.NoError(t, err)
+		var cfg isolatedConfig
+		require.NoError(t, json.Unmarshal(data, &cfg))
+		require.Equal(t, isolatedConfig{
+			Auths:               map[string]struct{}{},
+			CredHelpers:         map[string]string{"123456789012.dkr.ecr.us-west-2.amazonaws.com": credStoreECRLogin},
+			CLIPluginsExtraDirs: []string{filepath.Join(home, ".docker", "cli-plugins")},
+		}, cfg)
+		require.True(t, isolated.IsEcrCredentialHelperEnabled("123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"))
+	})
+	t.Run("runs docker commands with the isolated config", func(t *testing.T) {
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"logout", "mockURI"}, gomock.Any()).
+			Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+				cmd := &osexec.Cmd{}
+				for _, opt := range opts {
+					opt(cmd)
+				}
+				require.Equal(t, dockerConfigEnv+"="+dir, cmd.Env[len(cmd.Env)-1])
+			}).Return(nil)
+
+		require.NoError(t, isolated.runner.RunWithContext(ctx, "docker", []string{"logout", "mockURI"}))
+	})
+	t.Run("cleanup removes the config directory", func(t *testing.T) {
+		require.NoError(t, cleanup())
+
+		_, err := os.Stat(dir)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
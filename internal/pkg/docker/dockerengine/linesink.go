@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LineSink receives the output of a build line by line, along with the name of the image being built.
+// Implementations must be safe for concurrent use when they're shared by concurrent builds.
+type LineSink interface {
+	WriteLine(image, line string)
+}
+
+// LineSinkFunc is a function that implements LineSink.
+type LineSinkFunc func(image, line string)
+
+// WriteLine calls f(image, line).
+func (f LineSinkFunc) WriteLine(image, line string) {
+	f(image, line)
+}
+
+// BuildWithSink builds the image like Build, sending the output to sink line by line.
+func (c DockerCmdClient) BuildWithSink(ctx context.Context, in *BuildArguments, sink LineSink) error {
+	w := newLineWriter(sink, in.displayName())
+	defer w.Flush()
+	return c.Build(ctx, in, w)
+}
+
+// PrefixSink writes each line to an io.Writer prefixed with the name of its image, for example "[web:latest] Step 1/4".
+type PrefixSink struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewPrefixSink returns a PrefixSink that writes to w.
+func NewPrefixSink(w io.Writer) *PrefixSink {
+	return &PrefixSink{
+		w: w,
+	}
+}
+
+// WriteLine writes the prefixed line to the underlying writer. Lines of concurrent builds are never interleaved.
+func (s *PrefixSink) WriteLine(image, line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "[%s] %s\n", image, line)
+}
+
+// CaptureSink keeps the lines of each image in memory so that they can be displayed later, for example only if the build fails.
+type CaptureSink struct {
+	mu    sync.Mutex
+	lines map[string][]string
+}
+
+// NewCaptureSink returns an empty CaptureSink.
+func NewCaptureSink() *CaptureSink {
+	return &CaptureSink{
+		lines: make(map[string][]string),
+	}
+}
+
+// WriteLine records the line for the image.
+func (s *CaptureSink) WriteLine(image, line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines[image] = append(s.lines[image], line)
+}
+
+// Lines returns the lines recorded for the image.
+func (s *CaptureSink) Lines(image string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines[image]...)
+}
+
+// lineWriter is an io.Writer that sends complete lines to a LineSink.
+type lineWriter struct {
+	sink  LineSink
+	image string
+	buf   bytes.Buffer
+}
+
+func newLineWriter(sink LineSink, image string) *lineWriter {
+	return &lineWriter{
+		sink:  sink,
+		image: image,
+	}
+}
+
+// Write buffers p and sends all the complete lines to the sink.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			return len(p), nil
+		}
+		line := w.buf.Next(idx + 1)
+		w.sink.WriteLine(w.image, string(bytes.TrimRight(line, "\r\n")))
+	}
+}
+
+// Flush sends the remaining incomplete line, if any, to the sink.
+func (w *lineWriter) Flush() {
+	if w.buf.Len() == 0 {
+		return
+	}
+	w.sink.WriteLine(w.image, w.buf.String())
+	w.buf.Reset()
+}
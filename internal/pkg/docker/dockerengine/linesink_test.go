@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_BuildWithSink(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(mockStdout("Step 1/2 : FROM scratch\r\nStep 2/2 : COPY . .\nSuccessfully built")).Return(nil)
+	s := DockerCmdClient{
+		runner: m,
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}
+	sink := NewCaptureSink()
+
+	err := s.BuildWithSink(ctx, &BuildArguments{
+		URI:        "mockURI",
+		Tags:       []string{"latest"},
+		Dockerfile: "mockDir/Dockerfile",
+	}, sink)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"Step 1/2 : FROM scratch", "Step 2/2 : COPY . .", "Successfully built"}, sink.Lines("mockURI:latest"))
+}
+
+func TestLineWriter(t *testing.T) {
+	out := &strings.Builder{}
+	w := newLineWriter(NewPrefixSink(out), "web")
+
+	_, err := w.Write([]byte("hello\nwor"))
+	require.NoError(t, err)
+	require.Equal(t, "[web] hello\n", out.String())
+	_, err = w.Write([]byte("ld"))
+	require.NoError(t, err)
+	w.Flush()
+
+	require.Equal(t, "[web] hello\n[web] world\n", out.String())
+}
+
+func TestLineSinkFunc(t *testing.T) {
+	var got []string
+	sink := LineSinkFunc(func(image, line string) {
+		got = append(got, image+": "+line)
+	})
+
+	sink.WriteLine("web", "hello")
+
+	require.Equal(t, []string{"web: hello"}, got)
+}
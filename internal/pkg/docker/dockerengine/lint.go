@@ -0,0 +1,342 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"sort"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+)
+
+// Levels of lint findings, as reported by hadolint.
+const (
+	LintLevelError   = "error"
+	LintLevelWarning = "warning"
+	LintLevelInfo    = "info"
+	LintLevelStyle   = "style"
+)
+
+// Rules of the built-in linter. They reuse the codes of the equivalent hadolint rules where there is one.
+const (
+	lintRuleRootUser      = "DL3002"
+	lintRuleUntaggedImage = "DL3006"
+	lintRuleLatestTag     = "DL3007"
+	lintRuleAptLists      = "DL3009"
+	lintRuleMissingUser   = "CP0001"
+)
+
+// LintOptions configures the Dockerfile lint stage that runs before a build.
+// The Dockerfile is linted with hadolint if it is installed, and with a few built-in rules otherwise.
+// Built-in rules that hadolint has no equivalent for, like the missing user rule, always run.
+type LintOptions struct {
+	Strict bool     // Optional. Fails the build if there are findings of level warning or error. Otherwise the findings are only logged.
+	Ignore []string // Optional. Rules to ignore, for example "DL3007".
+}
+
+// LintFinding is a problem found in a Dockerfile.
+type LintFinding struct {
+	Rule    string // For example "DL3007".
+	Level   string // One of LintLevelError, LintLevelWarning, LintLevelInfo or LintLevelStyle.
+	Line    int
+	Message string
+}
+
+// String returns the finding formatted like "3 DL3007 warning: Using latest is prone to errors ...".
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%d %s %s: %s", f.Line, f.Rule, f.Level, f.Message)
+}
+
+// ErrLintFailed means the Dockerfile has findings that fail the build in strict mode.
+type ErrLintFailed struct {
+	Dockerfile string
+	Findings   []LintFinding
+}
+
+func (e *ErrLintFailed) Error() string {
+	lines := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		lines[i] = fmt.Sprintf("%s:%s", e.Dockerfile, f)
+	}
+	return fmt.Sprintf("lint %s: %d findings:\n%s", e.Dockerfile, len(e.Findings), strings.Join(lines, "\n"))
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrLintFailed) RecommendActions() string {
+	return fmt.Sprintf("Fix the findings in %s, or add their rules to the ignored lint rules.", e.Dockerfile)
+}
+
+// dockerfileLinter lints Dockerfiles with hadolint, or with the built-in rules if hadolint is not installed.
+// The built-in rules without a hadolint equivalent are merged with the findings of hadolint.
+type dockerfileLinter struct {
+	runner   Cmd
+	lookPath func(file string) (string, error) // Override in unit tests.
+}
+
+// LintDockerfile returns the findings of linting the Dockerfile at path, minus the ignored rules.
+func (c DockerCmdClient) LintDockerfile(ctx context.Context, path string, opts *LintOptions) ([]LintFinding, error) {
+	l := &dockerfileLinter{
+		runner:   c.runner,
+		lookPath: osexec.LookPath,
+	}
+	return l.lint(ctx, path, opts)
+}
+
+// lint runs the lint stage of a build: it fails on findings in strict mode, and logs them otherwise.
+func (c DockerCmdClient) lint(ctx context.Context, in *BuildArguments) error {
+	if in.Lint == nil || in.Dockerfile == stdinPath {
+		return nil
+	}
+	findings, err := c.LintDockerfile(ctx, in.Dockerfile, in.Lint)
+	if err != nil {
+		return err
+	}
+	var failed []LintFinding
+	for _, f := range findings {
+		if in.Lint.Strict && (f.Level == LintLevelError || f.Level == LintLevelWarning) {
+			failed = append(failed, f)
+			continue
+		}
+		log.Warningf("%s:%s\n", in.Dockerfile, f)
+	}
+	if len(failed) != 0 {
+		return &ErrLintFailed{
+			Dockerfile: in.Dockerfile,
+			Findings:   failed,
+		}
+	}
+	return nil
+}
+
+func (l *dockerfileLinter) lint(ctx context.Context, path string, opts *LintOptions) ([]LintFinding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open Dockerfile: %w", err)
+	}
+	defer f.Close()
+	findings, err := lintBuiltin(f)
+	if err != nil {
+		return nil, fmt.Errorf("lint %s: %w", path, err)
+	}
+	if _, err := l.lookPath("hadolint"); err == nil {
+		out, err := l.hadolint(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		// hadolint covers the other built-in rules, but has no equivalent of the missing user rule.
+		for _, f := range findings {
+			if f.Rule == lintRuleMissingUser {
+				out = append(out, f)
+			}
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].Line < out[j].Line
+		})
+		findings = out
+	}
+	ignored := make(map[string]bool)
+	for _, rule := range opts.Ignore {
+		ignored[rule] = true
+	}
+	var kept []LintFinding
+	for _, f := range findings {
+		if !ignored[f.Rule] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+// hadolint returns the findings of `hadolint` on the Dockerfile at path.
+func (l *dockerfileLinter) hadolint(ctx context.Context, path string) ([]LintFinding, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	// --no-fail exits with 0 regardless of the findings, so that a non-zero exit code means hadolint itself failed.
+	if err := l.runner.RunWithContext(ctx, "hadolint", []string{"--format", "json", "--no-fail", path}, exec.Stdout(stdout), exec.Stderr(stderr)); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("run hadolint on %s: %w: %s", path, err, msg)
+		}
+		return nil, fmt.Errorf("run hadolint on %s: %w", path, err)
+	}
+	var out []struct {
+		Code    string `json:"code"`
+		Level   string `json:"level"`
+		Line    int    `json:"line"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &out); err != nil {
+		return nil, fmt.Errorf("unmarshal hadolint findings: %w", err)
+	}
+	findings := make([]LintFinding, len(out))
+	for i, f := range out {
+		findings[i] = LintFinding{
+			Rule:    f.Code,
+			Level:   f.Level,
+			Line:    f.Line,
+			Message: f.Message,
+		}
+	}
+	return findings, nil
+}
+
+// dockerfileInstruction is an instruction of a Dockerfile, with its continuation lines joined.
+type dockerfileInstruction struct {
+	cmd  string // In uppercase, for example "FROM".
+	args string
+	line int // Line number at the start of the instruction.
+}
+
+// lintBuiltin returns the findings of the built-in rules on the Dockerfile read from r.
+func lintBuiltin(r io.Reader) ([]LintFinding, error) {
+	instrs, err := scanInstructions(r)
+	if err != nil {
+		return nil, err
+	}
+	var findings []LintFinding
+	stages := make(map[string]bool)
+	var lastFrom int
+	var lastUser *dockerfileInstruction
+	for i, instr := range instrs {
+		switch instr.cmd {
+		case "FROM":
+			lastFrom, lastUser = instr.line, nil
+			image, stage := parseFromArgs(instr.args)
+			if f, ok := lintBaseImage(image, stages, instr.line); ok {
+				findings = append(findings, f)
+			}
+			if stage != "" {
+				stages[stage] = true
+			}
+		case "USER":
+			lastUser = &instrs[i]
+		case "RUN":
+			if strings.Contains(instr.args, "apt-get install") &&
+				!strings.Contains(instr.args, "rm -rf /var/lib/apt/lists") && !strings.Contains(instr.args, "--mount=type=cache") {
+				findings = append(findings, LintFinding{
+					Rule:    lintRuleAptLists,
+					Level:   LintLevelInfo,
+					Line:    instr.line,
+					Message: "Delete the apt-get lists after installing something with `rm -rf /var/lib/apt/lists/*` to keep them out of the image.",
+				})
+			}
+		}
+	}
+	if lastFrom == 0 {
+		return findings, nil
+	}
+	// Only the user of the final stage matters at runtime.
+	switch {
+	case lastUser == nil:
+		findings = append(findings, LintFinding{
+			Rule:    lintRuleMissingUser,
+			Level:   LintLevelWarning,
+			Line:    lastFrom,
+			Message: "The final stage doesn't set a USER, so the container runs as the user of the base image, which is often root.",
+		})
+	case isRootUser(lastUser.args):
+		findings = append(findings, LintFinding{
+			Rule:    lintRuleRootUser,
+			Level:   LintLevelWarning,
+			Line:    lastUser.line,
+			Message: "Last USER should not be root.",
+		})
+	}
+	return findings, nil
+}
+
+// lintBaseImage returns a finding if the base image of a FROM instruction is not pinned to a tag or a digest.
+func lintBaseImage(image string, stages map[string]bool, line int) (LintFinding, bool) {
+	if image == "" || image == "scratch" || stages[strings.ToLower(image)] || strings.Contains(image, "$") || strings.Contains(image, "@") {
+		return LintFinding{}, false
+	}
+	// The tag follows the last colon after the last slash, which may be preceded by a registry port.
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, ok := strings.Cut(name, ":")
+	switch {
+	case !ok:
+		return LintFinding{
+			Rule:    lintRuleUntaggedImage,
+			Level:   LintLevelWarning,
+			Line:    line,
+			Message: fmt.Sprintf("Always tag the version of an image explicitly: %s.", image),
+		}, true
+	case tag == "latest":
+		return LintFinding{
+			Rule:    lintRuleLatestTag,
+			Level:   LintLevelWarning,
+			Line:    line,
+			Message: fmt.Sprintf("Using latest is prone to errors if the image will ever update. Pin the version of %s explicitly.", image),
+		}, true
+	}
+	return LintFinding{}, false
+}
+
+// parseFromArgs returns the image and the lowercase stage name from the arguments of a FROM instruction
+// like "--platform=$BUILDPLATFORM golang:1.20 AS build".
+func parseFromArgs(args string) (image, stage string) {
+	var fields []string
+	for _, field := range strings.Fields(args) {
+		if strings.HasPrefix(field, "--") {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return "", ""
+	}
+	if len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+		stage = strings.ToLower(fields[2])
+	}
+	return fields[0], stage
+}
+
+// isRootUser returns true if the arguments of a USER instruction, like "root:root" or "0", designate root.
+func isRootUser(args string) bool {
+	user, _, _ := strings.Cut(strings.TrimSpace(args), ":")
+	return user == "root" || user == "0"
+}
+
+// scanInstructions splits the Dockerfile read from r into instructions, skipping comments and blank lines.
+func scanInstructions(r io.Reader) ([]dockerfileInstruction, error) {
+	var instrs []dockerfileInstruction
+	var cur *dockerfileInstruction
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSuffix(line, "\\")
+		if cur != nil {
+			cur.args += " " + strings.TrimSpace(line)
+		} else {
+			cmd, args, _ := strings.Cut(line, " ")
+			cur = &dockerfileInstruction{
+				cmd:  strings.ToUpper(cmd),
+				args: strings.TrimSpace(args),
+				line: lineNum,
+			}
+		}
+		if !continued {
+			instrs = append(instrs, *cur)
+			cur = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		instrs = append(instrs, *cur)
+	}
+	return instrs, nil
+}
@@ -0,0 +1,165 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintBuiltin(t *testing.T) {
+	tests := map[string]struct {
+		dockerfile string
+
+		wanted []LintFinding
+	}{
+		"passes a pinned image with a non-root user": {
+			dockerfile: `
+FROM golang:1.20 AS build
+RUN go build -o /app .
+
+FROM gcr.io/distroless/static:nonroot
+COPY --from=build /app /app
+USER nonroot:nonroot
+`,
+		},
+		"reports unpinned base images but not previous stages": {
+			dockerfile: `FROM --platform=$BUILDPLATFORM golang AS build
+FROM build AS test
+FROM localhost:5000/nginx:latest
+USER nginx
+`,
+			wanted: []LintFinding{
+				{Rule: "DL3006", Level: LintLevelWarning, Line: 1, Message: "Always tag the version of an image explicitly: golang."},
+				{Rule: "DL3007", Level: LintLevelWarning, Line: 3, Message: "Using latest is prone to errors if the image will ever update. Pin the version of localhost:5000/nginx:latest explicitly."},
+			},
+		},
+		"reports apt lists left in the image and a missing user": {
+			dockerfile: `FROM ubuntu:22.04
+# Install curl.
+RUN apt-get update && \
+    apt-get install -y curl
+`,
+			wanted: []LintFinding{
+				{Rule: "DL3009", Level: LintLevelInfo, Line: 3, Message: "Delete the apt-get lists after installing something with `rm -rf /var/lib/apt/lists/*` to keep them out of the image."},
+				{Rule: "CP0001", Level: LintLevelWarning, Line: 1, Message: "The final stage doesn't set a USER, so the container runs as the user of the base image, which is often root."},
+			},
+		},
+		"reports a final root user": {
+			dockerfile: `FROM ubuntu:22.04
+RUN apt-get update && apt-get install -y curl && rm -rf /var/lib/apt/lists/*
+USER 0:0
+`,
+			wanted: []LintFinding{
+				{Rule: "DL3002", Level: LintLevelWarning, Line: 3, Message: "Last USER should not be root."},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := lintBuiltin(strings.NewReader(tc.dockerfile))
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerfileLinter_lint(t *testing.T) {
+	ctx := context.Background()
+	dockerfile := filepath.Join(t.TempDir(), "Dockerfile")
+	require.NoError(t, os.WriteFile(dockerfile, []byte("FROM nginx:latest\n"), 0644))
+	hadolintArgs := []string{"--format", "json", "--no-fail", dockerfile}
+
+	tests := map[string]struct {
+		hadolint   bool
+		opts       *LintOptions
+		setupMocks func(m *MockCmd)
+
+		wanted    []LintFinding
+		wantedErr string
+	}{
+		"lints with the built-in rules without hadolint": {
+			opts:       &LintOptions{Ignore: []string{"CP0001"}},
+			setupMocks: func(m *MockCmd) {},
+			wanted: []LintFinding{
+				{Rule: "DL3007", Level: LintLevelWarning, Line: 1, Message: "Using latest is prone to errors if the image will ever update. Pin the version of nginx:latest explicitly."},
+			},
+		},
+		"lints with hadolint if it's installed and merges the built-in rules it lacks": {
+			hadolint: true,
+			opts:     &LintOptions{Ignore: []string{"DL3002"}},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "hadolint", hadolintArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`[{"code":"DL3007","column":1,"file":"Dockerfile","level":"warning","line":1,"message":"Using latest is prone to errors if the image will ever update. Pin the version explicitly to a release tag"},
+{"code":"DL3002","column":1,"file":"Dockerfile","level":"warning","line":2,"message":"Last USER should not be root"}]`)).Return(nil)
+			},
+			wanted: []LintFinding{
+				{Rule: "DL3007", Level: LintLevelWarning, Line: 1, Message: "Using latest is prone to errors if the image will ever update. Pin the version explicitly to a release tag"},
+				{Rule: "CP0001", Level: LintLevelWarning, Line: 1, Message: "The final stage doesn't set a USER, so the container runs as the user of the base image, which is often root."},
+			},
+		},
+		"ignores the merged built-in rules": {
+			hadolint: true,
+			opts:     &LintOptions{Ignore: []string{"CP0001"}},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "hadolint", hadolintArgs, gomock.Any(), gomock.Any()).Do(mockStdout(`[]`)).Return(nil)
+			},
+		},
+		"wraps errors from hadolint": {
+			hadolint: true,
+			opts:     &LintOptions{},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "hadolint", hadolintArgs, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "run hadolint on " + dockerfile + ": exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			l := &dockerfileLinter{
+				runner: m,
+				lookPath: func(string) (string, error) {
+					if tc.hadolint {
+						return "/usr/local/bin/hadolint", nil
+					}
+					return "", errors.New("not found")
+				},
+			}
+
+			got, err := l.lint(ctx, dockerfile, tc.opts)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestErrLintFailed_Error(t *testing.T) {
+	err := &ErrLintFailed{
+		Dockerfile: "web/Dockerfile",
+		Findings: []LintFinding{
+			{Rule: "DL3007", Level: LintLevelWarning, Line: 1, Message: "Using latest is prone to errors."},
+			{Rule: "CP0001", Level: LintLevelWarning, Line: 1, Message: "The final stage doesn't set a USER."},
+		},
+	}
+
+	require.EqualError(t, err, `lint web/Dockerfile: 2 findings:
+web/Dockerfile:1 DL3007 warning: Using latest is prone to errors.
+web/Dockerfile:1 CP0001 warning: The final stage doesn't set a USER.`)
+}
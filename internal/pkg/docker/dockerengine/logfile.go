@@ -0,0 +1,229 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	defaultLogMaxSize  = 10 << 20 // 10 MiB.
+	defaultLogMaxAge   = 7 * 24 * time.Hour
+	defaultLogMaxFiles = 5
+
+	currentLogFile   = "docker.log"
+	rotatedLogPrefix = "docker-"
+	rotatedLogSuffix = ".log"
+	// rotatedLogTimeFormat sorts lexicographically in chronological order.
+	rotatedLogTimeFormat = "20060102T150405.000000000"
+)
+
+// persistedCommands are the docker commands whose output is written to the log files.
+var persistedCommands = map[string]bool{
+	"build":  true,
+	"buildx": true,
+	"push":   true,
+	"run":    true,
+}
+
+// LogPersistenceOptions holds the options of the log files that the output of docker commands is written to.
+type LogPersistenceOptions struct {
+	Dir      string        // Required. Directory holding the logs of every service, for example "copilot/.logs" under the workspace.
+	Service  string        // Required. Name of the service. Its logs are written to a directory named after it under Dir.
+	MaxSize  int64         // Optional. Size in bytes after which the log file is rotated. Defaults to 10 MiB.
+	MaxAge   time.Duration // Optional. Age after which rotated log files are removed. Defaults to 7 days.
+	MaxFiles int           // Optional. Number of rotated log files kept. Defaults to 5.
+}
+
+// RotatingLog is an io.WriteCloser writing to a log file that is rotated once it reaches a maximum size.
+// Rotated files are removed once they're too old or too many. It is safe for concurrent use.
+type RotatingLog struct {
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+	now      func() time.Time // Override in unit tests.
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingLog opens the log file of the service, creating its directory if needed, and removes expired rotated files.
+func NewRotatingLog(opts LogPersistenceOptions) (*RotatingLog, error) {
+	l := &RotatingLog{
+		dir:      filepath.Join(opts.Dir, opts.Service),
+		maxSize:  opts.MaxSize,
+		maxAge:   opts.MaxAge,
+		maxFiles: opts.MaxFiles,
+		now:      time.Now,
+	}
+	if l.maxSize <= 0 {
+		l.maxSize = defaultLogMaxSize
+	}
+	if l.maxAge <= 0 {
+		l.maxAge = defaultLogMaxAge
+	}
+	if l.maxFiles <= 0 {
+		l.maxFiles = defaultLogMaxFiles
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, fmt.Errorf("create log directory %s: %w", l.dir, err)
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	if err := l.prune(); err != nil {
+		_ = l.file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write appends p to the log file, rotating it first if p doesn't fit.
+func (l *RotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (l *RotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+func (l *RotatingLog) open() error {
+	path := filepath.Join(l.dir, currentLogFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open log file %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat log file %s: %w", path, err)
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+func (l *RotatingLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	rotated := filepath.Join(l.dir, rotatedLogPrefix+l.now().UTC().Format(rotatedLogTimeFormat)+rotatedLogSuffix)
+	if err := os.Rename(filepath.Join(l.dir, currentLogFile), rotated); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+	return l.prune()
+}
+
+// prune removes the rotated log files older than maxAge, and the oldest ones beyond maxFiles.
+func (l *RotatingLog) prune() error {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("read log directory %s: %w", l.dir, err)
+	}
+	now := l.now()
+	var rotated []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, rotatedLogPrefix) || !strings.HasSuffix(name, rotatedLogSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) > l.maxAge {
+			if err := os.Remove(filepath.Join(l.dir, name)); err != nil {
+				return fmt.Errorf("remove expired log file %s: %w", name, err)
+			}
+			continue
+		}
+		rotated = append(rotated, name)
+	}
+	sort.Strings(rotated)
+	for len(rotated) > l.maxFiles {
+		if err := os.Remove(filepath.Join(l.dir, rotated[0])); err != nil {
+			return fmt.Errorf("remove log file %s: %w", rotated[0], err)
+		}
+		rotated = rotated[1:]
+	}
+	return nil
+}
+
+// WithLogPersistence returns a copy of the client that also writes the output of build, push and run commands
+// to the rotating log file of the service, so that past logs are available for troubleshooting.
+// Each command is preceded by a header with its time and its arguments, where secrets are redacted.
+// The cleanup function closes the log file.
+func (c DockerCmdClient) WithLogPersistence(opts LogPersistenceOptions) (DockerCmdClient, func() error, error) {
+	rl, err := NewRotatingLog(opts)
+	if err != nil {
+		return DockerCmdClient{}, nil, err
+	}
+	c.runner = teeRunner{
+		Cmd: c.runner,
+		log: rl,
+		now: time.Now,
+	}
+	return c, rl.Close, nil
+}
+
+// teeRunner writes the output of the persisted commands to log in addition to their own writers.
+type teeRunner struct {
+	Cmd
+	log io.Writer
+	now func() time.Time
+}
+
+func (r teeRunner) Run(name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.Run(name, args, r.tee(args, opts)...)
+}
+
+func (r teeRunner) RunWithContext(ctx context.Context, name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.RunWithContext(ctx, name, args, r.tee(args, opts)...)
+}
+
+// tee returns the options of the command, followed by one that tees its output to the log if it's a persisted command.
+func (r teeRunner) tee(args []string, opts []exec.CmdOption) []exec.CmdOption {
+	if len(args) == 0 || !persistedCommands[args[0]] {
+		return opts
+	}
+	fmt.Fprintf(r.log, "==> %s docker %s\n", r.now().UTC().Format(time.RFC3339), strings.Join(redactArgs(args), " "))
+	return append(opts, func(cmd *osexec.Cmd) {
+		cmd.Stdout = teeWriter(cmd.Stdout, r.log)
+		cmd.Stderr = teeWriter(cmd.Stderr, r.log)
+	})
+}
+
+func teeWriter(w, logw io.Writer) io.Writer {
+	if w == nil {
+		return logw
+	}
+	return io.MultiWriter(w, logw)
+}
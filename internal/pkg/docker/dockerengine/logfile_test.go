@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingLog(t *testing.T) {
+	t.Run("rotates the log file once it's full and keeps the newest files", func(t *testing.T) {
+		dir := t.TempDir()
+		l, err := NewRotatingLog(LogPersistenceOptions{Dir: dir, Service: "web", MaxSize: 10, MaxFiles: 2})
+		require.NoError(t, err)
+		now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+		l.now = func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}
+
+		for _, line := range []string{"build 1\n", "build 2\n", "build 3\n", "build 4\n"} {
+			_, err := l.Write([]byte(line))
+			require.NoError(t, err)
+		}
+		require.NoError(t, l.Close())
+
+		entries, err := os.ReadDir(filepath.Join(dir, "web"))
+		require.NoError(t, err)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		require.Equal(t, []string{
+			"docker-20231001T120003.000000000.log",
+			"docker-20231001T120005.000000000.log",
+			"docker.log",
+		}, names)
+		current, err := os.ReadFile(filepath.Join(dir, "web", "docker.log"))
+		require.NoError(t, err)
+		require.Equal(t, "build 4\n", string(current))
+	})
+	t.Run("appends to the existing log file and removes expired rotated files", func(t *testing.T) {
+		dir := t.TempDir()
+		serviceDir := filepath.Join(dir, "web")
+		require.NoError(t, os.MkdirAll(serviceDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "docker.log"), []byte("old\n"), 0644))
+		expired := filepath.Join(serviceDir, "docker-20230901T120000.000000000.log")
+		require.NoError(t, os.WriteFile(expired, nil, 0644))
+		require.NoError(t, os.Chtimes(expired, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+		l, err := NewRotatingLog(LogPersistenceOptions{Dir: dir, Service: "web", MaxAge: 24 * time.Hour})
+		require.NoError(t, err)
+		_, err = l.Write([]byte("new\n"))
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+
+		require.NoFileExists(t, expired)
+		current, err := os.ReadFile(filepath.Join(serviceDir, "docker.log"))
+		require.NoError(t, err)
+		require.Equal(t, "old\nnew\n", string(current))
+	})
+}
+
+func TestDockerCommand_WithLogPersistence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"build", "--build-arg", "TOKEN=secret", "."}, gomock.Any(), gomock.Any()).
+		Do(mockStdout("Successfully built abc\n")).Return(nil)
+	m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "web"}, gomock.Any()).
+		Do(mockStdout(`[{"Id":"sha256:abc"}]`)).Return(nil)
+	dir := t.TempDir()
+	c, cleanup, err := DockerCmdClient{
+		runner: m,
+	}.WithLogPersistence(LogPersistenceOptions{Dir: dir, Service: "web"})
+	require.NoError(t, err)
+	tee := c.runner.(teeRunner)
+	tee.now = func() time.Time { return time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC) }
+	c.runner = tee
+	out := &bytes.Buffer{}
+
+	require.NoError(t, c.runner.RunWithContext(context.Background(), "docker", []string{"build", "--build-arg", "TOKEN=secret", "."}, exec.Stdout(out)))
+	require.NoError(t, c.runner.RunWithContext(context.Background(), "docker", []string{"image", "inspect", "web"}, exec.Stdout(&bytes.Buffer{})))
+	require.NoError(t, cleanup())
+
+	require.Equal(t, "Successfully built abc\n", out.String())
+	logs, err := os.ReadFile(filepath.Join(dir, "web", "docker.log"))
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"==> 2023-10-01T12:00:00Z docker build --build-arg TOKEN=<redacted> .",
+		"Successfully built abc",
+		"",
+	}, "\n"), string(logs))
+}
@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// Platform is the platform of an image that was built and pushed separately, to be published in a manifest list.
+type Platform struct {
+	OS      string // Required. For example "linux".
+	Arch    string // Required. For example "arm64".
+	Variant string // Optional. For example "v8".
+	Image   string // Required. Reference of the image built for the platform, for example "<uri>:v1-arm64".
+}
+
+// String returns the platform in the format "os/arch[/variant]".
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return p.OS + "/" + p.Arch
+	}
+	return p.OS + "/" + p.Arch + "/" + p.Variant
+}
+
+// CreateManifestList publishes the images of platforms under each tag of uri with a manifest list,
+// so that clients pull the image matching their platform, and returns the digest of the manifest list.
+// The images of the platforms must already be pushed to the registry.
+func (c DockerCmdClient) CreateManifestList(ctx context.Context, uri string, tags []string, platforms []Platform) (digest string, err error) {
+	if len(tags) == 0 {
+		return "", &errEmptyImageTags{
+			uri: uri,
+		}
+	}
+	if len(platforms) == 0 {
+		return "", errors.New("a manifest list requires at least one platform")
+	}
+	if err := c.checkPolicy(uri, tags...); err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		list := imageName(uri, tag)
+		create := []string{"manifest", "create", "--amend", list}
+		for _, p := range platforms {
+			create = append(create, p.Image)
+		}
+		if err := c.runner.RunWithContext(ctx, "docker", create); err != nil {
+			return "", fmt.Errorf("create manifest list %s: %w", list, err)
+		}
+		for _, p := range platforms {
+			annotate := []string{"manifest", "annotate", "--os", p.OS, "--arch", p.Arch}
+			if p.Variant != "" {
+				annotate = append(annotate, "--variant", p.Variant)
+			}
+			annotate = append(annotate, list, p.Image)
+			if err := c.runner.RunWithContext(ctx, "docker", annotate); err != nil {
+				return "", fmt.Errorf("annotate image %s with platform %s in manifest list %s: %w", p.Image, p, list, err)
+			}
+		}
+		buf := &strings.Builder{}
+		// --purge removes the local copy of the manifest list, so that it's created from scratch on the next push.
+		if err := c.runner.RunWithContext(ctx, "docker", []string{"manifest", "push", "--purge", list}, exec.Stdout(buf)); err != nil {
+			return "", fmt.Errorf("push manifest list %s: %w", list, err)
+		}
+		// The manifest list has the same digest regardless of its tag.
+		digest = strings.TrimSpace(buf.String())
+	}
+	return digest, nil
+}
@@ -0,0 +1,85 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_CreateManifestList(t *testing.T) {
+	const uri = "aws_account_id.dkr.ecr.region.amazonaws.com/web"
+	platforms := []Platform{
+		{OS: "linux", Arch: "amd64", Image: uri + ":v1-amd64"},
+		{OS: "linux", Arch: "arm64", Variant: "v8", Image: uri + ":v1-arm64"},
+	}
+	tests := map[string]struct {
+		tags      []string
+		platforms []Platform
+		setupMock func(m *MockCmd)
+
+		wantedDigest string
+		wantedErr    string
+	}{
+		"creates, annotates and pushes the manifest list for each tag": {
+			tags:      []string{"v1", "latest"},
+			platforms: platforms,
+			setupMock: func(m *MockCmd) {
+				for _, tag := range []string{"v1", "latest"} {
+					list := uri + ":" + tag
+					gomock.InOrder(
+						m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"manifest", "create", "--amend", list, uri + ":v1-amd64", uri + ":v1-arm64"}).Return(nil),
+						m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"manifest", "annotate", "--os", "linux", "--arch", "amd64", list, uri + ":v1-amd64"}).Return(nil),
+						m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"manifest", "annotate", "--os", "linux", "--arch", "arm64", "--variant", "v8", list, uri + ":v1-arm64"}).Return(nil),
+						m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"manifest", "push", "--purge", list}, gomock.Any()).
+							Do(mockStdout("sha256:abc\n")).Return(nil),
+					)
+				}
+			},
+			wantedDigest: "sha256:abc",
+		},
+		"errors without tags": {
+			platforms: platforms,
+			setupMock: func(m *MockCmd) {},
+			wantedErr: "tags to reference an image should not be empty for building and pushing into the ECR repository " + uri,
+		},
+		"errors without platforms": {
+			tags:      []string{"v1"},
+			setupMock: func(m *MockCmd) {},
+			wantedErr: "a manifest list requires at least one platform",
+		},
+		"returns a wrapped error if an annotation fails": {
+			tags:      []string{"v1"},
+			platforms: platforms[1:],
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"manifest", "create", "--amend", uri + ":v1", uri + ":v1-arm64"}).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "annotate image " + uri + ":v1-arm64 with platform linux/arm64/v8 in manifest list " + uri + ":v1: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			c := DockerCmdClient{
+				runner: m,
+			}
+
+			digest, err := c.CreateManifestList(context.Background(), uri, tc.tags, tc.platforms)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedDigest, digest)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BuildMetrics holds the metrics of a single build.
+type BuildMetrics struct {
+	Image       string        // Name of the image, for example "web:latest".
+	StartedAt   time.Time     // Wall clock time when the build started.
+	Duration    time.Duration // Wall time of the build.
+	Steps       int           // Number of steps of the Dockerfile that ran.
+	CachedSteps int           // Number of steps reused from the build cache.
+	ContextSize int64         // Size in bytes of the build context sent to the daemon, 0 if unknown.
+	Err         error         // Set if the build failed.
+}
+
+// CacheHitRatio returns the ratio of the steps reused from the build cache, between 0 and 1.
+func (m *BuildMetrics) CacheHitRatio() float64 {
+	if m.Steps == 0 {
+		return 0
+	}
+	return float64(m.CachedSteps) / float64(m.Steps)
+}
+
+// MetricsSink receives the metrics of builds, for example to publish them to a monitoring service.
+// Implementations must be safe for concurrent use when they're shared by concurrent builds.
+type MetricsSink interface {
+	RecordBuild(m *BuildMetrics)
+}
+
+// MetricsSinkFunc is a function that implements MetricsSink.
+type MetricsSinkFunc func(m *BuildMetrics)
+
+// RecordBuild calls f(m).
+func (f MetricsSinkFunc) RecordBuild(m *BuildMetrics) {
+	f(m)
+}
+
+// BuildWithMetrics builds the image like Build and sends the metrics of the build to sink, whether it succeeds or not.
+func (c DockerCmdClient) BuildWithMetrics(ctx context.Context, in *BuildArguments, w io.Writer, sink MetricsSink) error {
+	counter := &buildStepCounter{}
+	start := time.Now()
+	err := c.Build(ctx, in, io.MultiWriter(w, counter))
+	counter.flush()
+	m := &BuildMetrics{
+		Image:       in.displayName(),
+		StartedAt:   start,
+		Duration:    time.Since(start),
+		Steps:       counter.steps(),
+		CachedSteps: counter.cachedSteps(),
+		ContextSize: counter.buildContextSize(in),
+		Err:         err,
+	}
+	sink.RecordBuild(m)
+	return err
+}
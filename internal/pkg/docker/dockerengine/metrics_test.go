@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_BuildWithMetrics(t *testing.T) {
+	ctx := context.Background()
+	output := `#1 [internal] load build definition from Dockerfile
+#3 [internal] load build context
+#3 transferring context: 512B
+#3 transferring context: 1.50MB 0.1s done
+#5 [build 1/2] FROM docker.io/library/golang:1.20
+#5 CACHED
+#6 [build 2/2] RUN go build -o /app
+`
+
+	tests := map[string]struct {
+		buildErr error
+
+		wantedErr string
+	}{
+		"records the metrics of a successful build": {},
+		"records the metrics of a failed build": {
+			buildErr:  errors.New("exit status 1"),
+			wantedErr: "building image: exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+				Do(mockStdout(output)).Return(tc.buildErr)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}
+			var got *BuildMetrics
+
+			err := s.BuildWithMetrics(ctx, &BuildArguments{
+				URI:        "web",
+				Tags:       []string{"latest"},
+				Dockerfile: filepath.Join(t.TempDir(), "Dockerfile"),
+			}, &strings.Builder{}, MetricsSinkFunc(func(m *BuildMetrics) {
+				got = m
+			}))
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				require.EqualError(t, got.Err, tc.wantedErr)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, got.Err)
+			}
+			require.Equal(t, "web:latest", got.Image)
+			require.Equal(t, 2, got.Steps)
+			require.Equal(t, 1, got.CachedSteps)
+			require.Equal(t, 0.5, got.CacheHitRatio())
+			require.Equal(t, int64(1_500_000), got.ContextSize)
+			require.NotZero(t, got.Duration)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// RegistryConfig holds the registries that the client pulls through or reaches without TLS verification.
+type RegistryConfig struct {
+	Mirror   string   // Optional. Registry mirror that Docker Hub images are pulled through, for example "https://mirror.example.com".
+	Insecure []string // Optional. Registries served over plain HTTP or with untrusted certificates, for example "registry.test:5000".
+}
+
+// daemonRegistryConfig is the subset of the registry configuration of the daemon printed by `docker info` that we care about.
+type daemonRegistryConfig struct {
+	InsecureRegistryCIDRs []string `json:"InsecureRegistryCIDRs"`
+	IndexConfigs          map[string]struct {
+		Secure bool `json:"Secure"`
+	} `json:"IndexConfigs"`
+	Mirrors []string `json:"Mirrors"`
+}
+
+// ErrInsecureRegistryNotAllowed means a registry marked as insecure isn't allowed to be reached insecurely by the daemon.
+type ErrInsecureRegistryNotAllowed struct {
+	Registry string
+}
+
+func (e *ErrInsecureRegistryNotAllowed) Error() string {
+	return fmt.Sprintf("registry %s is not configured as an insecure registry of the docker daemon", e.Registry)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrInsecureRegistryNotAllowed) RecommendActions() string {
+	return fmt.Sprintf(`Add %q to "insecure-registries" in the daemon.json file of the docker daemon, and restart the daemon.`, e.Registry)
+}
+
+// WithRegistryConfig returns a copy of the client that pulls Docker Hub images through the mirror of cfg.
+// The configuration is validated against the daemon's: insecure registries must be allowed by the daemon,
+// since docker commands can't skip TLS verification on their own. If the daemon already routes Docker Hub pulls
+// through the mirror, images are pulled by their original reference.
+func (c DockerCmdClient) WithRegistryConfig(ctx context.Context, cfg RegistryConfig) (DockerCmdClient, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"info", "--format", "{{json .RegistryConfig}}"}, exec.Stdout(buf)); err != nil {
+		return DockerCmdClient{}, fmt.Errorf("get docker registry configuration: %w", err)
+	}
+	var daemon daemonRegistryConfig
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &daemon); err != nil {
+		return DockerCmdClient{}, fmt.Errorf("unmarshal docker registry configuration: %w", err)
+	}
+	for _, registry := range cfg.Insecure {
+		if !daemon.allowsInsecure(registry) {
+			return DockerCmdClient{}, &ErrInsecureRegistryNotAllowed{
+				Registry: registry,
+			}
+		}
+	}
+	c.mirror = ""
+	if cfg.Mirror != "" && !daemon.hasMirror(cfg.Mirror) {
+		c.mirror = mirrorHost(cfg.Mirror)
+	}
+	return c, nil
+}
+
+// allowsInsecure returns true if the daemon reaches the registry without TLS verification.
+func (d *daemonRegistryConfig) allowsInsecure(registry string) bool {
+	if index, ok := d.IndexConfigs[registry]; ok && !index.Secure {
+		return true
+	}
+	host, _, err := net.SplitHostPort(registry)
+	if err != nil {
+		host = registry
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range d.InsecureRegistryCIDRs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMirror returns true if the daemon pulls Docker Hub images through the mirror.
+func (d *daemonRegistryConfig) hasMirror(mirror string) bool {
+	for _, m := range d.Mirrors {
+		if mirrorHost(m) == mirrorHost(mirror) {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorHost returns the host of a mirror URL like "https://mirror.example.com/".
+func mirrorHost(mirror string) string {
+	if _, host, ok := strings.Cut(mirror, "://"); ok {
+		mirror = host
+	}
+	return strings.TrimSuffix(mirror, "/")
+}
+
+// mirrored returns the reference of the image in the mirror if it's a Docker Hub image and the client has a mirror,
+// like "mirror.example.com/library/nginx:1.25" for "nginx:1.25". Otherwise, it returns ref unchanged.
+func (c DockerCmdClient) mirrored(ref string) string {
+	if c.mirror == "" {
+		return ref
+	}
+	if registry, _ := parseImageRef(ref); registry != defaultRegistry {
+		return ref
+	}
+	return c.mirror + "/" + dockerHubRepository(ref)
+}
+
+// dockerHubRepository returns the reference of a Docker Hub image without its registry,
+// like "library/nginx:1.25" for "nginx:1.25" or "bitnami/redis:7.2" for "docker.io/bitnami/redis:7.2".
+func dockerHubRepository(ref string) string {
+	repo := strings.TrimPrefix(ref, defaultRegistry+"/")
+	if !strings.Contains(strings.SplitN(repo, ":", 2)[0], "/") {
+		// Official images are in the "library" namespace.
+		repo = "library/" + repo
+	}
+	return repo
+}
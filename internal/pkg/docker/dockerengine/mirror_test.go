@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_WithRegistryConfig(t *testing.T) {
+	ctx := context.Background()
+	infoArgs := []string{"info", "--format", "{{json .RegistryConfig}}"}
+	daemonConfig := `{"InsecureRegistryCIDRs":["127.0.0.0/8","10.0.0.0/16"],"IndexConfigs":{"docker.io":{"Name":"docker.io","Secure":true},"registry.test:5000":{"Name":"registry.test:5000","Secure":false}},"Mirrors":["https://daemon-mirror.example.com/"]}`
+	tests := map[string]struct {
+		cfg       RegistryConfig
+		setupMock func(m *MockCmd)
+
+		wantedMirror string
+		wantedErr    string
+	}{
+		"accepts registries that the daemon marks as insecure by name or by CIDR": {
+			cfg: RegistryConfig{
+				Insecure: []string{"registry.test:5000", "10.0.3.4:5000", "127.0.0.1"},
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Do(mockStdout(daemonConfig)).Return(nil)
+			},
+		},
+		"returns ErrInsecureRegistryNotAllowed if the daemon verifies the registry": {
+			cfg: RegistryConfig{
+				Insecure: []string{"registry.test:5000", "10.1.0.1:5000"},
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Do(mockStdout(daemonConfig)).Return(nil)
+			},
+			wantedErr: "registry 10.1.0.1:5000 is not configured as an insecure registry of the docker daemon",
+		},
+		"pulls through a mirror that the daemon doesn't know about": {
+			cfg: RegistryConfig{
+				Mirror: "https://mirror.example.com/",
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Do(mockStdout(daemonConfig)).Return(nil)
+			},
+			wantedMirror: "mirror.example.com",
+		},
+		"leaves pulls unchanged if the daemon already uses the mirror": {
+			cfg: RegistryConfig{
+				Mirror: "https://daemon-mirror.example.com",
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Do(mockStdout(daemonConfig)).Return(nil)
+			},
+		},
+		"wraps errors from docker info": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "get docker registry configuration: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.WithRegistryConfig(ctx, tc.cfg)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedMirror, got.mirror)
+		})
+	}
+}
+
+func TestDockerCommand_PullThroughMirror(t *testing.T) {
+	tests := map[string]struct {
+		image      string
+		wantedPull string
+	}{
+		"adds the library namespace to official images": {
+			image:      "nginx:1.25",
+			wantedPull: "mirror.example.com/library/nginx:1.25",
+		},
+		"keeps the namespace of other Docker Hub images": {
+			image:      "docker.io/bitnami/redis:7.2",
+			wantedPull: "mirror.example.com/bitnami/redis:7.2",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			gomock.InOrder(
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"pull", tc.wantedPull}, gomock.Any(), gomock.Any()).Return(nil),
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", tc.wantedPull, tc.image}).Return(nil),
+			)
+			s := DockerCmdClient{
+				runner: m,
+				mirror: "mirror.example.com",
+			}
+
+			require.NoError(t, s.Pull(ctx, tc.image, PullOptions{}))
+		})
+	}
+
+	t.Run("pulls images of other registries directly", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"pull", "public.ecr.aws/nginx/nginx:1.25"}, gomock.Any(), gomock.Any()).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+			mirror: "mirror.example.com",
+		}
+
+		require.NoError(t, s.Pull(ctx, "public.ecr.aws/nginx/nginx:1.25", PullOptions{}))
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	ociLayoutFile = "oci-layout" // Marker file at the root of every OCI image layout directory.
+	ociIndexFile  = "index.json" // Entrypoint of the image references in an OCI image layout directory.
+
+	loadedImagePrefix   = "Loaded image: "
+	loadedImageIDPrefix = "Loaded image ID: "
+)
+
+// LoadOCILayout imports the images of an OCI image layout directory, for example one produced by
+// `docker buildx build --output type=oci,tar=false`, into the daemon and returns the loaded image references.
+func (c DockerCmdClient) LoadOCILayout(ctx context.Context, path string) ([]string, error) {
+	for _, name := range []string{ociLayoutFile, ociIndexFile} {
+		if _, err := os.Stat(filepath.Join(path, name)); err != nil {
+			return nil, &errNotOCILayout{
+				path: path,
+				err:  err,
+			}
+		}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarDirectory(path, pw))
+	}()
+	defer pr.Close()
+
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"load"}, exec.Stdin(pr), exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("docker load OCI layout %s: %w", path, err)
+	}
+	return parseLoadedImages(buf.String()), nil
+}
+
+// tarDirectory writes the content of dir as a tar stream to w, with paths relative to dir.
+func tarDirectory(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			// OCI layouts only contain regular files and directories.
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("archive directory %s: %w", dir, err)
+	}
+	return tw.Close()
+}
+
+// parseLoadedImages returns the image references printed by `docker load`.
+func parseLoadedImages(out string) []string {
+	var refs []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, loadedImagePrefix):
+			refs = append(refs, strings.TrimPrefix(line, loadedImagePrefix))
+		case strings.HasPrefix(line, loadedImageIDPrefix):
+			refs = append(refs, strings.TrimPrefix(line, loadedImageIDPrefix))
+		}
+	}
+	return refs
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_LoadOCILayout(t *testing.T) {
+	ctx := context.Background()
+	newLayout := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ociLayoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ociIndexFile), []byte(`{"schemaVersion":2}`), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", "abc"), []byte("layer"), 0644))
+		return dir
+	}
+
+	t.Run("returns an error if the directory is not an OCI layout", func(t *testing.T) {
+		dir := t.TempDir()
+		s := DockerCmdClient{}
+
+		_, err := s.LoadOCILayout(ctx, dir)
+
+		var target *errNotOCILayout
+		require.True(t, errors.As(err, &target))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("streams the layout to docker load and returns the loaded images", func(t *testing.T) {
+		dir := newLayout(t)
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var files []string
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"load"}, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+				cmd := &osexec.Cmd{}
+				for _, opt := range opts {
+					opt(cmd)
+				}
+				tr := tar.NewReader(cmd.Stdin)
+				for {
+					hdr, err := tr.Next()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					files = append(files, hdr.Name)
+				}
+				_, _ = cmd.Stdout.Write([]byte("Loaded image: mockURI:latest\nLoaded image ID: sha256:abc\n"))
+			}).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		got, err := s.LoadOCILayout(ctx, dir)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"mockURI:latest", "sha256:abc"}, got)
+		require.ElementsMatch(t, []string{"blobs", "blobs/sha256", "blobs/sha256/abc", ociIndexFile, ociLayoutFile}, files)
+	})
+	t.Run("returns a wrapped error if docker load fails", func(t *testing.T) {
+		dir := newLayout(t)
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"load"}, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		_, err := s.LoadOCILayout(ctx, dir)
+
+		require.EqualError(t, err, "docker load OCI layout "+dir+": some error")
+	})
+}
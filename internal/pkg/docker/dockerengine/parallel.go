@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// BuildAll builds the images concurrently, running at most concurrency builds at a time.
+// The output of each build is written to w line by line, prefixed with the name of the image being built.
+// BuildAll waits for all builds to complete and returns the errors of all the builds that failed.
+func (c DockerCmdClient) BuildAll(ctx context.Context, builds []*BuildArguments, concurrency int, w io.Writer) error {
+	return c.BuildAllWithSink(ctx, builds, concurrency, NewPrefixSink(w))
+}
+
+// BuildAllWithSink is like BuildAll but sends the output of the builds to sink line by line.
+func (c DockerCmdClient) BuildAllWithSink(ctx context.Context, builds []*BuildArguments, concurrency int, sink LineSink) error {
+	if concurrency <= 0 {
+		concurrency = len(builds)
+	}
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex // Guards errs.
+		errs []error
+	)
+	for _, build := range builds {
+		build := build
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("build %s: %w", build.displayName(), ctx.Err()))
+				mu.Unlock()
+				return
+			}
+			if err := c.BuildWithSink(ctx, build, sink); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("build %s: %w", build.displayName(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// displayName returns the name used to identify the image in logs.
+func (in *BuildArguments) displayName() string {
+	if len(in.Tags) == 0 {
+		return in.URI
+	}
+	return imageName(in.URI, in.Tags[0])
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	osexec "os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_BuildAll(t *testing.T) {
+	ctx := context.Background()
+	builds := []*BuildArguments{
+		{URI: "mockURI", Tags: []string{"latest"}, Dockerfile: "web/Dockerfile"},
+		{URI: "mockURI", Tags: []string{"logging-latest"}, Dockerfile: "logging/Dockerfile"},
+		{URI: "mockURI", Tags: []string{"proxy-latest"}, Dockerfile: "proxy/Dockerfile"},
+	}
+
+	t.Run("builds with bounded concurrency and prefixes the output of each build", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var running, maxRunning int32
+		m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ string, args []string, opts ...exec.CmdOption) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+				cmd := &osexec.Cmd{}
+				for _, opt := range opts {
+					opt(cmd)
+				}
+				_, _ = cmd.Stdout.Write([]byte(fmt.Sprintf("building %s\nstep", args[2])))
+				_, _ = cmd.Stdout.Write([]byte(" 1\n"))
+			}).Return(nil).Times(3)
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+		out := &strings.Builder{}
+
+		err := s.BuildAll(ctx, builds, 2, out)
+
+		require.NoError(t, err)
+		require.LessOrEqual(t, maxRunning, int32(2))
+		for _, name := range []string{"mockURI:latest", "mockURI:logging-latest", "mockURI:proxy-latest"} {
+			require.Contains(t, out.String(), fmt.Sprintf("[%s] building %s\n", name, name))
+			require.Contains(t, out.String(), fmt.Sprintf("[%s] step 1\n", name))
+		}
+	})
+	t.Run("returns the errors of all failed builds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var mu sync.Mutex
+		m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, args []string, _ ...exec.CmdOption) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if args[2] == "mockURI:latest" {
+					return nil
+				}
+				return errors.New("some error")
+			}).Times(3)
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+
+		err := s.BuildAll(ctx, builds, 0, &strings.Builder{})
+
+		require.ErrorContains(t, err, "build mockURI:logging-latest: building image: some error")
+		require.ErrorContains(t, err, "build mockURI:proxy-latest: building image: some error")
+		require.NotContains(t, err.Error(), "build mockURI:latest:")
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import "fmt"
+
+// PlannedDigest stands for the digest of an image in the commands of a plan that run after the image is pushed.
+const PlannedDigest = "<digest>"
+
+// PlanBuild returns the commands that Build would run for the arguments, in order, without running anything.
+// Each command is the full argv, starting with "docker", or with "pack" for builds with Cloud Native Buildpacks.
+// Secrets stored in SSM or Secrets Manager aren't fetched: they're mounted from the files that Build would write them to,
+// in a temporary directory whose random suffix is rendered as "*".
+func (c DockerCmdClient) PlanBuild(in *BuildArguments) ([][]string, error) {
+	if in.Buildpacks != nil {
+		args, err := in.packArgs()
+		if err != nil {
+			return nil, fmt.Errorf("generate pack build args: %w", err)
+		}
+		return [][]string{append([]string{"pack"}, args...)}, nil
+	}
+	args, err := in.plannedSecrets().GenerateDockerBuildArgs(c)
+	if err != nil {
+		return nil, fmt.Errorf("generate docker build args: %w", err)
+	}
+	var cmds [][]string
+	if in.Squash {
+		cmds = append(cmds, dockerCommand(squashSupportArgs))
+	}
+	return append(cmds, dockerCommand(args)), nil
+}
+
+// PlanPush returns the commands that Push would run for the repository and tags, in order, without running anything.
+// Each command is the full argv, starting with "docker", or with the signing tool if the client signs images.
+// Conditional commands are included as if they all ran:
+//   - If the client skips unchanged pushes, each tag is inspected locally and in the registry, and only pushed if it changed.
+//   - The command that reads the digest of the image after the pushes only runs if the pushes don't print it.
+//
+// The digest of the image isn't known before it's pushed, so the signing commands reference it as PlannedDigest.
+func (c DockerCmdClient) PlanPush(uri string, tags ...string) [][]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	var cmds [][]string
+	for _, img := range imageNames(uri, tags) {
+		if c.skipUnchanged {
+			cmds = append(cmds, dockerCommand(imageInspectArgs(img)), dockerCommand(manifestInspectArgs(img)))
+		}
+		cmds = append(cmds, dockerCommand(c.pushArgs(img)))
+	}
+	cmds = append(cmds, dockerCommand(repoDigestArgs(imageName(uri, tags[0]))))
+	if c.signing != nil {
+		cmds = append(cmds, c.signing.commands(uri+"@"+PlannedDigest)...)
+	}
+	if c.removeAfterPush {
+		cmds = append(cmds, dockerCommand(removeImageArgs(imageNames(uri, tags))))
+	}
+	return cmds
+}
+
+// PlanRun returns the command that Run would run for the options without running anything.
+// The command is the full argv, starting with "docker".
+// The image isn't inspected, so the path of libfaketime is rendered as PlannedFakeTimeLibPath if it depends on an unknown architecture.
+func (c DockerCmdClient) PlanRun(options *RunOptions) []string {
+	return dockerCommand(c.runArgs(options))
+}
+
+func dockerCommand(args []string) []string {
+	return append([]string{"docker"}, args...)
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Plan(t *testing.T) {
+	s := DockerCmdClient{
+		lookupEnv: func(key string) (string, bool) {
+			if key == "CI" {
+				return "true", true
+			}
+			return "", false
+		},
+	}
+
+	t.Run("build", func(t *testing.T) {
+		got, err := s.PlanBuild(&BuildArguments{
+			URI:        "mockURI",
+			Tags:       []string{"latest"},
+			Dockerfile: "web/Dockerfile",
+			Squash:     true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"docker", "version", "-f", "{{json .Server.Experimental}}"},
+			{"docker", "build", "-t", "mockURI:latest", "--squash", "--progress", "plain", "web", "-f", "web/Dockerfile"},
+		}, got)
+	})
+	t.Run("build with invalid arguments", func(t *testing.T) {
+		_, err := s.PlanBuild(&BuildArguments{URI: "mockURI"})
+
+		require.EqualError(t, err, "generate docker build args: tags to reference an image should not be empty for building and pushing into the ECR repository mockURI")
+	})
+	t.Run("build with secrets from secret stores", func(t *testing.T) {
+		got, err := s.PlanBuild(&BuildArguments{
+			URI:        "mockURI",
+			Tags:       []string{"latest"},
+			Dockerfile: "web/Dockerfile",
+			Secrets: map[string]string{
+				"npmrc": "/home/user/.npmrc",
+				"token": "ssm://github-token",
+				"key":   "secretsmanager://deploy-key",
+			},
+		})
+
+		require.NoError(t, err)
+		dir := filepath.Join(os.TempDir(), "copilot-build-secrets-*")
+		require.Equal(t, [][]string{
+			{"docker", "build", "-t", "mockURI:latest",
+				"--secret", "id=key,src=" + filepath.Join(dir, "secret-0"),
+				"--secret", "id=npmrc,src=/home/user/.npmrc",
+				"--secret", "id=token,src=" + filepath.Join(dir, "secret-1"),
+				"--progress", "plain", "web", "-f", "web/Dockerfile"},
+		}, got)
+	})
+	t.Run("build with buildpacks", func(t *testing.T) {
+		got, err := s.PlanBuild(&BuildArguments{
+			URI:        "mockURI",
+			Tags:       []string{"latest", "v1"},
+			Context:    "web",
+			Dockerfile: "web/Dockerfile",
+			Buildpacks: &BuildpacksOptions{
+				Buildpacks: []string{"paketo-buildpacks/go"},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"pack", "build", "mockURI:latest", "--path", "web", "--builder", DefaultBuildpacksBuilder,
+				"--buildpack", "paketo-buildpacks/go", "--tag", "mockURI:v1"},
+		}, got)
+	})
+	t.Run("push", func(t *testing.T) {
+		got := s.PlanPush("mockURI", "latest", "v1")
+
+		require.Equal(t, [][]string{
+			{"docker", "push", "mockURI:latest", "--quiet"},
+			{"docker", "push", "mockURI:v1", "--quiet"},
+			{"docker", "inspect", "--format", "'{{json .RepoDigests}}'", "mockURI:latest"},
+		}, got)
+	})
+	t.Run("push signed without unchanged tags and clean up", func(t *testing.T) {
+		got := s.WithSkipUnchangedPushes().WithRemoveAfterPush().
+			WithSigning(Signing{Tool: SigningToolNotation, Key: "arn:aws:signer:us-west-2:123456789012:/signing-profiles/app"}).
+			PlanPush("mockURI", "latest", "v1")
+
+		require.Equal(t, [][]string{
+			{"docker", "image", "inspect", "mockURI:latest"},
+			{"docker", "manifest", "inspect", "--verbose", "mockURI:latest"},
+			{"docker", "push", "mockURI:latest", "--quiet"},
+			{"docker", "image", "inspect", "mockURI:v1"},
+			{"docker", "manifest", "inspect", "--verbose", "mockURI:v1"},
+			{"docker", "push", "mockURI:v1", "--quiet"},
+			{"docker", "inspect", "--format", "'{{json .RepoDigests}}'", "mockURI:latest"},
+			{"notation", "inspect", "--output", "json", "mockURI@<digest>"},
+			{"notation", "sign", "--plugin", "com.amazonaws.signer.notation.plugin", "--id", "arn:aws:signer:us-west-2:123456789012:/signing-profiles/app", "mockURI@<digest>"},
+			{"notation", "inspect", "--output", "json", "mockURI@<digest>"},
+			{"docker", "image", "rm", "mockURI:latest", "mockURI:v1"},
+		}, got)
+	})
+	t.Run("push signed with cosign", func(t *testing.T) {
+		got := s.WithSigning(Signing{Tool: SigningToolCosign}).PlanPush("mockURI", "latest")
+
+		require.Equal(t, [][]string{
+			{"docker", "push", "mockURI:latest", "--quiet"},
+			{"docker", "inspect", "--format", "'{{json .RepoDigests}}'", "mockURI:latest"},
+			{"cosign", "sign", "--yes", "mockURI@<digest>"},
+		}, got)
+	})
+	t.Run("run", func(t *testing.T) {
+		got := s.PlanRun(&RunOptions{
+			ImageURI:      "mockURI:latest",
+			ContainerName: "web",
+			Command:       []string{"serve"},
+		})
+
+		require.Equal(t, []string{"docker", "run", "--name", "web", "mockURI:latest", "serve"}, got)
+	})
+}
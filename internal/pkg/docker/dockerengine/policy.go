@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	latestTag       = "latest"
+)
+
+// ImagePolicy validates the references of images, like "<uri>:<tag>", before they're pushed.
+type ImagePolicy interface {
+	Validate(ref string) error
+}
+
+// ImageRefPolicy is an ImagePolicy configured with rules on the registries and the tags of images,
+// so that platform teams can enforce naming standards through configuration.
+type ImageRefPolicy struct {
+	AllowedRegistries []string // Optional. Registries that images can be pushed to, for example "123456789012.dkr.ecr.us-west-2.amazonaws.com". Defaults to any registry.
+	TagPattern        string   // Optional. Regular expression that tags must match, for example `^v\d+\.\d+\.\d+$`.
+	ForbidLatest      bool     // Optional. Forbids the "latest" tag, which is also the tag of references without one.
+}
+
+// ErrPolicyViolation means the reference of an image violates the image policy.
+type ErrPolicyViolation struct {
+	Ref    string
+	Reason string
+}
+
+func (e *ErrPolicyViolation) Error() string {
+	return fmt.Sprintf("image %s violates the image policy: %s", e.Ref, e.Reason)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrPolicyViolation) RecommendActions() string {
+	return "Push the image to an allowed registry with a tag that complies with the image policy, or ask the owners of the policy to update it."
+}
+
+// Validate returns ErrPolicyViolation if ref violates one of the rules of the policy.
+func (p *ImageRefPolicy) Validate(ref string) error {
+	registry, tag := parseImageRef(ref)
+	if len(p.AllowedRegistries) != 0 && !containsString(p.AllowedRegistries, registry) {
+		return &ErrPolicyViolation{
+			Ref:    ref,
+			Reason: fmt.Sprintf("registry %s is not one of the allowed registries %s", registry, strings.Join(p.AllowedRegistries, ", ")),
+		}
+	}
+	if p.ForbidLatest && tag == latestTag {
+		return &ErrPolicyViolation{
+			Ref:    ref,
+			Reason: `the "latest" tag is forbidden`,
+		}
+	}
+	if p.TagPattern != "" {
+		re, err := regexp.Compile(p.TagPattern)
+		if err != nil {
+			return fmt.Errorf("compile tag pattern %q of the image policy: %w", p.TagPattern, err)
+		}
+		if !re.MatchString(tag) {
+			return &ErrPolicyViolation{
+				Ref:    ref,
+				Reason: fmt.Sprintf("tag %q doesn't match the pattern %s", tag, p.TagPattern),
+			}
+		}
+	}
+	return nil
+}
+
+// WithImagePolicy returns a copy of the client that validates the references of images with p before pushing them.
+func (c DockerCmdClient) WithImagePolicy(p ImagePolicy) DockerCmdClient {
+	c.policy = p
+	return c
+}
+
+// checkPolicy validates the references of the image tagged with each tag before any of them is pushed.
+func (c DockerCmdClient) checkPolicy(uri string, tags ...string) error {
+	if c.policy == nil {
+		return nil
+	}
+	for _, tag := range tags {
+		if err := c.policy.Validate(imageName(uri, tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseImageRef returns the registry and the tag of an image reference like "public.ecr.aws/nginx/nginx:1.25@sha256:...".
+// References without a registry are hosted on Docker Hub, and references without a tag are tagged "latest".
+func parseImageRef(ref string) (registry, tag string) {
+	ref, _, _ = strings.Cut(ref, "@")
+	registry = defaultRegistry
+	if first, rest, ok := strings.Cut(ref, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, ref = first, rest
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	if _, t, ok := strings.Cut(name, ":"); ok {
+		return registry, t
+	}
+	return registry, latestTag
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
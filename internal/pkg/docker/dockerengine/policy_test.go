@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRefPolicy_Validate(t *testing.T) {
+	const ecr = "123456789012.dkr.ecr.us-west-2.amazonaws.com"
+	tests := map[string]struct {
+		policy ImageRefPolicy
+		ref    string
+
+		wantedErr string
+	}{
+		"allows any reference without rules": {
+			ref: "nginx",
+		},
+		"allows a compliant reference": {
+			policy: ImageRefPolicy{AllowedRegistries: []string{ecr}, TagPattern: `^v\d+\.\d+\.\d+$`, ForbidLatest: true},
+			ref:    ecr + "/app/web:v1.2.3",
+		},
+		"rejects a registry that isn't allowed": {
+			policy:    ImageRefPolicy{AllowedRegistries: []string{ecr}},
+			ref:       "myorg/web:v1",
+			wantedErr: "image myorg/web:v1 violates the image policy: registry docker.io is not one of the allowed registries " + ecr,
+		},
+		"rejects a registry with a port that isn't allowed": {
+			policy:    ImageRefPolicy{AllowedRegistries: []string{ecr}},
+			ref:       "localhost:5000/web:v1",
+			wantedErr: "image localhost:5000/web:v1 violates the image policy: registry localhost:5000 is not one of the allowed registries " + ecr,
+		},
+		"rejects the implicit latest tag": {
+			policy:    ImageRefPolicy{ForbidLatest: true},
+			ref:       ecr + "/app/web",
+			wantedErr: "image " + ecr + `/app/web violates the image policy: the "latest" tag is forbidden`,
+		},
+		"rejects a tag that doesn't match the pattern": {
+			policy:    ImageRefPolicy{TagPattern: `^v\d+$`},
+			ref:       ecr + "/app/web:gitsha-1234567",
+			wantedErr: "image " + ecr + `/app/web:gitsha-1234567 violates the image policy: tag "gitsha-1234567" doesn't match the pattern ^v\d+$`,
+		},
+		"errors on an invalid pattern": {
+			policy:    ImageRefPolicy{TagPattern: `v(`},
+			ref:       "web:v1",
+			wantedErr: "compile tag pattern \"v(\" of the image policy: error parsing regexp: missing closing ): `v(`",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate(tc.ref)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDockerCommand_PushWithImagePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(gomock.Any(), "docker", gomock.Any(), gomock.Any()).Times(0)
+	c := DockerCmdClient{
+		runner: m,
+	}.WithImagePolicy(&ImageRefPolicy{ForbidLatest: true})
+
+	_, err := c.Push(context.Background(), "mockURI", io.Discard, "v1", "latest")
+
+	require.EqualError(t, err, `image mockURI:latest violates the image policy: the "latest" tag is forbidden`)
+}
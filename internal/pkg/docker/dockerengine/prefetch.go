@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+const defaultPrefetchConcurrency = 4
+
+// TaskSpec describes the containers of a task that runs locally.
+type TaskSpec struct {
+	Containers []*RunOptions
+	// Optional. Category of the sidecar containers keyed by container name, for example "logging".
+	// Used to start partial stacks with the compose backend. Containers without a category always start.
+	SidecarCategories map[string]string
+}
+
+// images returns the unique images referenced by the containers of the task, sorted.
+func (t *TaskSpec) images() []string {
+	seen := make(map[string]bool)
+	var images []string
+	for _, container := range t.Containers {
+		if container.ImageURI == "" || seen[container.ImageURI] {
+			continue
+		}
+		seen[container.ImageURI] = true
+		images = append(images, container.ImageURI)
+	}
+	sort.Strings(images)
+	return images
+}
+
+// Prefetch pulls the images referenced by the task that are missing from the local image store in parallel,
+// so that the containers can start right away once the task runs. A progress line is written to w as each image becomes available.
+// Images are pulled like Pull, so failed pulls return ErrRegistryRateLimited if the registry throttled them.
+func (c DockerCmdClient) Prefetch(ctx context.Context, spec *TaskSpec, w io.Writer) error {
+	images := spec.images()
+	var mu sync.Mutex
+	var done int
+	report := func(format string, img string) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		fmt.Fprintf(w, format+" (%d/%d)\n", img, done, len(images))
+	}
+
+	return pullInParallel(len(images), func(i int) error {
+		img := images[i]
+		if _, err := c.inspectLocalImage(ctx, img); err == nil {
+			report("Image %s is already present", img)
+			return nil
+		}
+		if err := c.Pull(ctx, img, PullOptions{Quiet: true}); err != nil {
+			return err
+		}
+		report("Pulled image %s", img)
+		return nil
+	})
+}
+
+// pullInParallel calls pull for each of the n images, with at most defaultPrefetchConcurrency pulls at a time.
+// A failed pull doesn't cancel the others, so that all the images that can be pulled are available for the next run.
+// The errors are joined in the order of the images.
+func pullInParallel(n int, pull func(i int) error) error {
+	sem := make(chan struct{}, defaultPrefetchConcurrency)
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = pull(i)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Prefetch(t *testing.T) {
+	spec := &TaskSpec{
+		Containers: []*RunOptions{
+			{ImageURI: "public.ecr.aws/nginx:latest"},
+			{ImageURI: "local:latest"},
+			{ImageURI: "public.ecr.aws/nginx:latest"},
+		},
+	}
+
+	t.Run("pulls each missing image once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "local:latest"}, gomock.Any()).
+			Do(mockStdout(`[{"Id":"sha256:abc"}]`)).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "public.ecr.aws/nginx:latest"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/nginx:latest"}, gomock.Any(), gomock.Any()).
+			Return(nil).Times(1)
+		s := DockerCmdClient{
+			runner: m,
+		}
+		out := &strings.Builder{}
+
+		err := s.Prefetch(context.Background(), spec, out)
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Image local:latest is already present")
+		require.Contains(t, out.String(), "Pulled image public.ecr.aws/nginx:latest")
+		require.Contains(t, out.String(), "(2/2)")
+	})
+	t.Run("returns a wrapped error if a pull fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "local:latest"}, gomock.Any()).
+			Do(mockStdout(`[{"Id":"sha256:abc"}]`)).Return(nil).AnyTimes()
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "public.ecr.aws/nginx:latest"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/nginx:latest"}, gomock.Any(), gomock.Any()).
+			Return(errors.New("some error"))
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		err := s.Prefetch(context.Background(), spec, &strings.Builder{})
+
+		require.EqualError(t, err, "docker pull public.ecr.aws/nginx:latest: some error")
+	})
+	t.Run("detects rate limited pulls", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "local:latest"}, gomock.Any()).
+			Do(mockStdout(`[{"Id":"sha256:abc"}]`)).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "public.ecr.aws/nginx:latest"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/nginx:latest"}, gomock.Any(), gomock.Any()).
+			Do(mockStderr("Error response from daemon: toomanyrequests: Rate exceeded\n")).Return(errors.New("exit status 1"))
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		err := s.Prefetch(context.Background(), spec, &strings.Builder{})
+
+		var rateLimited *ErrRegistryRateLimited
+		require.ErrorAs(t, err, &rateLimited)
+		require.Equal(t, "public.ecr.aws/nginx:latest", rateLimited.Image)
+	})
+	t.Run("pulls Docker Hub images from the mirror", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "nginx:1.25"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "mirror.example.com/library/nginx:1.25"}, gomock.Any(), gomock.Any()).
+			Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"tag", "mirror.example.com/library/nginx:1.25", "nginx:1.25"}).
+			Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+			mirror: "mirror.example.com",
+		}
+
+		err := s.Prefetch(context.Background(), &TaskSpec{
+			Containers: []*RunOptions{{ImageURI: "nginx:1.25"}},
+		}, &strings.Builder{})
+
+		require.NoError(t, err)
+	})
+	t.Run("lets the other pulls finish and joins the errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		spec := &TaskSpec{
+			Containers: []*RunOptions{
+				{ImageURI: "public.ecr.aws/nginx:latest"},
+				{ImageURI: "public.ecr.aws/redis:latest"},
+				{ImageURI: "public.ecr.aws/postgres:latest"},
+			},
+		}
+		for _, img := range []string{"public.ecr.aws/nginx:latest", "public.ecr.aws/redis:latest", "public.ecr.aws/postgres:latest"} {
+			m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", img}, gomock.Any()).
+				Return(errors.New("no such image"))
+		}
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/nginx:latest"}, gomock.Any(), gomock.Any()).
+			Return(errors.New("some error"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/redis:latest"}, gomock.Any(), gomock.Any()).
+			Return(errors.New("some other error"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "public.ecr.aws/postgres:latest"}, gomock.Any(), gomock.Any()).
+			Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+		}
+		out := &strings.Builder{}
+
+		err := s.Prefetch(context.Background(), spec, out)
+
+		require.EqualError(t, err, "docker pull public.ecr.aws/nginx:latest: some error\ndocker pull public.ecr.aws/redis:latest: some other error")
+		require.Contains(t, out.String(), "Pulled image public.ecr.aws/postgres:latest")
+	})
+}
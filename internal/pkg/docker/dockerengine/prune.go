@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/dustin/go-humanize"
+)
+
+// CopilotBuilderLabel is the label set on the images built by copilot.
+const CopilotBuilderLabel = "com.aws.copilot.image.builder"
+
+// reclaimedSpacePattern matches the summary printed by `docker image prune`, like "Total reclaimed space: 1.2GB".
+var reclaimedSpacePattern = regexp.MustCompile(`Total reclaimed space: ([\d.]+\s?[kMGT]?B)`)
+
+// PruneCopilotImages removes the dangling images built by copilot that were created more than olderThan ago,
+// like the previous builds of a service whose tags moved to newer images, to reclaim disk space on long-lived build machines.
+// Images used by containers are kept. It returns the number of bytes reclaimed.
+func (c DockerCmdClient) PruneCopilotImages(ctx context.Context, olderThan time.Duration) (int64, error) {
+	args := []string{"image", "prune", "--force", "--filter", "label=" + CopilotBuilderLabel}
+	if olderThan > 0 {
+		args = append(args, "--filter", "until="+olderThan.String())
+	}
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return 0, fmt.Errorf("prune copilot images: %w", err)
+	}
+	m := reclaimedSpacePattern.FindStringSubmatch(buf.String())
+	if m == nil {
+		return 0, nil
+	}
+	reclaimed, err := humanize.ParseBytes(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("parse reclaimed space %q: %w", m[1], err)
+	}
+	return int64(reclaimed), nil
+}
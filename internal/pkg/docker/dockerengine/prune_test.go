@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_PruneCopilotImages(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		olderThan time.Duration
+		setupMock func(m *MockCmd)
+
+		wanted    int64
+		wantedErr string
+	}{
+		"prunes the dangling copilot images older than the duration": {
+			olderThan: 7 * 24 * time.Hour,
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "prune", "--force", "--filter", "label=com.aws.copilot.image.builder", "--filter", "until=168h0m0s"}, gomock.Any()).
+					Do(mockStdout("Deleted Images:\ndeleted: sha256:aaaa\n\nTotal reclaimed space: 1.2GB\n")).Return(nil)
+			},
+			wanted: 1200000000,
+		},
+		"prunes all the dangling copilot images": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "prune", "--force", "--filter", "label=com.aws.copilot.image.builder"}, gomock.Any()).
+					Do(mockStdout("Total reclaimed space: 0B\n")).Return(nil)
+			},
+		},
+		"wraps the error": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "prune copilot images: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.PruneCopilotImages(ctx, tc.olderThan)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// PullOptions holds the options of Pull.
+type PullOptions struct {
+	Platform string    // Optional. Platform of the image to pull, for example "linux/arm64". Defaults to the platform of the daemon.
+	Quiet    bool      // Optional. Only write the reference of the pulled image instead of the progress of each layer.
+	Progress io.Writer // Optional. Writer that the progress of the pull is streamed to. Defaults to io.Discard.
+}
+
+// Pull pulls the image from its registry, so that it's present before the containers that use it are run.
+// It returns ErrRegistryRateLimited if the registry throttled the pull.
+// If the client has a trust policy, the signature of the image is verified first and the image is pulled by the verified digest.
+// If the client has a registry mirror, Docker Hub images are pulled from the mirror.
+// Images that aren't pulled by their original reference are tagged with it.
+func (c DockerCmdClient) Pull(ctx context.Context, image string, opts PullOptions) error {
+	pinned, err := c.pinTrusted(ctx, image)
+	if err != nil {
+		return err
+	}
+	w := opts.Progress
+	if w == nil {
+		w = io.Discard
+	}
+	args := []string{"pull"}
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+	if opts.Quiet {
+		args = append(args, "--quiet")
+	}
+	// Content pulled by digest from the mirror is the same as the one verified in the original registry.
+	ref := c.mirrored(pinned)
+	args = append(args, ref)
+	tail := &tailBuffer{size: buildOutputTailSize}
+	out := io.MultiWriter(w, tail)
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(out), exec.Stderr(out)); err != nil {
+		if isRateLimited(tail.String()) {
+			return &ErrRegistryRateLimited{
+				Image: image,
+				err:   err,
+			}
+		}
+		return fmt.Errorf("docker pull %s: %w", image, err)
+	}
+	if ref != image {
+		return c.Tag(ctx, ref, image)
+	}
+	return nil
+}
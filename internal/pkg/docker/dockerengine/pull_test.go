@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_Pull(t *testing.T) {
+	tests := map[string]struct {
+		opts      PullOptions
+		setupMock func(m *MockCmd)
+
+		wantedOut string
+		wantedErr string
+	}{
+		"streams the progress of the pull": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "nginx:1.25"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("1.25: Pulling from library/nginx\n")).Return(nil)
+			},
+			wantedOut: "1.25: Pulling from library/nginx\n",
+		},
+		"pulls the image for the platform quietly": {
+			opts: PullOptions{Platform: "linux/arm64", Quiet: true},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--platform", "linux/arm64", "--quiet", "nginx:1.25"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("docker.io/library/nginx:1.25\n")).Return(nil)
+			},
+			wantedOut: "docker.io/library/nginx:1.25\n",
+		},
+		"returns a wrapped error": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "nginx:1.25"}, gomock.Any(), gomock.Any()).
+					Return(errors.New("some error"))
+			},
+			wantedErr: "docker pull nginx:1.25: some error",
+		},
+		"returns ErrRegistryRateLimited if the registry throttled the pull": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "nginx:1.25"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("Error response from daemon: toomanyrequests: You have reached your pull rate limit.\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "pull image nginx:1.25: registry rate limit reached: exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			c := DockerCmdClient{
+				runner: m,
+			}
+			out := &strings.Builder{}
+			tc.opts.Progress = out
+
+			err := c.Pull(context.Background(), "nginx:1.25", tc.opts)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pullThroughCacheUpstreams are the upstream URLs of the pull through cache rules, keyed by the registry of the images.
+var pullThroughCacheUpstreams = map[string]string{
+	defaultRegistry:     "registry-1.docker.io",
+	"ghcr.io":           "ghcr.io",
+	"quay.io":           "quay.io",
+	"gcr.io":            "gcr.io",
+	"registry.k8s.io":   "registry.k8s.io",
+	"mcr.microsoft.com": "mcr.microsoft.com",
+	ECRPublicRegistry:   ECRPublicRegistry,
+}
+
+// PullThroughCache is the ECR pull through cache of an account, which caches the images of upstream registries.
+type PullThroughCache struct {
+	Registry string            // Required. ECR registry of the account, like "123456789012.dkr.ecr.us-west-2.amazonaws.com".
+	Rules    map[string]string // Required. Repository prefix of each rule keyed by upstream registry URL, like "registry-1.docker.io": "docker-hub".
+}
+
+// ErrPullThroughCacheRuleNotFound means an image of an upstream registry can't be pulled through the cache.
+type ErrPullThroughCacheRuleNotFound struct {
+	Image    string
+	Upstream string
+}
+
+func (e *ErrPullThroughCacheRuleNotFound) Error() string {
+	return fmt.Sprintf("no pull through cache rule for upstream registry %s of image %s", e.Upstream, e.Image)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrPullThroughCacheRuleNotFound) RecommendActions() string {
+	return fmt.Sprintf("Create a pull through cache rule for %s with `aws ecr create-pull-through-cache-rule --upstream-registry-url %s --ecr-repository-prefix <prefix>`, or remove the pull through cache from the build.", e.Upstream, e.Upstream)
+}
+
+// rewrite returns the reference of the image in the cache if it's an image of an upstream registry,
+// like "<registry>/docker-hub/library/nginx:1.25" for "nginx:1.25". Other images are returned unchanged.
+// It returns ErrPullThroughCacheRuleNotFound if the cache has no rule for the upstream registry of the image.
+func (p *PullThroughCache) rewrite(ref string) (string, error) {
+	if p == nil {
+		return ref, nil
+	}
+	registry, _ := parseImageRef(ref)
+	upstream, ok := pullThroughCacheUpstreams[registry]
+	if !ok {
+		return ref, nil
+	}
+	prefix, ok := p.Rules[upstream]
+	if !ok {
+		return "", &ErrPullThroughCacheRuleNotFound{
+			Image:    ref,
+			Upstream: upstream,
+		}
+	}
+	repo := strings.TrimPrefix(ref, registry+"/")
+	if registry == defaultRegistry {
+		repo = dockerHubRepository(ref)
+	}
+	return p.Registry + "/" + prefix + "/" + repo, nil
+}
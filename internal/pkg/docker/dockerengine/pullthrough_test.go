@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullThroughCache_Rewrite(t *testing.T) {
+	const registry = "123456789012.dkr.ecr.us-west-2.amazonaws.com"
+	cache := &PullThroughCache{
+		Registry: registry,
+		Rules: map[string]string{
+			"registry-1.docker.io": "docker-hub",
+			"ghcr.io":              "github",
+		},
+	}
+	tests := map[string]struct {
+		cache *PullThroughCache
+		ref   string
+
+		wanted    string
+		wantedErr string
+	}{
+		"leaves images unchanged without a cache": {
+			ref:    "nginx:1.25",
+			wanted: "nginx:1.25",
+		},
+		"rewrites official Docker Hub images": {
+			cache:  cache,
+			ref:    "nginx:1.25",
+			wanted: registry + "/docker-hub/library/nginx:1.25",
+		},
+		"rewrites other Docker Hub images": {
+			cache:  cache,
+			ref:    "docker.io/bitnami/redis:7.2",
+			wanted: registry + "/docker-hub/bitnami/redis:7.2",
+		},
+		"rewrites images of other upstream registries": {
+			cache:  cache,
+			ref:    "ghcr.io/org/app:cache",
+			wanted: registry + "/github/org/app:cache",
+		},
+		"leaves images of other registries unchanged": {
+			cache:  cache,
+			ref:    registry + "/app/svc:cache",
+			wanted: registry + "/app/svc:cache",
+		},
+		"returns ErrPullThroughCacheRuleNotFound without a rule for the upstream registry": {
+			cache:     cache,
+			ref:       "quay.io/org/app:cache",
+			wantedErr: "no pull through cache rule for upstream registry quay.io of image quay.io/org/app:cache",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.cache.rewrite(tc.ref)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestBuildArguments_PullThroughCache(t *testing.T) {
+	in := &BuildArguments{
+		Dockerfile: "Dockerfile",
+		Tags:       []string{"latest"},
+		CacheFrom:  []string{"nginx:1.25"},
+		PullThroughCache: &PullThroughCache{
+			Registry: "123456789012.dkr.ecr.us-west-2.amazonaws.com",
+			Rules:    map[string]string{"registry-1.docker.io": "docker-hub"},
+		},
+	}
+
+	args, err := in.GenerateDockerBuildArgs(DockerCmdClient{
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	})
+
+	require.NoError(t, err)
+	require.Contains(t, args, "123456789012.dkr.ecr.us-west-2.amazonaws.com/docker-hub/library/nginx:1.25")
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrDigestMismatch means the same image got different digests in different repositories.
+type ErrDigestMismatch struct {
+	Image       string
+	DigestByURI map[string]string
+}
+
+func (e *ErrDigestMismatch) Error() string {
+	uris := make([]string, 0, len(e.DigestByURI))
+	for uri := range e.DigestByURI {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	digests := make([]string, len(uris))
+	for i, uri := range uris {
+		digests[i] = fmt.Sprintf("%s@%s", uri, e.DigestByURI[uri])
+	}
+	return fmt.Sprintf("image %s was pushed with different digests: %s", e.Image, strings.Join(digests, ", "))
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrDigestMismatch) RecommendActions() string {
+	return "Make sure that the repositories don't rewrite the manifests of pushed images, for example by converting their media types, and push the image again."
+}
+
+// PushAll tags the local image as "<uri>:<tag>" for each of the URIs and tags, and pushes it to each repository.
+// The image must have the same digest in every repository, so that all of them can be referenced by a single digest,
+// which is returned. Otherwise, it returns ErrDigestMismatch.
+func (c DockerCmdClient) PushAll(ctx context.Context, image string, uris []string, w io.Writer, tags ...string) (digest string, err error) {
+	digests := make(map[string]string)
+	var errs []error
+	for _, uri := range uris {
+		d, err := c.retagAndPush(ctx, image, uri, w, tags)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("push %s to %s: %w", image, uri, err))
+			continue
+		}
+		digests[uri] = d
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+	for _, d := range digests {
+		if digest == "" {
+			digest = d
+		}
+		if d != digest {
+			return "", &ErrDigestMismatch{
+				Image:       image,
+				DigestByURI: digests,
+			}
+		}
+	}
+	return digest, nil
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_PushAll(t *testing.T) {
+	ctx := context.Background()
+	const (
+		sidecars = "123456789012.dkr.ecr.us-west-2.amazonaws.com/sidecars/envoy"
+		app      = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/envoy"
+	)
+	expectPush := func(m *MockCmd, uri, digest string) {
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "envoy:local", uri + ":v1"}).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":v1"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("v1: digest: " + digest + " size: 528\n")).Return(nil)
+	}
+	tests := map[string]struct {
+		setupMock func(m *MockCmd)
+
+		wanted    string
+		wantedErr string
+	}{
+		"returns the shared digest": {
+			setupMock: func(m *MockCmd) {
+				expectPush(m, sidecars, "sha256:aaaa")
+				expectPush(m, app, "sha256:aaaa")
+			},
+			wanted: "sha256:aaaa",
+		},
+		"returns ErrDigestMismatch if the digests differ": {
+			setupMock: func(m *MockCmd) {
+				expectPush(m, sidecars, "sha256:aaaa")
+				expectPush(m, app, "sha256:bbbb")
+			},
+			wantedErr: "image envoy:local was pushed with different digests: " + app + "@sha256:bbbb, " + sidecars + "@sha256:aaaa",
+		},
+		"returns the errors of all the repositories": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "envoy:local", sidecars + ":v1"}).Return(errors.New("some error"))
+				expectPush(m, app, "sha256:aaaa")
+			},
+			wantedErr: "push envoy:local to " + sidecars + ": tag image envoy:local as " + sidecars + ":v1: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}
+
+			got, err := s.PushAll(ctx, "envoy:local", []string{sidecars, app}, &strings.Builder{}, "v1")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
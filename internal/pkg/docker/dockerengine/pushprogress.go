@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Statuses of a layer reported by docker push.
+const (
+	LayerStatusPreparing     = "Preparing"
+	LayerStatusWaiting       = "Waiting"
+	LayerStatusPushed        = "Pushed"
+	LayerStatusAlreadyExists = "Layer already exists"
+)
+
+// layerStatusPattern matches the status lines of the layers printed by docker push, like "5f70bf18a086: Pushed".
+var layerStatusPattern = regexp.MustCompile(`^([a-f0-9]{12}): (.+)$`)
+
+// PushProgress is a change in the status of a layer of a pushed image.
+type PushProgress struct {
+	Image  string // Reference of the image being pushed, like "<uri>:<tag>".
+	Layer  string // Short ID of the layer.
+	Status string // One of the LayerStatus constants, or another status reported by docker.
+}
+
+// PushProgressFunc receives the status of the layers of pushed images.
+// It's called concurrently when several tags are pushed at once.
+type PushProgressFunc func(PushProgress)
+
+// WithPushProgress returns a copy of the client that reports each change in the status of the layers of the images it pushes to fn,
+// so that it can be rendered as a status per layer instead of the raw output of docker push.
+// The output of docker push isn't a terminal, so docker only reports the statuses of the layers and not the bytes uploaded.
+func (c DockerCmdClient) WithPushProgress(fn PushProgressFunc) DockerCmdClient {
+	c.pushProgress = fn
+	return c
+}
+
+// WriteLine parses a line of the output of docker push, and calls fn if it reports the status of a layer.
+func (fn PushProgressFunc) WriteLine(image, line string) {
+	p, ok := parsePushProgress(line)
+	if !ok {
+		return
+	}
+	p.Image = image
+	fn(p)
+}
+
+// parsePushProgress returns the status of the layer reported by a line of the output of docker push.
+func parsePushProgress(line string) (PushProgress, bool) {
+	m := layerStatusPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return PushProgress{}, false
+	}
+	return PushProgress{
+		Layer:  m[1],
+		Status: m[2],
+	}, true
+}
@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePushProgress(t *testing.T) {
+	tests := map[string]struct {
+		line string
+
+		wanted   PushProgress
+		wantedOK bool
+	}{
+		"ignores lines that aren't about layers": {
+			line: "The push refers to repository [123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc]",
+		},
+		"ignores the digest line": {
+			line: "latest: digest: sha256:f1d4ae3f size: 528",
+		},
+		"parses statuses": {
+			line:     "5f70bf18a086: Layer already exists",
+			wanted:   PushProgress{Layer: "5f70bf18a086", Status: LayerStatusAlreadyExists},
+			wantedOK: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parsePushProgress(tc.line)
+
+			require.Equal(t, tc.wantedOK, ok)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerCommand_WithPushProgress(t *testing.T) {
+	ctx := context.Background()
+	const uri = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":v1"}, gomock.Any(), gomock.Any()).
+		Do(mockStdout("The push refers to repository [" + uri + "]\n" +
+			"5f70bf18a086: Preparing\n" +
+			"5f70bf18a086: Waiting\n" +
+			"5f70bf18a086: Pushed\n" +
+			"v1: digest: sha256:f1d4ae3f size: 528\n")).Return(nil)
+	var got []PushProgress
+	s := DockerCmdClient{
+		runner: m,
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}.WithPushProgress(func(p PushProgress) {
+		got = append(got, p)
+	})
+
+	digest, err := s.Push(ctx, uri, &strings.Builder{}, "v1")
+
+	require.NoError(t, err)
+	require.Equal(t, "sha256:f1d4ae3f", digest)
+	require.Equal(t, []PushProgress{
+		{Image: uri + ":v1", Layer: "5f70bf18a086", Status: LayerStatusPreparing},
+		{Image: uri + ":v1", Layer: "5f70bf18a086", Status: LayerStatusWaiting},
+		{Image: uri + ":v1", Layer: "5f70bf18a086", Status: LayerStatusPushed},
+	}, got)
+}
@@ -0,0 +1,185 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Priority is the priority of an operation in an OperationQueue.
+type Priority int
+
+// Priorities of the operations, from lowest to highest.
+const (
+	// PriorityBackground is for operations that nobody waits on, such as prefetching base images.
+	// A background operation is interrupted and queued again to make room for an interactive one.
+	PriorityBackground Priority = iota
+	// PriorityInteractive is for operations that the user waits on, such as the builds and pushes of a deployment.
+	PriorityInteractive
+)
+
+// Operation is a docker operation, such as a build, a pull or a push, to run in an OperationQueue.
+type Operation struct {
+	Group    string   // Optional. Group that the operation belongs to, for example the deployment of a service, to cancel them all at once.
+	Priority Priority // Optional. Defaults to PriorityBackground.
+	// Required. Runs the operation until ctx is canceled.
+	// Background operations must be safe to run again after being interrupted.
+	Run func(ctx context.Context) error
+}
+
+// OperationHandle tracks the completion of an operation submitted to an OperationQueue.
+type OperationHandle struct {
+	done chan struct{}
+	err  error
+}
+
+// Done returns a channel that is closed when the operation completes or is canceled.
+func (h *OperationHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the operation completes and returns its error.
+func (h *OperationHandle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+type queuedOperation struct {
+	Operation
+	ctx    context.Context
+	seq    int // Order of submission, to run the operations of the same priority in order.
+	handle *OperationHandle
+
+	cancel    context.CancelFunc // Set while the operation runs.
+	preempted bool               // True if the operation was interrupted to make room for an interactive operation.
+}
+
+// OperationQueue runs docker operations with a limited concurrency, highest priority first.
+// Interactive operations preempt background ones, and all the operations of a group can be canceled in one call.
+type OperationQueue struct {
+	concurrency int
+
+	mu      sync.Mutex
+	pending []*queuedOperation
+	running map[*queuedOperation]struct{}
+	nextSeq int
+}
+
+// NewOperationQueue returns an OperationQueue that runs up to concurrency operations at once.
+// If concurrency isn't positive, the operations run one at a time.
+func NewOperationQueue(concurrency int) *OperationQueue {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &OperationQueue{
+		concurrency: concurrency,
+		running:     make(map[*queuedOperation]struct{}),
+	}
+}
+
+// Submit queues the operation, which runs with ctx once a slot is free, and returns a handle to wait for it.
+func (q *OperationQueue) Submit(ctx context.Context, op Operation) *OperationHandle {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queued := &queuedOperation{
+		Operation: op,
+		ctx:       ctx,
+		seq:       q.nextSeq,
+		handle:    &OperationHandle{done: make(chan struct{})},
+	}
+	q.nextSeq++
+	q.pending = append(q.pending, queued)
+	if op.Priority > PriorityBackground && len(q.running) >= q.concurrency {
+		q.preemptLocked()
+	}
+	q.dispatchLocked()
+	return queued.handle
+}
+
+// CancelGroup cancels the pending and running operations of the group.
+// Pending operations complete with context.Canceled, and running ones complete once their Run function returns.
+func (q *OperationQueue) CancelGroup(group string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var kept []*queuedOperation
+	for _, op := range q.pending {
+		if op.Group == group {
+			q.complete(op, context.Canceled)
+			continue
+		}
+		kept = append(kept, op)
+	}
+	q.pending = kept
+	for op := range q.running {
+		if op.Group == group {
+			op.preempted = false
+			op.cancel()
+		}
+	}
+}
+
+// preemptLocked interrupts the most recently submitted running background operation, if any. The caller must hold q.mu.
+func (q *OperationQueue) preemptLocked() {
+	var victim *queuedOperation
+	for op := range q.running {
+		if op.Priority != PriorityBackground || op.preempted {
+			continue
+		}
+		if victim == nil || op.seq > victim.seq {
+			victim = op
+		}
+	}
+	if victim == nil {
+		return
+	}
+	victim.preempted = true
+	victim.cancel()
+}
+
+// dispatchLocked starts the highest priority pending operations while there are free slots. The caller must hold q.mu.
+func (q *OperationQueue) dispatchLocked() {
+	sort.SliceStable(q.pending, func(i, j int) bool {
+		if q.pending[i].Priority != q.pending[j].Priority {
+			return q.pending[i].Priority > q.pending[j].Priority
+		}
+		return q.pending[i].seq < q.pending[j].seq
+	})
+	// Preempted operations still hold their slot until their Run function returns.
+	for len(q.pending) > 0 && len(q.running) < q.concurrency {
+		op := q.pending[0]
+		q.pending = q.pending[1:]
+		if err := op.ctx.Err(); err != nil {
+			q.complete(op, err)
+			continue
+		}
+		ctx, cancel := context.WithCancel(op.ctx)
+		op.cancel = cancel
+		op.preempted = false
+		q.running[op] = struct{}{}
+		go q.run(ctx, op)
+	}
+}
+
+func (q *OperationQueue) run(ctx context.Context, op *queuedOperation) {
+	err := op.Run(ctx)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	op.cancel()
+	delete(q.running, op)
+	if op.preempted && err != nil && op.ctx.Err() == nil {
+		// The operation was interrupted to make room for an interactive one, so it runs again later.
+		q.pending = append(q.pending, op)
+		q.dispatchLocked()
+		return
+	}
+	q.complete(op, err)
+	q.dispatchLocked()
+}
+
+func (q *OperationQueue) complete(op *queuedOperation, err error) {
+	op.handle.err = err
+	close(op.handle.done)
+}
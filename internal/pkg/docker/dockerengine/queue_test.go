@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs the highest priority operations first", func(t *testing.T) {
+		q := NewOperationQueue(1)
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var order []string
+		record := func(name string) func(context.Context) error {
+			return func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+		blocker := q.Submit(ctx, Operation{Priority: PriorityInteractive, Run: func(context.Context) error {
+			<-release
+			return nil
+		}})
+		prefetch := q.Submit(ctx, Operation{Priority: PriorityBackground, Run: record("prefetch")})
+		build := q.Submit(ctx, Operation{Priority: PriorityInteractive, Run: record("build")})
+		push := q.Submit(ctx, Operation{Priority: PriorityInteractive, Run: record("push")})
+		close(release)
+
+		for _, h := range []*OperationHandle{blocker, prefetch, build, push} {
+			require.NoError(t, h.Wait())
+		}
+		require.Equal(t, []string{"build", "push", "prefetch"}, order)
+	})
+
+	t.Run("interactive operations preempt background ones which run again later", func(t *testing.T) {
+		q := NewOperationQueue(1)
+		started := make(chan struct{}, 2)
+		var runs int
+		prefetch := q.Submit(ctx, Operation{Priority: PriorityBackground, Run: func(ctx context.Context) error {
+			runs++
+			started <- struct{}{}
+			if runs > 1 {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}})
+		<-started
+
+		build := q.Submit(ctx, Operation{Priority: PriorityInteractive, Run: func(context.Context) error {
+			return nil
+		}})
+
+		require.NoError(t, build.Wait())
+		require.NoError(t, prefetch.Wait())
+		require.Equal(t, 2, runs)
+	})
+
+	t.Run("cancels all the operations of a group", func(t *testing.T) {
+		q := NewOperationQueue(1)
+		started := make(chan struct{})
+		running := q.Submit(ctx, Operation{Group: "deploy-web", Priority: PriorityInteractive, Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}})
+		pending := q.Submit(ctx, Operation{Group: "deploy-web", Priority: PriorityInteractive, Run: func(context.Context) error {
+			t.Error("a canceled operation must not run")
+			return nil
+		}})
+		other := q.Submit(ctx, Operation{Group: "deploy-api", Priority: PriorityInteractive, Run: func(context.Context) error {
+			return nil
+		}})
+		<-started
+
+		q.CancelGroup("deploy-web")
+
+		require.ErrorIs(t, running.Wait(), context.Canceled)
+		require.ErrorIs(t, pending.Wait(), context.Canceled)
+		require.NoError(t, other.Wait())
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// Artifact types of common supply-chain metadata attached to images.
+const (
+	ArtifactTypeSPDX      = "application/spdx+json"
+	ArtifactTypeCycloneDX = "application/vnd.cyclonedx+json"
+	ArtifactTypeSARIF     = "application/sarif+json"
+)
+
+// attachedDigestPattern matches the line printed by oras attach once the artifact is pushed, like "Digest: sha256:...".
+var attachedDigestPattern = regexp.MustCompile(`Digest: (sha256:[a-f0-9]+)`)
+
+// Artifact is a file pushed to a registry as an OCI artifact that refers to an image, like an SBOM or scan results.
+type Artifact struct {
+	Type        string            // Required. Artifact type, for example ArtifactTypeSPDX.
+	Path        string            // Required. Path to the file of the artifact.
+	MediaType   string            // Optional. Media type of the file. Defaults to Type.
+	Annotations map[string]string // Optional. Annotations of the artifact manifest, for example "org.opencontainers.image.created".
+}
+
+// AttachArtifact pushes the artifact to the repository of the image, with the image as its subject, using the oras CLI.
+// The subject must be referenced by digest, like "<uri>@sha256:...", so that the artifact is bound to that exact image.
+// Registries that support the OCI referrers API, like ECR, list the artifact among the referrers of the image.
+// It returns the digest of the artifact manifest.
+func (c DockerCmdClient) AttachArtifact(ctx context.Context, subject string, artifact Artifact, w io.Writer) (string, error) {
+	mediaType := artifact.MediaType
+	if mediaType == "" {
+		mediaType = artifact.Type
+	}
+	args := []string{"attach", "--artifact-type", artifact.Type}
+	keys := make([]string, 0, len(artifact.Annotations))
+	for k := range artifact.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--annotation", fmt.Sprintf("%s=%s", k, artifact.Annotations[k]))
+	}
+	// Artifacts are usually written to temporary directories, which oras refuses as absolute paths by default.
+	args = append(args, "--disable-path-validation", subject, fmt.Sprintf("%s:%s", artifact.Path, mediaType))
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "oras", args, exec.Stdout(io.MultiWriter(w, buf)), exec.Stderr(w)); err != nil {
+		return "", fmt.Errorf("attach %s artifact %s to %s: %w", artifact.Type, artifact.Path, subject, err)
+	}
+	m := attachedDigestPattern.FindStringSubmatch(buf.String())
+	if m == nil {
+		return "", fmt.Errorf("parse the digest of the %s artifact attached to %s", artifact.Type, subject)
+	}
+	return m[1], nil
+}
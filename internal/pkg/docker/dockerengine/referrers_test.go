@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_AttachArtifact(t *testing.T) {
+	ctx := context.Background()
+	const subject = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc@sha256:f1d4ae3f"
+	tests := map[string]struct {
+		artifact  Artifact
+		setupMock func(m *MockCmd)
+
+		wanted    string
+		wantedErr string
+	}{
+		"attaches an SBOM with annotations": {
+			artifact: Artifact{
+				Type: ArtifactTypeSPDX,
+				Path: "/tmp/sbom.spdx.json",
+				Annotations: map[string]string{
+					"org.opencontainers.image.title":   "sbom",
+					"org.opencontainers.image.created": "2024-01-01T00:00:00Z",
+				},
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "oras", []string{"attach", "--artifact-type", ArtifactTypeSPDX,
+					"--annotation", "org.opencontainers.image.created=2024-01-01T00:00:00Z",
+					"--annotation", "org.opencontainers.image.title=sbom",
+					"--disable-path-validation", subject, "/tmp/sbom.spdx.json:application/spdx+json"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("Attached to [registry] " + subject + "\nDigest: sha256:0a1b2c\n")).Return(nil)
+			},
+			wanted: "sha256:0a1b2c",
+		},
+		"uses the media type of the file": {
+			artifact: Artifact{
+				Type:      "application/vnd.copilot.manifest",
+				Path:      "manifest.yml",
+				MediaType: "application/yaml",
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "oras", []string{"attach", "--artifact-type", "application/vnd.copilot.manifest",
+					"--disable-path-validation", subject, "manifest.yml:application/yaml"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("Digest: sha256:3d4e5f\n")).Return(nil)
+			},
+			wanted: "sha256:3d4e5f",
+		},
+		"wraps the error": {
+			artifact: Artifact{Type: ArtifactTypeSARIF, Path: "scan.sarif"},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "oras", gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "attach application/sarif+json artifact scan.sarif to " + subject + ": some error",
+		},
+		"returns an error if the digest isn't printed": {
+			artifact: Artifact{Type: ArtifactTypeSARIF, Path: "scan.sarif"},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "oras", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantedErr: "parse the digest of the application/sarif+json artifact attached to " + subject,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.AttachArtifact(ctx, subject, tc.artifact, &strings.Builder{})
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// RegionalRegistry gives access to the repository of an image in each region.
+type RegionalRegistry interface {
+	RepositoryURI(region string) (string, error)
+	Auth(region string) (username, password string, err error)
+}
+
+// LoginToRegions logs in to the registry of each region, unless a credential helper is configured for it,
+// and returns the URI of the repository keyed by region, ready to be passed to PushToRegions.
+// It returns the URIs of the regions that the client logged in to, and the errors of the other regions.
+func (c DockerCmdClient) LoginToRegions(regions []string, registry RegionalRegistry) (map[string]string, error) {
+	uris := make(map[string]string)
+	var errs []error
+	for _, region := range regions {
+		uri, err := c.loginToRegion(region, registry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("log in to region %s: %w", region, err))
+			continue
+		}
+		uris[region] = uri
+	}
+	return uris, errors.Join(errs...)
+}
+
+func (c DockerCmdClient) loginToRegion(region string, registry RegionalRegistry) (string, error) {
+	uri, err := registry.RepositoryURI(region)
+	if err != nil {
+		return "", fmt.Errorf("get repository URI: %w", err)
+	}
+	if c.IsEcrCredentialHelperEnabled(uri) {
+		return uri, nil
+	}
+	username, password, err := registry.Auth(region)
+	if err != nil {
+		return "", fmt.Errorf("get auth: %w", err)
+	}
+	if err := c.Login(uri, username, password); err != nil {
+		return "", err
+	}
+	return uri, nil
+}
+
+// PushToRegions tags the local image as "<uri>:<tag>" for the repository of each region and each tag, and pushes it.
+// The client must be logged in to the registries, for example with LoginToRegions.
+// It returns the digest of the image keyed by region for the regions that the image was pushed to, and the errors of the other regions.
+func (c DockerCmdClient) PushToRegions(ctx context.Context, image string, uriByRegion map[string]string, w io.Writer, tags ...string) (map[string]string, error) {
+	regions := make([]string, 0, len(uriByRegion))
+	for region := range uriByRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	digests := make(map[string]string)
+	var errs []error
+	for _, region := range regions {
+		digest, err := c.retagAndPush(ctx, image, uriByRegion[region], w, tags)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("push %s to region %s: %w", image, region, err))
+			continue
+		}
+		digests[region] = digest
+	}
+	return digests, errors.Join(errs...)
+}
+
+// retagAndPush tags the local image as "<uri>:<tag>" for each tag, and pushes it to uri.
+func (c DockerCmdClient) retagAndPush(ctx context.Context, image, uri string, w io.Writer, tags []string) (string, error) {
+	if len(tags) == 0 {
+		return "", &errEmptyImageTags{
+			uri: uri,
+		}
+	}
+	for _, tag := range tags {
+		if err := c.Tag(ctx, image, imageName(uri, tag)); err != nil {
+			return "", err
+		}
+	}
+	return c.Push(ctx, uri, w, tags...)
+}
@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRegionalRegistry struct {
+	uris map[string]string
+}
+
+func (r *mockRegionalRegistry) RepositoryURI(region string) (string, error) {
+	uri, ok := r.uris[region]
+	if !ok {
+		return "", errors.New("repository not found")
+	}
+	return uri, nil
+}
+
+func (r *mockRegionalRegistry) Auth(region string) (string, string, error) {
+	return "AWS", "password-" + region, nil
+}
+
+func TestDockerCommand_LoginToRegions(t *testing.T) {
+	registry := &mockRegionalRegistry{
+		uris: map[string]string{
+			"us-west-2": "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc",
+		},
+	}
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", registry.uris["us-west-2"]}, gomock.Any()).Return(nil)
+	s := DockerCmdClient{
+		runner: m,
+		lookupEnv: func(string) (string, bool) {
+			return "", false
+		},
+	}
+
+	got, err := s.LoginToRegions([]string{"us-west-2", "ap-south-1"}, registry)
+
+	require.EqualError(t, err, "log in to region ap-south-1: get repository URI: repository not found")
+	require.Equal(t, map[string]string{"us-west-2": registry.uris["us-west-2"]}, got)
+}
+
+func TestDockerCommand_PushToRegions(t *testing.T) {
+	ctx := context.Background()
+	uris := map[string]string{
+		"us-west-2": "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc",
+		"eu-west-1": "123456789012.dkr.ecr.eu-west-1.amazonaws.com/app/svc",
+	}
+	digests := map[string]string{
+		"us-west-2": "sha256:aaaa",
+		"eu-west-1": "sha256:bbbb",
+	}
+	expectPush := func(m *MockCmd, region string) {
+		uri := uris[region]
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "local/svc:v1", uri + ":v1"}).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "local/svc:v1", uri + ":latest"}).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":v1"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout(fmt.Sprintf("v1: digest: %s size: 528\n", digests[region]))).Return(nil)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":latest"}, gomock.Any(), gomock.Any()).Return(nil)
+	}
+
+	t.Run("retags and pushes to each region", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		expectPush(m, "us-west-2")
+		expectPush(m, "eu-west-1")
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+
+		got, err := s.PushToRegions(ctx, "local/svc:v1", uris, &strings.Builder{}, "v1", "latest")
+
+		require.NoError(t, err)
+		require.Equal(t, digests, got)
+	})
+	t.Run("returns the digests of the successful regions and the errors of the others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		expectPush(m, "us-west-2")
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "local/svc:v1", uris["eu-west-1"] + ":v1"}).Return(errors.New("some error"))
+		s := DockerCmdClient{
+			runner: m,
+			lookupEnv: func(string) (string, bool) {
+				return "", false
+			},
+		}
+
+		got, err := s.PushToRegions(ctx, "local/svc:v1", uris, &strings.Builder{}, "v1", "latest")
+
+		require.EqualError(t, err, fmt.Sprintf("push local/svc:v1 to region eu-west-1: tag image local/svc:v1 as %s:v1: some error", uris["eu-west-1"]))
+		require.Equal(t, map[string]string{"us-west-2": "sha256:aaaa"}, got)
+	})
+	t.Run("returns an error without tags", func(t *testing.T) {
+		s := DockerCmdClient{}
+
+		_, err := s.PushToRegions(ctx, "local/svc:v1", map[string]string{"us-west-2": uris["us-west-2"]}, &strings.Builder{})
+
+		require.EqualError(t, err, fmt.Sprintf("push local/svc:v1 to region us-west-2: tags to reference an image should not be empty for building and pushing into the ECR repository %s", uris["us-west-2"]))
+	})
+}
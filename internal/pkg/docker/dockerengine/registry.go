@@ -0,0 +1,192 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// RegistryClient pushes images to registries with go-containerregistry, without running any external tool.
+// It enables deployments from hosts where only prebuilt artifacts, such as the output of a buildx build
+// in a container, are available, as well as pushing the images of a local Docker daemon.
+type RegistryClient struct {
+	auth map[string]authn.Authenticator // Credentials keyed by registry, set by Login.
+
+	// Override in unit tests.
+	transport  http.RoundTripper
+	localImage func(ref name.Reference, opts ...daemon.Option) (v1.Image, error)
+}
+
+// NewRegistryClient returns a RegistryClient that authenticates with the credentials of the docker config file
+// until Login is called for a registry.
+func NewRegistryClient() *RegistryClient {
+	return &RegistryClient{
+		auth:       make(map[string]authn.Authenticator),
+		transport:  remote.DefaultTransport,
+		localImage: daemon.Image,
+	}
+}
+
+// Login verifies the credentials against the registry of uri, and uses them for the following pushes to the registry.
+func (c *RegistryClient) Login(ctx context.Context, uri, username, password string) error {
+	repo, err := name.NewRepository(uri)
+	if err != nil {
+		return fmt.Errorf("parse repository %s: %w", uri, err)
+	}
+	auth := authn.FromConfig(authn.AuthConfig{
+		Username: username,
+		Password: password,
+	})
+	if _, err := transport.NewWithContext(ctx, repo.Registry, auth, c.transport, []string{repo.Scope(transport.PushScope)}); err != nil {
+		return fmt.Errorf("authenticate to %s: %w", repo.RegistryStr(), err)
+	}
+	c.auth[repo.RegistryStr()] = auth
+	return nil
+}
+
+// Push uploads the image at path to uri with the tags, and returns the digest of the image.
+// The path is an image tarball, like the ones written by Save or by `docker buildx build --output type=docker`,
+// or an OCI image layout directory. The image is uploaded once, and the other tags are added in the registry.
+func (c *RegistryClient) Push(ctx context.Context, path, uri string, w io.Writer, tags ...string) (digest string, err error) {
+	if len(tags) == 0 {
+		return "", &errEmptyImageTags{
+			uri: uri,
+		}
+	}
+	img, err := readImageArchive(path)
+	if err != nil {
+		return "", err
+	}
+	return c.push(ctx, img, uri, w, tags)
+}
+
+// PushLocal uploads the image of the local Docker daemon to uri with the tags, and returns the digest of the image.
+func (c *RegistryClient) PushLocal(ctx context.Context, image, uri string, w io.Writer, tags ...string) (digest string, err error) {
+	if len(tags) == 0 {
+		return "", &errEmptyImageTags{
+			uri: uri,
+		}
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference %s: %w", image, err)
+	}
+	img, err := c.localImage(ref, daemon.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("read local image %s: %w", image, err)
+	}
+	return c.push(ctx, img, uri, w, tags)
+}
+
+func (c *RegistryClient) push(ctx context.Context, img remote.Taggable, uri string, w io.Writer, tags []string) (string, error) {
+	first, err := name.NewTag(imageName(uri, tags[0]))
+	if err != nil {
+		return "", fmt.Errorf("parse image reference %s: %w", imageName(uri, tags[0]), err)
+	}
+	opts := c.remoteOptions(ctx, first.Context())
+	fmt.Fprintf(w, "Pushing %s\n", first)
+	switch img := img.(type) {
+	case v1.ImageIndex:
+		err = remote.WriteIndex(first, img, opts...)
+	case v1.Image:
+		err = remote.Write(first, img, opts...)
+	}
+	if err != nil {
+		return "", fmt.Errorf("push image to %s: %w", first, err)
+	}
+	for _, tag := range tags[1:] {
+		ref := first.Context().Tag(tag)
+		if err := remote.Tag(ref, img, opts...); err != nil {
+			return "", fmt.Errorf("tag %s as %s: %w", first, tag, err)
+		}
+	}
+	digest, err := taggableDigest(img)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(w, "%s: digest: %s\n", tag, digest)
+	}
+	return digest, nil
+}
+
+// remoteOptions returns the options to push to repo, with the credentials of Login or of the docker config file.
+func (c *RegistryClient) remoteOptions(ctx context.Context, repo name.Repository) []remote.Option {
+	opts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithTransport(c.transport),
+	}
+	if auth, ok := c.auth[repo.RegistryStr()]; ok {
+		return append(opts, remote.WithAuth(auth))
+	}
+	return append(opts, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+// readImageArchive reads the image tarball or the OCI image layout directory at path.
+// A layout with a single image or image index is read as that image or index, and as the whole index otherwise.
+func readImageArchive(path string) (remote.Taggable, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat image archive %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		img, err := tarball.ImageFromPath(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("read image tarball %s: %w", path, err)
+		}
+		return img, nil
+	}
+	idx, err := layout.ImageIndexFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("read OCI image layout %s: %w", path, err)
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("read index of OCI image layout %s: %w", path, err)
+	}
+	if len(manifest.Manifests) != 1 {
+		return idx, nil
+	}
+	desc := manifest.Manifests[0]
+	if desc.MediaType.IsIndex() {
+		child, err := idx.ImageIndex(desc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("read image index %s of OCI image layout %s: %w", desc.Digest, path, err)
+		}
+		return child, nil
+	}
+	img, err := idx.Image(desc.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("read image %s of OCI image layout %s: %w", desc.Digest, path, err)
+	}
+	return img, nil
+}
+
+func taggableDigest(t remote.Taggable) (string, error) {
+	var digest v1.Hash
+	var err error
+	switch t := t.(type) {
+	case v1.ImageIndex:
+		digest, err = t.Digest()
+	case v1.Image:
+		digest, err = t.Digest()
+	}
+	if err != nil {
+		return "", fmt.Errorf("compute image digest: %w", err)
+	}
+	return digest.String(), nil
+}
@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry returns the URI of a repository in an in-memory registry.
+func newTestRegistry(t *testing.T) string {
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://") + "/web"
+}
+
+func newTestRegistryClient() *RegistryClient {
+	return &RegistryClient{
+		auth:      make(map[string]authn.Authenticator),
+		transport: http.DefaultTransport,
+	}
+}
+
+// requireTagged asserts that the tags of the repository at uri all reference the digest.
+func requireTagged(t *testing.T, uri, digest string, tags ...string) {
+	for _, tag := range tags {
+		desc, err := remote.Head(mustParseTag(t, imageName(uri, tag)))
+		require.NoError(t, err)
+		require.Equal(t, digest, desc.Digest.String(), "tag %s", tag)
+	}
+}
+
+func mustParseTag(t *testing.T, ref string) name.Tag {
+	tag, err := name.NewTag(ref)
+	require.NoError(t, err)
+	return tag
+}
+
+func TestRegistryClient_Push(t *testing.T) {
+	ctx := context.Background()
+	img, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	idx, err := random.Index(1024, 1, 2)
+	require.NoError(t, err)
+	idxDigest, err := idx.Digest()
+	require.NoError(t, err)
+
+	t.Run("pushes an image tarball once and tags it in the registry", func(t *testing.T) {
+		uri := newTestRegistry(t)
+		path := filepath.Join(t.TempDir(), "image.tar")
+		require.NoError(t, tarball.WriteToFile(path, mustParseTag(t, "web:build"), img))
+
+		digest, err := newTestRegistryClient().Push(ctx, path, uri, io.Discard, "v1", "latest")
+
+		require.NoError(t, err)
+		require.Equal(t, imgDigest.String(), digest)
+		requireTagged(t, uri, digest, "v1", "latest")
+	})
+	t.Run("pushes the image of an OCI image layout", func(t *testing.T) {
+		uri := newTestRegistry(t)
+		dir := t.TempDir()
+		p, err := layout.Write(dir, empty.Index)
+		require.NoError(t, err)
+		require.NoError(t, p.AppendImage(img))
+
+		digest, err := newTestRegistryClient().Push(ctx, dir, uri, io.Discard, "v1")
+
+		require.NoError(t, err)
+		require.Equal(t, imgDigest.String(), digest)
+		requireTagged(t, uri, digest, "v1")
+	})
+	t.Run("pushes the multi-platform index of an OCI image layout", func(t *testing.T) {
+		uri := newTestRegistry(t)
+		dir := t.TempDir()
+		p, err := layout.Write(dir, empty.Index)
+		require.NoError(t, err)
+		require.NoError(t, p.AppendIndex(idx))
+
+		digest, err := newTestRegistryClient().Push(ctx, dir, uri, io.Discard, "v1")
+
+		require.NoError(t, err)
+		require.Equal(t, idxDigest.String(), digest)
+		requireTagged(t, uri, digest, "v1")
+	})
+	t.Run("errors without tags", func(t *testing.T) {
+		_, err := newTestRegistryClient().Push(ctx, "image.tar", "web", io.Discard)
+
+		require.EqualError(t, err, "tags to reference an image should not be empty for building and pushing into the ECR repository web")
+	})
+	t.Run("returns a wrapped error if the archive can't be read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "image.tar")
+
+		_, err := newTestRegistryClient().Push(ctx, path, "web", io.Discard, "v1")
+
+		require.ErrorContains(t, err, "stat image archive "+path)
+	})
+}
+
+func TestRegistryClient_PushLocal(t *testing.T) {
+	ctx := context.Background()
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	t.Run("pushes the image of the local daemon", func(t *testing.T) {
+		uri := newTestRegistry(t)
+		c := newTestRegistryClient()
+		var gotRef string
+		c.localImage = func(ref name.Reference, _ ...daemon.Option) (v1.Image, error) {
+			gotRef = ref.String()
+			return img, nil
+		}
+
+		digest, err := c.PushLocal(ctx, "web:build", uri, io.Discard, "v1", "latest")
+
+		require.NoError(t, err)
+		require.Equal(t, "web:build", gotRef)
+		require.Equal(t, imgDigest.String(), digest)
+		requireTagged(t, uri, digest, "v1", "latest")
+	})
+	t.Run("returns a wrapped error if the local image can't be read", func(t *testing.T) {
+		c := newTestRegistryClient()
+		c.localImage = func(name.Reference, ...daemon.Option) (v1.Image, error) {
+			return nil, errors.New("some error")
+		}
+
+		_, err := c.PushLocal(ctx, "web:build", "web", io.Discard, "v1")
+
+		require.EqualError(t, err, "read local image web:build: some error")
+	})
+}
+
+func TestRegistryClient_Login(t *testing.T) {
+	uri := newTestRegistry(t)
+	c := newTestRegistryClient()
+
+	err := c.Login(context.Background(), uri, "AWS", "password")
+
+	require.NoError(t, err)
+	require.Contains(t, c.auth, strings.TrimSuffix(uri, "/web"))
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	dockerHostEnv     = "DOCKER_HOST"
+	dockerContextEnv  = "DOCKER_CONTEXT"
+	dockerBuildKitEnv = "DOCKER_BUILDKIT"
+)
+
+// remoteDaemonSchemes are the schemes of the daemon addresses supported by the docker CLI.
+var remoteDaemonSchemes = []string{"ssh://", "tcp://", "unix://", "npipe://"}
+
+// envRunner runs commands with additional environment variables.
+type envRunner struct {
+	Cmd
+	env []string
+}
+
+func (r envRunner) Run(name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.Run(name, args, append(opts, exec.Env(r.env...))...)
+}
+
+func (r envRunner) RunWithContext(ctx context.Context, name string, args []string, opts ...exec.CmdOption) error {
+	return r.Cmd.RunWithContext(ctx, name, args, append(opts, exec.Env(r.env...))...)
+}
+
+// WithRemoteDaemon returns a copy of the client that runs docker commands against another daemon than the local one,
+// so that a laptop with limited resources can offload builds to a build box. The build context is uploaded to the daemon,
+// and images are built, stored and pushed from there.
+// target is either the name of a docker context, for example "buildbox", or the address of a daemon, for example "ssh://ec2-user@buildbox".
+//
+// Builds go through BuildKit, whose session syncs the build context incrementally: only the files that changed since
+// the previous build of the same context directory are sent, instead of the whole context on every build.
+// If BuildKit is disabled with DOCKER_BUILDKIT=0, the legacy builder sends the whole context compressed with gzip instead.
+func (c DockerCmdClient) WithRemoteDaemon(target string) (DockerCmdClient, error) {
+	var env []string
+	switch {
+	case target == "":
+		return DockerCmdClient{}, errors.New("remote docker daemon must be a docker context name or a daemon address")
+	case strings.Contains(target, "://"):
+		if !hasRemoteDaemonScheme(target) {
+			return DockerCmdClient{}, fmt.Errorf("unsupported docker daemon address %s: the scheme must be one of %s", target, strings.Join(remoteDaemonSchemes, ", "))
+		}
+		env = []string{dockerHostEnv + "=" + target}
+	default:
+		// DOCKER_HOST takes precedence over DOCKER_CONTEXT, so it's cleared in case the user set it.
+		env = []string{dockerHostEnv + "=", dockerContextEnv + "=" + target}
+	}
+	if buildKitDisabled(c.lookupEnv) {
+		c.compressContext = true
+	} else {
+		env = append(env, dockerBuildKitEnv+"=1")
+	}
+	remote := c
+	remote.runner = envRunner{
+		Cmd: c.runner,
+		env: env,
+	}
+	return remote, nil
+}
+
+// buildKitDisabled returns true if the user opted out of BuildKit with the DOCKER_BUILDKIT environment variable.
+func buildKitDisabled(lookupEnv func(string) (string, bool)) bool {
+	v, ok := lookupEnv(dockerBuildKitEnv)
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(v)
+	return err == nil && !enabled
+}
+
+func hasRemoteDaemonScheme(addr string) bool {
+	for _, scheme := range remoteDaemonSchemes {
+		if strings.HasPrefix(addr, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	osexec "os/exec"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_WithRemoteDaemon(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		target   string
+		buildKit string
+
+		wantedEnv      []string
+		wantedCompress bool
+		wantedErr      string
+	}{
+		"targets a daemon over SSH": {
+			target:    "ssh://ec2-user@buildbox",
+			wantedEnv: []string{"DOCKER_HOST=ssh://ec2-user@buildbox", "DOCKER_BUILDKIT=1"},
+		},
+		"targets a docker context": {
+			target:    "buildbox",
+			wantedEnv: []string{"DOCKER_HOST=", "DOCKER_CONTEXT=buildbox", "DOCKER_BUILDKIT=1"},
+		},
+		"compresses the context if BuildKit is disabled": {
+			target:         "ssh://ec2-user@buildbox",
+			buildKit:       "0",
+			wantedEnv:      []string{"DOCKER_HOST=ssh://ec2-user@buildbox"},
+			wantedCompress: true,
+		},
+		"errors on an unsupported scheme": {
+			target:    "http://buildbox:2375",
+			wantedErr: "unsupported docker daemon address http://buildbox:2375: the scheme must be one of ssh://, tcp://, unix://, npipe://",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(key string) (string, bool) {
+					if key == "DOCKER_BUILDKIT" && tc.buildKit != "" {
+						return tc.buildKit, true
+					}
+					return "", false
+				},
+			}
+
+			remote, err := s.WithRemoteDaemon(tc.target)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			buildArgs, err := (&BuildArguments{Dockerfile: "Dockerfile", Tags: []string{"latest"}}).GenerateDockerBuildArgs(remote)
+			require.NoError(t, err)
+			if tc.wantedCompress {
+				require.Contains(t, buildArgs, "--compress")
+			} else {
+				require.NotContains(t, buildArgs, "--compress")
+			}
+			m.EXPECT().RunWithContext(ctx, "docker", []string{"build", "."}, gomock.Any()).
+				Do(func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+					cmd := &osexec.Cmd{}
+					for _, opt := range opts {
+						opt(cmd)
+					}
+					require.Equal(t, tc.wantedEnv, cmd.Env[len(cmd.Env)-len(tc.wantedEnv):])
+				}).Return(nil)
+			require.NoError(t, remote.runner.RunWithContext(ctx, "docker", []string{"build", "."}))
+		})
+	}
+}
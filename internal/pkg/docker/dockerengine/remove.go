@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// RemoveImage removes the references from the local image store. The layers of an image are deleted
+// once none of its references remain and no container uses it.
+func (c DockerCmdClient) RemoveImage(ctx context.Context, refs ...string) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	if err := c.runner.RunWithContext(ctx, "docker", removeImageArgs(refs), exec.Stdout(io.Discard)); err != nil {
+		return fmt.Errorf("remove images %s: %w", strings.Join(refs, ", "), err)
+	}
+	return nil
+}
+
+// WithRemoveAfterPush returns a copy of the client that removes the local tags of an image once it's pushed,
+// so that CI agents and developer machines don't fill up with images that are already in the registry.
+func (c DockerCmdClient) WithRemoveAfterPush() DockerCmdClient {
+	c.removeAfterPush = true
+	return c
+}
+
+// removePushedTags removes the local tags of the pushed image. The push succeeded, so failures are only reported to w.
+func (c DockerCmdClient) removePushedTags(ctx context.Context, uri string, w io.Writer, tags []string) {
+	if err := c.RemoveImage(ctx, imageNames(uri, tags)...); err != nil {
+		fmt.Fprintf(w, "Failed to clean up the pushed image: %v\n", err)
+	}
+}
+
+func removeImageArgs(refs []string) []string {
+	return append([]string{"image", "rm"}, refs...)
+}
+
+// imageNames returns the references of the image for each tag, like "<uri>:<tag>".
+func imageNames(uri string, tags []string) []string {
+	refs := make([]string, len(tags))
+	for i, tag := range tags {
+		refs[i] = imageName(uri, tag)
+	}
+	return refs
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_RemoveImage(t *testing.T) {
+	ctx := context.Background()
+	t.Run("removes all the references at once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "rm", "app:v1", "app:latest"}, gomock.Any()).Return(nil)
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		require.NoError(t, s.RemoveImage(ctx, "app:v1", "app:latest"))
+	})
+	t.Run("wraps the error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "rm", "app:v1"}, gomock.Any()).Return(errors.New("some error"))
+		s := DockerCmdClient{
+			runner: m,
+		}
+
+		require.EqualError(t, s.RemoveImage(ctx, "app:v1"), "remove images app:v1: some error")
+	})
+}
+
+func TestDockerCommand_WithRemoveAfterPush(t *testing.T) {
+	ctx := context.Background()
+	const uri = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"
+	tests := map[string]struct {
+		removeErr error
+
+		wantedOut string
+	}{
+		"removes the local tags once the image is pushed": {},
+		"only reports cleanup failures": {
+			removeErr: errors.New("some error"),
+			wantedOut: "Failed to clean up the pushed image: remove images " + uri + ":v1: some error\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			gomock.InOrder(
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":v1"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("v1: digest: sha256:f1d4ae3f size: 528\n")).Return(nil),
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"image", "rm", uri + ":v1"}, gomock.Any()).Return(tc.removeErr),
+			)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}.WithRemoveAfterPush()
+			out := &strings.Builder{}
+
+			digest, err := s.Push(ctx, uri, out, "v1")
+
+			require.NoError(t, err)
+			require.Equal(t, "sha256:f1d4ae3f", digest)
+			require.True(t, strings.HasSuffix(out.String(), tc.wantedOut))
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	defaultHealthTimeout      = time.Minute
+	defaultHealthPollInterval = time.Second
+)
+
+// Statuses reported by `docker inspect` for the health and the state of a container.
+const (
+	containerStatusHealthy   = "healthy"
+	containerStatusUnhealthy = "unhealthy"
+	containerStatusRunning   = "running"
+)
+
+// ReplaceOptions holds the options to replace a container with a new version without downtime.
+type ReplaceOptions struct {
+	Network       string        // Required. User-defined network that the old container is attached to.
+	Aliases       []string      // Required. Network aliases that the other containers use to reach the container.
+	HealthTimeout time.Duration // Optional. How long to wait for the new container to become healthy. Defaults to 1 minute.
+}
+
+// ErrContainerUnhealthy means a container didn't become healthy.
+type ErrContainerUnhealthy struct {
+	Container string
+	Status    string // Last status of the container, for example "unhealthy" or "exited".
+}
+
+func (e *ErrContainerUnhealthy) Error() string {
+	return fmt.Sprintf("container %s did not become healthy: status is %q", e.Container, e.Status)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrContainerUnhealthy) RecommendActions() string {
+	return fmt.Sprintf("Check the logs of the container with `docker logs %s`.", e.Container)
+}
+
+type containerReplacer struct {
+	client       DockerCmdClient
+	pollInterval time.Duration
+}
+
+// ReplaceContainer replaces the running container old with a new version started from next, without downtime:
+//  1. The new container starts detached on the network of opts, without its aliases.
+//  2. Once it's healthy, or running if its image has no health check, the aliases are added to it.
+//  3. The old container is detached from the network, so that the aliases only resolve to the new one, then removed.
+//
+// If the client has a trust policy, the new container only starts if the signature of its image is trusted.
+// If the new container doesn't become healthy, it's removed and the old one keeps serving traffic.
+// Ports published on the host can't move between containers, so next must not publish any: publish them from a proxy instead.
+func (c DockerCmdClient) ReplaceContainer(ctx context.Context, old string, next *RunOptions, opts *ReplaceOptions) error {
+	r := &containerReplacer{
+		client:       c,
+		pollInterval: defaultHealthPollInterval,
+	}
+	return r.replace(ctx, old, next, opts)
+}
+
+func (r *containerReplacer) replace(ctx context.Context, old string, next *RunOptions, opts *ReplaceOptions) error {
+	if err := validateReplace(old, next, opts); err != nil {
+		return err
+	}
+	c := r.client
+	next, err := c.trustedRunOptions(ctx, next)
+	if err != nil {
+		return err
+	}
+	if next, err = c.withFakeTimeLib(ctx, next); err != nil {
+		return err
+	}
+	args := append([]string{"run", "--detach", "--network", opts.Network}, next.generateRunArguments()[1:]...)
+	if err := c.runner.RunWithContext(ctx, "docker", args); err != nil {
+		return fmt.Errorf("run container %s: %w", next.ContainerName, err)
+	}
+	timeout := opts.HealthTimeout
+	if timeout == 0 {
+		timeout = defaultHealthTimeout
+	}
+	if err := r.waitForHealthy(ctx, next.ContainerName, timeout); err != nil {
+		// ctx may be canceled already, so we use a fresh context to clean up.
+		if rmErr := c.forceRemoveContainer(context.Background(), next.ContainerName); rmErr != nil {
+			return errors.Join(err, rmErr)
+		}
+		return err
+	}
+	// A container can't be connected twice to the same network, so it's reconnected to take the aliases.
+	// It doesn't receive traffic yet, so only its outbound connections are interrupted.
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"network", "disconnect", opts.Network, next.ContainerName}); err != nil {
+		return fmt.Errorf("disconnect container %s from network %s: %w", next.ContainerName, opts.Network, err)
+	}
+	connectArgs := []string{"network", "connect"}
+	for _, alias := range opts.Aliases {
+		connectArgs = append(connectArgs, "--alias", alias)
+	}
+	connectArgs = append(connectArgs, opts.Network, next.ContainerName)
+	if err := c.runner.RunWithContext(ctx, "docker", connectArgs); err != nil {
+		return fmt.Errorf("connect container %s to network %s: %w", next.ContainerName, opts.Network, err)
+	}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"network", "disconnect", opts.Network, old}); err != nil {
+		return fmt.Errorf("disconnect container %s from network %s: %w", old, opts.Network, err)
+	}
+	if err := c.forceRemoveContainer(ctx, old); err != nil {
+		return fmt.Errorf("remove container %s: %w", old, err)
+	}
+	return nil
+}
+
+func validateReplace(old string, next *RunOptions, opts *ReplaceOptions) error {
+	switch {
+	case opts.Network == "":
+		return errors.New("a network is required to replace a container")
+	case len(opts.Aliases) == 0:
+		return fmt.Errorf("network aliases are required to route traffic from container %s to its replacement", old)
+	case next.ContainerName == "" || next.ContainerName == old:
+		return fmt.Errorf("the replacement of container %s must have a different name", old)
+	case next.ContainerNetwork != "":
+		return fmt.Errorf("the replacement of container %s can't share the network of container %s", old, next.ContainerNetwork)
+	case len(next.ContainerPorts) != 0:
+		return fmt.Errorf("the replacement of container %s can't publish ports: they are still bound by the old container", old)
+	}
+	return validateRestartPolicy(next.RestartPolicy)
+}
+
+// waitForHealthy waits until the container is healthy, or running if it has no health check.
+func (r *containerReplacer) waitForHealthy(ctx context.Context, container string, timeout time.Duration) error {
+	w := &healthWaiter{
+		client:       r.client,
+		pollInterval: r.pollInterval,
+	}
+	return w.wait(ctx, container, timeout)
+}
+
+// containerStatus returns the health status of the container, or its state if it has no health check.
+func (c DockerCmdClient) containerStatus(ctx context.Context, container string) (string, error) {
+	buf := &bytes.Buffer{}
+	args := []string{"inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", container}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return "", fmt.Errorf("inspect container %s: %w", container, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerReplacer_Replace(t *testing.T) {
+	ctx := context.Background()
+	statusArgs := []string{"inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", "web-2"}
+	runArgs := []string{"run", "--detach", "--network", "copilot", "--name", "web-2", "web:v2"}
+	defaultOpts := &ReplaceOptions{
+		Network: "copilot",
+		Aliases: []string{"web"},
+	}
+
+	tests := map[string]struct {
+		next       *RunOptions
+		opts       *ReplaceOptions
+		setupMocks func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"switches the aliases to the new container once it's healthy": {
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", runArgs).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("starting\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("healthy\n")).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"network", "disconnect", "copilot", "web-2"}).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"network", "connect", "--alias", "web", "copilot", "web-2"}).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"network", "disconnect", "copilot", "web-1"}).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"rm", "--force", "web-1"}).Return(nil),
+				)
+			},
+		},
+		"removes the new container and keeps the old one if the new one is unhealthy": {
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", runArgs).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("unhealthy\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"rm", "--force", "web-2"}).Return(nil),
+				)
+			},
+			wantedErr: `container web-2 did not become healthy: status is "unhealthy"`,
+		},
+		"times out if the new container is still starting": {
+			opts: &ReplaceOptions{
+				Network:       "copilot",
+				Aliases:       []string{"web"},
+				HealthTimeout: 10 * time.Millisecond,
+			},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", runArgs).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", statusArgs, gomock.Any()).Do(mockStdout("starting\n")).Return(nil).MinTimes(1)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"rm", "--force", "web-2"}).Return(nil)
+			},
+			wantedErr: `container web-2 did not become healthy: status is "starting"`,
+		},
+		"returns a wrapped error if the new container can't start": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", runArgs).Return(errors.New("some error"))
+			},
+			wantedErr: "run container web-2: some error",
+		},
+		"errors if the new container publishes ports": {
+			next: &RunOptions{
+				ImageURI:       "web:v2",
+				ContainerName:  "web-2",
+				ContainerPorts: map[string]string{"8080": "8080"},
+			},
+			setupMocks: func(m *MockCmd) {},
+			wantedErr:  "the replacement of container web-1 can't publish ports: they are still bound by the old container",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			r := &containerReplacer{
+				client:       DockerCmdClient{runner: m},
+				pollInterval: time.Millisecond,
+			}
+			next := tc.next
+			if next == nil {
+				next = &RunOptions{ImageURI: "web:v2", ContainerName: "web-2"}
+			}
+			opts := tc.opts
+			if opts == nil {
+				opts = defaultOpts
+			}
+
+			err := r.replace(ctx, "web-1", next, opts)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
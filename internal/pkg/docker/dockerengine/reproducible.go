@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+// sourceDateEpochArg is the build arg that BuildKit uses as the timestamp of the image and its layers,
+// as defined by https://reproducible-builds.org/specs/source-date-epoch/.
+const sourceDateEpochArg = "SOURCE_DATE_EPOCH"
+
+// sourceDateEpoch returns the timestamp of reproducible builds in seconds since the Unix epoch: SOURCE_DATE_EPOCH
+// from the environment, typically set to the time of the last commit, or 0 so that builds don't depend on the clock.
+func (c DockerCmdClient) sourceDateEpoch() string {
+	if v, ok := c.lookupEnv(sourceDateEpochArg); ok && v != "" {
+		return v
+	}
+	return "0"
+}
+
+// withSourceDateEpoch returns a copy of the build args with SOURCE_DATE_EPOCH set to epoch, unless it's already set.
+func withSourceDateEpoch(buildArgs map[string]string, epoch string) map[string]string {
+	if _, ok := buildArgs[sourceDateEpochArg]; ok {
+		return buildArgs
+	}
+	args := make(map[string]string, len(buildArgs)+1)
+	for k, v := range buildArgs {
+		args[k] = v
+	}
+	args[sourceDateEpochArg] = epoch
+	return args
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+)
+
+const (
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// transientBuildErrors are messages printed by docker when a build fails because of a daemon or network hiccup rather than the build itself.
+var transientBuildErrors = []string{
+	"TLS handshake timeout",
+	"i/o timeout",
+	"connection reset by peer",
+	"unexpected EOF",
+	": EOF",
+	"net/http: request canceled while waiting for connection",
+	"Client.Timeout exceeded while awaiting headers",
+	"503 Service Unavailable",
+	"502 Bad Gateway",
+	"toomanyrequests",
+}
+
+// transientPushErrors are messages printed by docker when a push fails because of registry throttling or a network hiccup.
+var transientPushErrors = []string{
+	"blob upload unknown",
+	"429 Too Many Requests",
+	"toomanyrequests",
+	"TooManyRequestsException",
+	"ThrottlingException",
+	"503 Service Unavailable",
+	"502 Bad Gateway",
+	"connection reset by peer",
+	"TLS handshake timeout",
+	"i/o timeout",
+	"unexpected EOF",
+}
+
+// RetryPolicy configures how builds or pushes that fail with transient errors are retried.
+type RetryPolicy struct {
+	MaxAttempts    int           // Required. Maximum number of attempts, including the first one.
+	InitialBackoff time.Duration // Optional. Wait before the first retry, doubled after each retry. Defaults to 1s.
+	MaxBackoff     time.Duration // Optional. Maximum wait between retries. Defaults to 30s.
+}
+
+// backoff returns the wait before the given retry, starting at 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	wait, max := p.InitialBackoff, p.MaxBackoff
+	if wait <= 0 {
+		wait = defaultRetryInitialBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+	for i := 1; i < retry && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
+
+// jittered returns a random wait between half of wait and wait, so that concurrent clients throttled at the same time
+// don't retry in lockstep.
+func jittered(wait time.Duration) time.Duration {
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isTransientBuildFailure returns true if the build output indicates that the build failed because of a transient error.
+func isTransientBuildFailure(output string) bool {
+	for _, msg := range transientBuildErrors {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientPushFailure returns true if the push output indicates that the push failed because of a transient error.
+func isTransientPushFailure(output string) bool {
+	for _, msg := range transientPushErrors {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// tailBuffer is an io.Writer that keeps the last size bytes written to it.
+type tailBuffer struct {
+	size int
+	buf  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if extra := len(b.buf) - b.size; extra > 0 {
+		b.buf = b.buf[extra:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
+
+// withRetry calls build until it succeeds, fails with a non-transient error, or the policy's attempts are exhausted.
+// build writes its output to the writer it's given, which is inspected to recognize transient failures.
+func (p *RetryPolicy) withRetry(ctx context.Context, name string, build func(tail *tailBuffer) error) error {
+	return p.retry(ctx, "Building "+name, isTransientBuildFailure, p.backoff, build)
+}
+
+// withPushRetry calls push until it succeeds, fails with a non-transient error, or the policy's attempts are exhausted.
+// The waits between retries are jittered since registries throttle all the concurrent pushes at once.
+func (p *RetryPolicy) withPushRetry(ctx context.Context, img string, push func(tail *tailBuffer) error) error {
+	return p.retry(ctx, "Pushing "+img, isTransientPushFailure, func(retry int) time.Duration {
+		return jittered(p.backoff(retry))
+	}, push)
+}
+
+// retry calls op until it succeeds, fails with an error that isn't transient according to its output,
+// or the policy's attempts are exhausted. The action describes op in the warnings, for example "Building web:latest".
+func (p *RetryPolicy) retry(ctx context.Context, action string, isTransient func(output string) bool, backoff func(retry int) time.Duration, op func(tail *tailBuffer) error) error {
+	for attempt := 1; ; attempt++ {
+		tail := &tailBuffer{size: buildOutputTailSize}
+		err := op(tail)
+		if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !isTransient(tail.String()) {
+			return err
+		}
+		wait := backoff(attempt)
+		log.Warningf("%s failed with a transient error, retrying in %s (attempt %d/%d).\n", action, wait, attempt+1, p.MaxAttempts)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_BuildWithRetry(t *testing.T) {
+	ctx := context.Background()
+	wantedArgs := []string{"build", "-t", "mockURI:latest", "mockDir", "-f", "mockDir/Dockerfile"}
+	transientOutput := "failed to resolve source metadata for docker.io/library/golang:1.20: net/http: TLS handshake timeout\n"
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"retries transient failures until the build succeeds": {
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+						Do(mockStdout(transientOutput)).Return(errors.New("exit status 1")),
+					m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		"gives up after the maximum number of attempts": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(transientOutput)).Return(errors.New("exit status 1")).Times(3)
+			},
+			wantedErr: "building image: pull base image docker.io/library/golang:1.20: exit status 1",
+		},
+		"does not retry non-transient failures": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", wantedArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout("RUN go build: exit code: 2\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "building image: exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}
+			out := &strings.Builder{}
+
+			err := s.Build(ctx, &BuildArguments{
+				URI:        "mockURI",
+				Tags:       []string{"latest"},
+				Dockerfile: "mockDir/Dockerfile",
+				Retry:      &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			}, out)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, out.String(), transientOutput)
+		})
+	}
+}
+
+func TestDockerCommand_PushWithRetry(t *testing.T) {
+	ctx := context.Background()
+	digestArgs := []string{"inspect", "--format", "'{{json .RepoDigests}}'", "uri:latest"}
+	throttledOutput := "toomanyrequests: Rate exceeded\n"
+
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"retries the throttled tag until the push succeeds": {
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).
+						Do(mockStdout(throttledOutput)).Return(errors.New("exit status 1")),
+					m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).Return(nil),
+				)
+				m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(`["uri@sha256:abc"]`)).Return(nil)
+			},
+		},
+		"gives up after the maximum number of attempts": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("blob upload unknown to registry\n")).Return(errors.New("exit status 1")).Times(3)
+			},
+			wantedErr: "docker push uri:latest: exit status 1",
+		},
+		"does not retry non-transient failures": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"push", "uri:latest"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("denied: not authorized\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "docker push uri:latest: exit status 1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}.WithPushRetry(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+			_, err := s.Push(ctx, "uri", &strings.Builder{}, "latest")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestJittered(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		wait := jittered(time.Second)
+
+		require.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		require.LessOrEqual(t, wait, time.Second)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	require.Equal(t, time.Second, p.backoff(1))
+	require.Equal(t, 2*time.Second, p.backoff(2))
+	require.Equal(t, 4*time.Second, p.backoff(3))
+	require.Equal(t, 5*time.Second, p.backoff(4))
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{size: 5}
+
+	_, _ = b.Write([]byte("hello "))
+	_, _ = b.Write([]byte("world"))
+
+	require.Equal(t, "world", b.String())
+}
@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy decides what happens when a scheduled run is due while a previous run is still in progress.
+type OverlapPolicy string
+
+// Overlap policies for scheduled jobs.
+const (
+	OverlapSkip    OverlapPolicy = "skip"    // Skip the new run. This is the default.
+	OverlapAllow   OverlapPolicy = "allow"   // Start the new run alongside the previous one.
+	OverlapReplace OverlapPolicy = "replace" // Stop the previous run and start the new one.
+)
+
+const defaultJobHistoryLimit = 20
+
+// ScheduledJob describes a job container to run locally on a schedule.
+type ScheduledJob struct {
+	Schedule     string        // Required. Cron expression or descriptor such as "@every 5m" or "@hourly".
+	Options      *RunOptions   // Required. Options of the job container.
+	Timeout      time.Duration // Optional. Maximum duration of a single run.
+	Overlap      OverlapPolicy // Optional. Defaults to OverlapSkip.
+	HistoryLimit int           // Optional. Number of runs to keep in the history. Defaults to 20.
+}
+
+// JobRun is an entry in the run history of a scheduled job.
+type JobRun struct {
+	ScheduledAt time.Time
+	Skipped     bool           // True if the run was skipped because of the overlap policy.
+	Result      *RunOnceResult // Set if the run completed.
+	Err         error          // Set if the run could not be completed.
+}
+
+type onceRunner interface {
+	RunOnce(ctx context.Context, options *RunOptions, timeout time.Duration) (*RunOnceResult, error)
+}
+
+// JobScheduler runs a job container on a schedule with RunOnce, emulating scheduled jobs locally.
+type JobScheduler struct {
+	runner   onceRunner
+	job      ScheduledJob
+	schedule cron.Schedule
+
+	// Override in unit tests.
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	history []JobRun
+	cancels map[int]context.CancelFunc // Cancel functions of the runs in progress.
+	nextID  int
+}
+
+// NewJobScheduler returns a JobScheduler that runs the job with the client.
+func NewJobScheduler(client DockerCmdClient, job ScheduledJob) (*JobScheduler, error) {
+	return newJobScheduler(client, job)
+}
+
+func newJobScheduler(runner onceRunner, job ScheduledJob) (*JobScheduler, error) {
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("parse schedule %q: %w", job.Schedule, err)
+	}
+	if job.Overlap == "" {
+		job.Overlap = OverlapSkip
+	}
+	if job.HistoryLimit <= 0 {
+		job.HistoryLimit = defaultJobHistoryLimit
+	}
+	return &JobScheduler{
+		runner:   runner,
+		job:      job,
+		schedule: schedule,
+		now:      time.Now,
+		after:    time.After,
+		cancels:  make(map[int]context.CancelFunc),
+	}, nil
+}
+
+// Start runs the job on its schedule until ctx is canceled, then waits for the runs in progress to stop.
+func (s *JobScheduler) Start(ctx context.Context) error {
+	for {
+		next := s.schedule.Next(s.now())
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return nil
+		case <-s.after(next.Sub(s.now())):
+			if ctx.Err() != nil {
+				continue
+			}
+			s.trigger(ctx, next)
+		}
+	}
+}
+
+// History returns the most recent runs of the job, oldest first.
+func (s *JobScheduler) History() []JobRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]JobRun(nil), s.history...)
+}
+
+func (s *JobScheduler) trigger(ctx context.Context, scheduledAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cancels) > 0 {
+		switch s.job.Overlap {
+		case OverlapSkip:
+			s.record(JobRun{ScheduledAt: scheduledAt, Skipped: true})
+			return
+		case OverlapReplace:
+			for _, cancel := range s.cancels {
+				cancel()
+			}
+		}
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	id := s.nextID
+	s.nextID++
+	s.cancels[id] = cancel
+	// Give each run its own container name so that overlapping runs don't conflict.
+	opts := *s.job.Options
+	if opts.ContainerName != "" {
+		opts.ContainerName = fmt.Sprintf("%s-%d", opts.ContainerName, id)
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		result, err := s.runner.RunOnce(runCtx, &opts, s.job.Timeout)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.cancels, id)
+		s.record(JobRun{ScheduledAt: scheduledAt, Result: result, Err: err})
+	}()
+}
+
+// record appends the run to the history. The caller must hold s.mu.
+func (s *JobScheduler) record(run JobRun) {
+	s.history = append(s.history, run)
+	if extra := len(s.history) - s.job.HistoryLimit; extra > 0 {
+		s.history = s.history[extra:]
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockOnceRunner struct {
+	mu      sync.Mutex
+	names   []string
+	release chan struct{} // If set, runs block until it's closed or their context is canceled.
+	onRun   func()
+}
+
+func (m *mockOnceRunner) RunOnce(ctx context.Context, options *RunOptions, _ time.Duration) (*RunOnceResult, error) {
+	m.mu.Lock()
+	m.names = append(m.names, options.ContainerName)
+	m.mu.Unlock()
+	if m.onRun != nil {
+		m.onRun()
+	}
+	if m.release != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &RunOnceResult{ContainerName: options.ContainerName}, nil
+}
+
+func TestNewJobScheduler(t *testing.T) {
+	_, err := newJobScheduler(&mockOnceRunner{}, ScheduledJob{Schedule: "not a schedule"})
+	require.ErrorContains(t, err, `parse schedule "not a schedule"`)
+}
+
+func TestJobScheduler_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs int
+	runner := &mockOnceRunner{
+		onRun: func() {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+		},
+	}
+	s, err := newJobScheduler(runner, ScheduledJob{
+		Schedule: "@every 1m",
+		Options:  &RunOptions{ImageURI: "job", ContainerName: "job"},
+	})
+	require.NoError(t, err)
+	fired := make(chan time.Time)
+	close(fired)
+	s.after = func(time.Duration) <-chan time.Time {
+		// Wait for the previous run to be recorded so that runs don't overlap.
+		s.wg.Wait()
+		return fired
+	}
+
+	require.NoError(t, s.Start(ctx))
+
+	require.Equal(t, []string{"job-0", "job-1", "job-2"}, runner.names)
+	history := s.History()
+	require.Len(t, history, 3)
+	require.Equal(t, "job-2", history[2].Result.ContainerName)
+}
+
+func TestJobScheduler_trigger(t *testing.T) {
+	scheduledAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		overlap OverlapPolicy
+
+		wantedRuns    int
+		wantedSkipped int
+		wantedErrs    int
+	}{
+		"skips a run while the previous one is in progress": {
+			overlap:       OverlapSkip,
+			wantedRuns:    1,
+			wantedSkipped: 1,
+		},
+		"allows overlapping runs": {
+			overlap:    OverlapAllow,
+			wantedRuns: 2,
+		},
+		"replaces the run in progress": {
+			overlap:    OverlapReplace,
+			wantedRuns: 2,
+			wantedErrs: 1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := &mockOnceRunner{release: make(chan struct{})}
+			s, err := newJobScheduler(runner, ScheduledJob{
+				Schedule:     "@hourly",
+				Options:      &RunOptions{ImageURI: "job"},
+				Overlap:      tc.overlap,
+				HistoryLimit: 5,
+			})
+			require.NoError(t, err)
+
+			s.trigger(context.Background(), scheduledAt)
+			s.trigger(context.Background(), scheduledAt.Add(time.Hour))
+			close(runner.release)
+			s.wg.Wait()
+
+			var skipped, errs int
+			for _, run := range s.History() {
+				if run.Skipped {
+					skipped++
+				}
+				if run.Err != nil {
+					errs++
+				}
+			}
+			require.Len(t, runner.names, tc.wantedRuns)
+			require.Equal(t, tc.wantedSkipped, skipped)
+			require.Equal(t, tc.wantedErrs, errs)
+		})
+	}
+}
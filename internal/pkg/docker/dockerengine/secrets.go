@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Schemes of the build secrets that are resolved from AWS secret stores.
+const (
+	ssmSecretScheme            = "ssm://"
+	secretsManagerSecretScheme = "secretsmanager://"
+)
+
+// buildSecretsDirPattern is the pattern of the temporary directory that resolved build secrets are written to.
+const buildSecretsDirPattern = "copilot-build-secrets-*"
+
+// SecretGetter retrieves the value of a secret by name.
+type SecretGetter interface {
+	GetSecretValue(name string) (string, error)
+}
+
+// BuildSecretStores holds the clients that resolve build secrets referencing AWS secret stores.
+type BuildSecretStores struct {
+	SSM            SecretGetter // Resolves "ssm://<parameter name>" secrets.
+	SecretsManager SecretGetter // Resolves "secretsmanager://<secret name or ARN>" secrets.
+}
+
+// secretArgs returns the `--secret` flags that mount the build secrets, sorted by id for test stability.
+func (in *BuildArguments) secretArgs() []string {
+	var ids []string
+	for id := range in.Secrets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var args []string
+	for _, id := range ids {
+		args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", id, in.Secrets[id]))
+	}
+	return args
+}
+
+// resolveSecrets writes the values of the secrets stored in SSM or Secrets Manager to temporary files
+// and returns a copy of the arguments whose secrets point to them, along with a function that removes the files.
+func (in *BuildArguments) resolveSecrets() (*BuildArguments, func() error, error) {
+	noop := func() error { return nil }
+	if len(in.secretStoreRefs()) == 0 {
+		return in, noop, nil
+	}
+	dir, err := os.MkdirTemp("", buildSecretsDirPattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create directory for build secrets: %w", err)
+	}
+	cleanup := func() error {
+		return os.RemoveAll(dir)
+	}
+	resolved, files := in.withSecretFiles(dir)
+	for _, file := range files {
+		value, err := in.SecretStores.getSecretValue(file.src)
+		if err != nil {
+			_ = cleanup()
+			return nil, nil, fmt.Errorf("resolve build secret %s: %w", file.id, err)
+		}
+		if err := os.WriteFile(file.path, []byte(value), 0600); err != nil {
+			_ = cleanup()
+			return nil, nil, fmt.Errorf("write build secret %s: %w", file.id, err)
+		}
+	}
+	return resolved, cleanup, nil
+}
+
+// plannedSecrets returns a copy of the arguments whose secrets stored in SSM or Secrets Manager point to the files
+// that resolveSecrets would write them to, without fetching them. The name of the temporary directory is only known
+// once it's created, so it's rendered with the "*" of its pattern.
+func (in *BuildArguments) plannedSecrets() *BuildArguments {
+	if len(in.secretStoreRefs()) == 0 {
+		return in
+	}
+	planned, _ := in.withSecretFiles(filepath.Join(os.TempDir(), buildSecretsDirPattern))
+	return planned
+}
+
+// secretFile is the file that a build secret referencing a secret store is written to.
+type secretFile struct {
+	id   string
+	src  string // Reference to the secret, like "ssm://<parameter name>".
+	path string
+}
+
+// secretStoreRefs returns the ids of the build secrets that reference SSM or Secrets Manager, sorted.
+func (in *BuildArguments) secretStoreRefs() []string {
+	var ids []string
+	for id, src := range in.Secrets {
+		if strings.HasPrefix(src, ssmSecretScheme) || strings.HasPrefix(src, secretsManagerSecretScheme) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// withSecretFiles returns a copy of the arguments whose secrets that reference SSM or Secrets Manager point to files in dir,
+// along with the files to write, sorted by secret id.
+func (in *BuildArguments) withSecretFiles(dir string) (*BuildArguments, []secretFile) {
+	resolved := *in
+	resolved.Secrets = make(map[string]string, len(in.Secrets))
+	for id, src := range in.Secrets {
+		resolved.Secrets[id] = src
+	}
+	var files []secretFile
+	for i, id := range in.secretStoreRefs() {
+		// Secret ids are arbitrary strings, so the files are named by position rather than by id.
+		path := filepath.Join(dir, fmt.Sprintf("secret-%d", i))
+		files = append(files, secretFile{id: id, src: in.Secrets[id], path: path})
+		resolved.Secrets[id] = path
+	}
+	return &resolved, files
+}
+
+func (s *BuildSecretStores) getSecretValue(ref string) (string, error) {
+	if name, ok := strings.CutPrefix(ref, ssmSecretScheme); ok {
+		if s == nil || s.SSM == nil {
+			return "", fmt.Errorf("no SSM client to resolve %s", ref)
+		}
+		return s.SSM.GetSecretValue(name)
+	}
+	name := strings.TrimPrefix(ref, secretsManagerSecretScheme)
+	if s == nil || s.SecretsManager == nil {
+		return "", fmt.Errorf("no Secrets Manager client to resolve %s", ref)
+	}
+	return s.SecretsManager.GetSecretValue(name)
+}
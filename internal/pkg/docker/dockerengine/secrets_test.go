@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSecretGetter map[string]string
+
+func (s stubSecretGetter) GetSecretValue(name string) (string, error) {
+	v, ok := s[name]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return v, nil
+}
+
+func TestBuildArguments_resolveSecrets(t *testing.T) {
+	stores := &BuildSecretStores{
+		SSM:            stubSecretGetter{"/copilot/npm-token": "npm"},
+		SecretsManager: stubSecretGetter{"github-token": "gh"},
+	}
+
+	tests := map[string]struct {
+		secrets map[string]string
+		stores  *BuildSecretStores
+
+		wantedValues map[string]string // Content of the secret files keyed by id.
+		wantedErr    string
+	}{
+		"writes the secrets stored in SSM and Secrets Manager to files": {
+			secrets: map[string]string{
+				"npm":    "ssm:///copilot/npm-token",
+				"github": "secretsmanager://github-token",
+			},
+			stores: stores,
+			wantedValues: map[string]string{
+				"npm":    "npm",
+				"github": "gh",
+			},
+		},
+		"returns a wrapped error if a secret can't be retrieved": {
+			secrets: map[string]string{
+				"npm": "ssm:///copilot/missing",
+			},
+			stores:    stores,
+			wantedErr: "resolve build secret npm: secret not found",
+		},
+		"returns an error if there is no client for the secret store": {
+			secrets: map[string]string{
+				"github": "secretsmanager://github-token",
+			},
+			wantedErr: "resolve build secret github: no Secrets Manager client to resolve secretsmanager://github-token",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			in := &BuildArguments{
+				Secrets:      tc.secrets,
+				SecretStores: tc.stores,
+			}
+
+			got, cleanup, err := in.resolveSecrets()
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			for id, wanted := range tc.wantedValues {
+				content, err := os.ReadFile(got.Secrets[id])
+				require.NoError(t, err)
+				require.Equal(t, wanted, string(content))
+			}
+			require.Equal(t, tc.secrets, in.Secrets, "the input arguments must not be modified")
+			require.NoError(t, cleanup())
+			_, err = os.Stat(filepath.Dir(got.Secrets["npm"]))
+			require.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestDockerCommand_Build_Secrets(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	var secretFile string
+	m.EXPECT().RunWithContext(ctx, "docker", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, args []string, _ ...exec.CmdOption) error {
+			require.Equal(t, []string{"build", "-t", "mockURI:latest", "--secret", "id=cert,src=/certs/ca.pem", "--secret"}, args[:6])
+			var ok bool
+			secretFile, ok = strings.CutPrefix(args[6], "id=npm,src=")
+			require.True(t, ok)
+			content, err := os.ReadFile(secretFile)
+			require.NoError(t, err)
+			require.Equal(t, "s3cr3t", string(content))
+			return nil
+		})
+	s := DockerCmdClient{
+		runner:    m,
+		lookupEnv: func(string) (string, bool) { return "", false },
+	}
+
+	err := s.Build(ctx, &BuildArguments{
+		URI:        "mockURI",
+		Tags:       []string{"latest"},
+		Dockerfile: filepath.Join(t.TempDir(), "Dockerfile"),
+		Secrets: map[string]string{
+			"cert": "/certs/ca.pem",
+			"npm":  "ssm:///copilot/npm-token",
+		},
+		SecretStores: &BuildSecretStores{
+			SSM: stubSecretGetter{"/copilot/npm-token": "s3cr3t"},
+		},
+	}, &strings.Builder{})
+
+	require.NoError(t, err)
+	_, err = os.Stat(secretFile)
+	require.True(t, os.IsNotExist(err), "the secret file must be removed after the build")
+}
@@ -0,0 +1,246 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+const (
+	sessionBundleVersion = 1
+	// defaultSessionLogLines is the number of log lines kept per container in a session bundle.
+	defaultSessionLogLines = 50
+	redactedValue          = "<redacted>"
+)
+
+// Types of the events of a local run session.
+const (
+	SessionEventOperation = "operation" // A docker command run by the client.
+	SessionEventContainer = "container" // A change of state of a container, such as "started" or "exited with code 1".
+)
+
+// SessionEvent is an event of a local run session.
+type SessionEvent struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Container string    `json:"container,omitempty"`
+	Message   string    `json:"message"`
+	Err       string    `json:"error,omitempty"`
+}
+
+// SessionBundle holds the events and the last log lines of each container of a local run session,
+// so that developers can share what happened locally in bug reports.
+type SessionBundle struct {
+	Version   int                 `json:"version"`
+	StartedAt time.Time           `json:"startedAt"`
+	Events    []SessionEvent      `json:"events"`
+	Logs      map[string][]string `json:"logs,omitempty"` // Last log lines keyed by container.
+}
+
+// SessionSummary aggregates the events of a session bundle.
+type SessionSummary struct {
+	Duration         time.Duration
+	Operations       int
+	FailedOperations int
+	Containers       []string // Names of the containers with events or logs, sorted.
+}
+
+// SessionRecorder records the docker commands, container events and log excerpts of a local run session.
+// It is safe for concurrent use.
+type SessionRecorder struct {
+	logLines int
+	now      func() time.Time // Override in unit tests.
+
+	mu     sync.Mutex
+	bundle SessionBundle
+}
+
+// NewSessionRecorder returns a SessionRecorder for a session that starts now.
+func NewSessionRecorder() *SessionRecorder {
+	r := &SessionRecorder{
+		logLines: defaultSessionLogLines,
+		now:      time.Now,
+	}
+	r.bundle = SessionBundle{
+		Version:   sessionBundleVersion,
+		StartedAt: r.now(),
+		Logs:      make(map[string][]string),
+	}
+	return r
+}
+
+// RecordOperation records a docker command and its error, if any. The values of environment variables and build args are redacted.
+func (r *SessionRecorder) RecordOperation(args []string, err error) {
+	event := SessionEvent{
+		Type:    SessionEventOperation,
+		Message: "docker " + strings.Join(redactArgs(args), " "),
+	}
+	if err != nil {
+		event.Err = err.Error()
+	}
+	r.add(event)
+}
+
+// RecordContainerEvent records a change of state of the container, for example "started" or "exited with code 1".
+func (r *SessionRecorder) RecordContainerEvent(container, event string) {
+	r.add(SessionEvent{
+		Type:      SessionEventContainer,
+		Container: container,
+		Message:   event,
+	})
+}
+
+// RecordLog records a log line of the container. Only the most recent lines of each container are kept.
+func (r *SessionRecorder) RecordLog(container, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lines := append(r.bundle.Logs[container], line)
+	if extra := len(lines) - r.logLines; extra > 0 {
+		lines = lines[extra:]
+	}
+	r.bundle.Logs[container] = lines
+}
+
+// Save writes the session bundle to the file at path.
+func (r *SessionRecorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.bundle, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal session bundle: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write session bundle %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *SessionRecorder) add(event SessionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.Time = r.now()
+	r.bundle.Events = append(r.bundle.Events, event)
+}
+
+// WithRecorder returns a copy of the client that records the docker commands it runs with r.
+func (c DockerCmdClient) WithRecorder(r *SessionRecorder) DockerCmdClient {
+	c.runner = recordingRunner{
+		Cmd:      c.runner,
+		recorder: r,
+	}
+	return c
+}
+
+// recordingRunner records the docker commands that it runs.
+type recordingRunner struct {
+	Cmd
+	recorder *SessionRecorder
+}
+
+func (r recordingRunner) Run(name string, args []string, opts ...exec.CmdOption) error {
+	err := r.Cmd.Run(name, args, opts...)
+	r.recorder.RecordOperation(args, err)
+	return err
+}
+
+func (r recordingRunner) RunWithContext(ctx context.Context, name string, args []string, opts ...exec.CmdOption) error {
+	err := r.Cmd.RunWithContext(ctx, name, args, opts...)
+	r.recorder.RecordOperation(args, err)
+	return err
+}
+
+// redactArgs returns a copy of the docker arguments where the values of environment variables and build args are redacted.
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i := 1; i < len(redacted); i++ {
+		switch redacted[i-1] {
+		case "--env", "-e", "--build-arg":
+			if key, _, ok := strings.Cut(redacted[i], "="); ok {
+				redacted[i] = key + "=" + redactedValue
+			}
+		}
+	}
+	return redacted
+}
+
+// LoadSession reads the session bundle saved at path.
+func LoadSession(path string) (*SessionBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read session bundle %s: %w", path, err)
+	}
+	var bundle SessionBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("unmarshal session bundle %s: %w", path, err)
+	}
+	if bundle.Version != sessionBundleVersion {
+		return nil, fmt.Errorf("unsupported version %d of session bundle %s", bundle.Version, path)
+	}
+	return &bundle, nil
+}
+
+// Replay writes the timeline of the session to w, followed by the last log lines of each container.
+func (b *SessionBundle) Replay(w io.Writer) {
+	for _, event := range b.Events {
+		offset := event.Time.Sub(b.StartedAt).Truncate(time.Millisecond)
+		switch {
+		case event.Type == SessionEventContainer:
+			fmt.Fprintf(w, "+%s [%s] %s\n", offset, event.Container, event.Message)
+		case event.Err != "":
+			fmt.Fprintf(w, "+%s %s (failed: %s)\n", offset, event.Message, event.Err)
+		default:
+			fmt.Fprintf(w, "+%s %s\n", offset, event.Message)
+		}
+	}
+	containers := make([]string, 0, len(b.Logs))
+	for container := range b.Logs {
+		containers = append(containers, container)
+	}
+	sort.Strings(containers)
+	for _, container := range containers {
+		fmt.Fprintf(w, "\nLast logs of %s:\n", container)
+		for _, line := range b.Logs[container] {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// Summary aggregates the events of the session.
+func (b *SessionBundle) Summary() SessionSummary {
+	var summary SessionSummary
+	containers := make(map[string]bool)
+	for container := range b.Logs {
+		containers[container] = true
+	}
+	for _, event := range b.Events {
+		if event.Type == SessionEventOperation {
+			summary.Operations++
+			if event.Err != "" {
+				summary.FailedOperations++
+			}
+		}
+		if event.Container != "" {
+			containers[event.Container] = true
+		}
+		if d := event.Time.Sub(b.StartedAt); d > summary.Duration {
+			summary.Duration = d
+		}
+	}
+	for container := range containers {
+		summary.Containers = append(summary.Containers, container)
+	}
+	sort.Strings(summary.Containers)
+	return summary
+}
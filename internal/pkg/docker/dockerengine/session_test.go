@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRecorder(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	r := NewSessionRecorder()
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	r.bundle.StartedAt = start
+	r.logLines = 2
+
+	ctrl := gomock.NewController(t)
+	m := NewMockCmd(ctrl)
+	m.EXPECT().RunWithContext(ctx, "docker", []string{"run", "--name", "web", "--env", "DB_PASSWORD=s3cr3t", "web:latest"}).Return(nil)
+	m.EXPECT().Run("docker", []string{"rm", "--force", "web"}).Return(errors.New("no such container"))
+	client := DockerCmdClient{runner: m}.WithRecorder(r)
+
+	require.NoError(t, client.runner.RunWithContext(ctx, "docker", []string{"run", "--name", "web", "--env", "DB_PASSWORD=s3cr3t", "web:latest"}))
+	r.RecordContainerEvent("web", "started")
+	r.RecordLog("web", "listening on :8080")
+	r.RecordLog("web", "GET /healthz")
+	r.RecordLog("web", "panic: nil map")
+	r.RecordContainerEvent("web", "exited with code 2")
+	require.Error(t, client.runner.Run("docker", []string{"rm", "--force", "web"}))
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, r.Save(path))
+	bundle, err := LoadSession(path)
+	require.NoError(t, err)
+
+	out := &strings.Builder{}
+	bundle.Replay(out)
+	require.Equal(t, `+1s docker run --name web --env DB_PASSWORD=<redacted> web:latest
++2s [web] started
++3s [web] exited with code 2
++4s docker rm --force web (failed: no such container)
+
+Last logs of web:
+  GET /healthz
+  panic: nil map
+`, out.String())
+	require.Equal(t, SessionSummary{
+		Duration:         4 * time.Second,
+		Operations:       2,
+		FailedOperations: 1,
+		Containers:       []string{"web"},
+	}, bundle.Summary())
+}
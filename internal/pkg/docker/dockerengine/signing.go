@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// Tools that sign images.
+const (
+	SigningToolCosign   = "cosign"
+	SigningToolNotation = "notation"
+)
+
+// awsSignerPlugin is the Notation plugin that signs with AWS Signer signing profiles.
+const awsSignerPlugin = "com.amazonaws.signer.notation.plugin"
+
+// Signing holds the configuration of the signature of pushed images.
+type Signing struct {
+	Tool string // Required. Either SigningToolCosign or SigningToolNotation.
+	// Optional for cosign: URI of the key, like "awskms:///arn:aws:kms:...". Images are signed keylessly if it's empty.
+	// Required for Notation: ARN of an AWS Signer signing profile, or the name of a key in the Notation key store.
+	Key string
+}
+
+// PushedImage is an image pushed to a registry.
+type PushedImage struct {
+	Digest    string
+	Signature string // Reference of the signature of the image. Empty if the client doesn't sign images.
+}
+
+// WithSigning returns a copy of the client that signs the digest of the images it pushes with s.
+func (c DockerCmdClient) WithSigning(s Signing) DockerCmdClient {
+	c.signing = &s
+	return c
+}
+
+// PushSigned pushes the image like Push, and signs its digest if the client signs images.
+// It returns the digest of the image along with the reference of its signature, so that it can be recorded with the deployment.
+func (c DockerCmdClient) PushSigned(ctx context.Context, uri string, w io.Writer, tags ...string) (PushedImage, error) {
+	digest, err := c.push(ctx, uri, w, tags...)
+	if err != nil {
+		return PushedImage{}, err
+	}
+	img := PushedImage{Digest: digest}
+	if c.signing != nil {
+		if img.Signature, err = c.sign(ctx, uri+"@"+digest, w); err != nil {
+			return PushedImage{}, err
+		}
+	}
+	if c.removeAfterPush {
+		c.removePushedTags(ctx, uri, w, tags)
+	}
+	return img, nil
+}
+
+// sign signs the image referenced by digest and returns the reference of the signature.
+func (c DockerCmdClient) sign(ctx context.Context, ref string, w io.Writer) (string, error) {
+	switch c.signing.Tool {
+	case SigningToolCosign:
+		return c.cosignSign(ctx, ref, w)
+	case SigningToolNotation:
+		return c.notationSign(ctx, ref, w)
+	default:
+		return "", fmt.Errorf("unsupported signing tool %q", c.signing.Tool)
+	}
+}
+
+func (c DockerCmdClient) cosignSign(ctx context.Context, ref string, w io.Writer) (string, error) {
+	if err := c.runner.RunWithContext(ctx, "cosign", c.signing.cosignSignArgs(ref), exec.Stdout(w), exec.Stderr(w)); err != nil {
+		return "", fmt.Errorf("sign %s with cosign: %w", ref, err)
+	}
+	return cosignSignatureRef(ref), nil
+}
+
+// cosignSignatureRef returns the tag that cosign stores the signature of the image under,
+// like "<repo>:sha256-<hex>.sig" for "<repo>@sha256:<hex>".
+func cosignSignatureRef(ref string) string {
+	repo, digest, _ := strings.Cut(ref, "@")
+	return repo + ":" + strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+func (c DockerCmdClient) notationSign(ctx context.Context, ref string, w io.Writer) (string, error) {
+	// Notation doesn't print the digest of the signature: find it by listing the signatures before and after signing.
+	before, err := c.notationSignatures(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if err := c.runner.RunWithContext(ctx, "notation", c.signing.notationSignArgs(ref), exec.Stdout(w), exec.Stderr(w)); err != nil {
+		return "", fmt.Errorf("sign %s with notation: %w", ref, err)
+	}
+	after, err := c.notationSignatures(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	repo, _, _ := strings.Cut(ref, "@")
+	for _, sig := range after {
+		if !containsString(before, sig) {
+			return repo + "@" + sig, nil
+		}
+	}
+	return "", fmt.Errorf("find the signature of %s created by notation", ref)
+}
+
+// notationSignatures returns the digests of the Notation signatures of the image.
+func (c DockerCmdClient) notationSignatures(ctx context.Context, ref string) ([]string, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "notation", notationInspectArgs(ref), exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("inspect notation signatures of %s: %w", ref, err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(buf.Bytes()), []byte("{")) {
+		// Images without signatures are reported with a message rather than JSON.
+		return nil, nil
+	}
+	var out struct {
+		Signatures []struct {
+			Digest string `json:"digest"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &out); err != nil {
+		return nil, fmt.Errorf("unmarshal notation signatures of %s: %w", ref, err)
+	}
+	var digests []string
+	for _, sig := range out.Signatures {
+		digests = append(digests, sig.Digest)
+	}
+	return digests, nil
+}
+
+func (s *Signing) cosignSignArgs(ref string) []string {
+	args := []string{"sign", "--yes"}
+	if s.Key != "" {
+		args = append(args, "--key", s.Key)
+	}
+	return append(args, ref)
+}
+
+func (s *Signing) notationSignArgs(ref string) []string {
+	args := []string{"sign"}
+	if strings.HasPrefix(s.Key, "arn:") {
+		args = append(args, "--plugin", awsSignerPlugin, "--id", s.Key)
+	} else {
+		args = append(args, "--key", s.Key)
+	}
+	return append(args, ref)
+}
+
+func notationInspectArgs(ref string) []string {
+	return []string{"inspect", "--output", "json", ref}
+}
+
+// commands returns the commands that sign the image referenced by digest, in order.
+// Each command is the full argv, starting with the signing tool.
+func (s *Signing) commands(ref string) [][]string {
+	switch s.Tool {
+	case SigningToolCosign:
+		return [][]string{append([]string{"cosign"}, s.cosignSignArgs(ref)...)}
+	case SigningToolNotation:
+		inspect := append([]string{"notation"}, notationInspectArgs(ref)...)
+		return [][]string{inspect, append([]string{"notation"}, s.notationSignArgs(ref)...), inspect}
+	}
+	return nil
+}
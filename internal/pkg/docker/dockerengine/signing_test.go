@@ -0,0 +1,135 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_PushSigned(t *testing.T) {
+	ctx := context.Background()
+	const (
+		uri    = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc"
+		digest = "sha256:f1d4ae3f"
+		ref    = uri + "@" + digest
+	)
+	expectPush := func(m *MockCmd) {
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"push", uri + ":v1"}, gomock.Any(), gomock.Any()).
+			Do(mockStdout("v1: digest: " + digest + " size: 528\n")).Return(nil)
+	}
+	tests := map[string]struct {
+		signing   *Signing
+		setupMock func(m *MockCmd)
+
+		wanted    PushedImage
+		wantedErr string
+	}{
+		"doesn't sign images by default": {
+			setupMock: expectPush,
+			wanted:    PushedImage{Digest: digest},
+		},
+		"signs keylessly with cosign": {
+			signing: &Signing{Tool: SigningToolCosign},
+			setupMock: func(m *MockCmd) {
+				expectPush(m)
+				m.EXPECT().RunWithContext(ctx, "cosign", []string{"sign", "--yes", ref}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wanted: PushedImage{
+				Digest:    digest,
+				Signature: uri + ":sha256-f1d4ae3f.sig",
+			},
+		},
+		"signs with a KMS key with cosign": {
+			signing: &Signing{Tool: SigningToolCosign, Key: "awskms:///alias/signing"},
+			setupMock: func(m *MockCmd) {
+				expectPush(m)
+				m.EXPECT().RunWithContext(ctx, "cosign", []string{"sign", "--yes", "--key", "awskms:///alias/signing", ref}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wanted: PushedImage{
+				Digest:    digest,
+				Signature: uri + ":sha256-f1d4ae3f.sig",
+			},
+		},
+		"signs with an AWS Signer profile with notation": {
+			signing: &Signing{Tool: SigningToolNotation, Key: "arn:aws:signer:us-west-2:123456789012:/signing-profiles/app"},
+			setupMock: func(m *MockCmd) {
+				inspectArgs := []string{"inspect", "--output", "json", ref}
+				expectPush(m)
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "notation", inspectArgs, gomock.Any()).
+						Do(mockStdout(`{"signatures":[{"digest":"sha256:aaaa"}]}`)).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "notation", []string{"sign", "--plugin", "com.amazonaws.signer.notation.plugin", "--id", "arn:aws:signer:us-west-2:123456789012:/signing-profiles/app", ref}, gomock.Any(), gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "notation", inspectArgs, gomock.Any()).
+						Do(mockStdout(`{"signatures":[{"digest":"sha256:aaaa"},{"digest":"sha256:bbbb"}]}`)).Return(nil),
+				)
+			},
+			wanted: PushedImage{
+				Digest:    digest,
+				Signature: uri + "@sha256:bbbb",
+			},
+		},
+		"signs an unsigned image with a local key with notation": {
+			signing: &Signing{Tool: SigningToolNotation, Key: "dev"},
+			setupMock: func(m *MockCmd) {
+				inspectArgs := []string{"inspect", "--output", "json", ref}
+				expectPush(m)
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "notation", inspectArgs, gomock.Any()).
+						Do(mockStdout(ref+" has no associated signature\n")).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "notation", []string{"sign", "--key", "dev", ref}, gomock.Any(), gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "notation", inspectArgs, gomock.Any()).
+						Do(mockStdout(`{"signatures":[{"digest":"sha256:cccc"}]}`)).Return(nil),
+				)
+			},
+			wanted: PushedImage{
+				Digest:    digest,
+				Signature: uri + "@sha256:cccc",
+			},
+		},
+		"returns a wrapped error if signing fails": {
+			signing: &Signing{Tool: SigningToolCosign},
+			setupMock: func(m *MockCmd) {
+				expectPush(m)
+				m.EXPECT().RunWithContext(ctx, "cosign", gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "sign " + ref + " with cosign: some error",
+		},
+		"returns an error for unsupported tools": {
+			signing:   &Signing{Tool: "gpg"},
+			setupMock: expectPush,
+			wantedErr: `unsupported signing tool "gpg"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}
+			if tc.signing != nil {
+				s = s.WithSigning(*tc.signing)
+			}
+
+			got, err := s.PushSigned(ctx, uri, &strings.Builder{}, "v1")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// maxLargestLayers is the number of layers listed as the largest ones in a size report.
+const maxLargestLayers = 5
+
+// LayerSize is the size of a layer of an image along with the instruction that created it.
+type LayerSize struct {
+	CreatedBy string
+	Size      int64 // Size in bytes.
+}
+
+// SizeReport breaks down the size of an image by layer.
+type SizeReport struct {
+	Image   string
+	Total   int64       // Size of the image in bytes.
+	Layers  []LayerSize // Layers from the base image to the last instruction, including empty ones.
+	Largest []LayerSize // Largest non-empty layers, from the largest to the smallest.
+}
+
+// historyOutput is a line of the JSON output of `docker history`.
+type historyOutput struct {
+	CreatedBy string `json:"CreatedBy"`
+	Size      string `json:"Size"`
+}
+
+// ImageSizeReport returns the size of the image and of each of its layers, so that users can find the instructions
+// that make the image grow, for example past the size that Fargate tasks can pull quickly.
+func (c DockerCmdClient) ImageSizeReport(ctx context.Context, image string) (*SizeReport, error) {
+	img, err := c.inspectLocalImage(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	args := []string{"history", "--no-trunc", "--human=false", "--format", "{{json .}}", image}
+	if err := c.runner.RunWithContext(ctx, "docker", args, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("docker history %s: %w", image, err)
+	}
+	var layers []LayerSize
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // Instructions like long RUN commands don't fit the default buffer.
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var out historyOutput
+		if err := json.Unmarshal(line, &out); err != nil {
+			return nil, fmt.Errorf("unmarshal docker history output %q: %w", line, err)
+		}
+		size, err := strconv.ParseInt(out.Size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse size %q of layer created by %q: %w", out.Size, out.CreatedBy, err)
+		}
+		layers = append(layers, LayerSize{
+			CreatedBy: out.CreatedBy,
+			Size:      size,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read docker history output: %w", err)
+	}
+	// docker history lists the layers from the most recent one.
+	for i, j := 0, len(layers)-1; i < j; i, j = i+1, j-1 {
+		layers[i], layers[j] = layers[j], layers[i]
+	}
+	return &SizeReport{
+		Image:   image,
+		Total:   img.Size,
+		Layers:  layers,
+		Largest: largestLayers(layers),
+	}, nil
+}
+
+func largestLayers(layers []LayerSize) []LayerSize {
+	var largest []LayerSize
+	for _, layer := range layers {
+		if layer.Size > 0 {
+			largest = append(largest, layer)
+		}
+	}
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].Size > largest[j].Size
+	})
+	if len(largest) > maxLargestLayers {
+		largest = largest[:maxLargestLayers]
+	}
+	return largest
+}
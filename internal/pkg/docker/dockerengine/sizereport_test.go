@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_ImageSizeReport(t *testing.T) {
+	ctx := context.Background()
+	inspectArgs := []string{"image", "inspect", "app:latest"}
+	historyArgs := []string{"history", "--no-trunc", "--human=false", "--format", "{{json .}}", "app:latest"}
+	tests := map[string]struct {
+		setupMock func(m *MockCmd)
+
+		wanted    *SizeReport
+		wantedErr string
+	}{
+		"reports the size of each layer and the largest ones": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", inspectArgs, gomock.Any()).
+					Do(mockStdout(`[{"Id":"sha256:1111","Size":1200}]`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", historyArgs, gomock.Any()).
+					Do(mockStdout(`{"CreatedBy":"CMD [\"app\"]","Size":"0"}
+{"CreatedBy":"COPY . /app","Size":"100"}
+{"CreatedBy":"RUN apt-get install -y build-essential","Size":"1000"}
+{"CreatedBy":"/bin/sh -c #(nop) ADD file:base in /","Size":"100"}
+`)).Return(nil)
+			},
+			wanted: &SizeReport{
+				Image: "app:latest",
+				Total: 1200,
+				Layers: []LayerSize{
+					{CreatedBy: "/bin/sh -c #(nop) ADD file:base in /", Size: 100},
+					{CreatedBy: "RUN apt-get install -y build-essential", Size: 1000},
+					{CreatedBy: "COPY . /app", Size: 100},
+					{CreatedBy: `CMD ["app"]`, Size: 0},
+				},
+				Largest: []LayerSize{
+					{CreatedBy: "RUN apt-get install -y build-essential", Size: 1000},
+					{CreatedBy: "/bin/sh -c #(nop) ADD file:base in /", Size: 100},
+					{CreatedBy: "COPY . /app", Size: 100},
+				},
+			},
+		},
+		"wraps errors from docker history": {
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", inspectArgs, gomock.Any()).
+					Do(mockStdout(`[{"Id":"sha256:1111","Size":1200}]`)).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", historyArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "docker history app:latest: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.ImageSizeReport(ctx, "app:latest")
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// remoteManifest is the subset of `docker manifest inspect --verbose` output for a single-platform image that we care about.
+type remoteManifest struct {
+	Descriptor struct {
+		Digest string `json:"digest"`
+	} `json:"Descriptor"`
+	SchemaV2Manifest *manifestConfig `json:"SchemaV2Manifest"`
+	OCIManifest      *manifestConfig `json:"OCIManifest"`
+}
+
+type manifestConfig struct {
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// WithSkipUnchangedPushes returns a copy of the client that doesn't push a tag if it already points to the local image
+// in the registry, for example for sidecars that didn't change since the last deployment.
+// The image is the same if the config of the remote manifest has the digest of the local image ID.
+func (c DockerCmdClient) WithSkipUnchangedPushes() DockerCmdClient {
+	c.skipUnchanged = true
+	return c
+}
+
+// pushedDigest returns the digest of the manifest that img points to in its registry, and true if it's the local image.
+// The check is best-effort: it returns false if the image can't be inspected, so that it's pushed.
+func (c DockerCmdClient) pushedDigest(ctx context.Context, img string) (string, bool) {
+	local, err := c.inspectLocalImage(ctx, img)
+	if err != nil {
+		return "", false
+	}
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", manifestInspectArgs(img), exec.Stdout(buf), exec.Stderr(&bytes.Buffer{})); err != nil {
+		// The tag doesn't exist in the registry yet.
+		return "", false
+	}
+	var remote remoteManifest
+	// Multi-platform images are rendered as a list of manifests, and are never skipped.
+	if err := json.Unmarshal(buf.Bytes(), &remote); err != nil {
+		return "", false
+	}
+	config := remote.SchemaV2Manifest
+	if config == nil {
+		config = remote.OCIManifest
+	}
+	if config == nil || remote.Descriptor.Digest == "" || config.Config.Digest != local.ID {
+		return "", false
+	}
+	return remote.Descriptor.Digest, true
+}
+
+func manifestInspectArgs(img string) []string {
+	return []string{"manifest", "inspect", "--verbose", img}
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_PushSkipUnchanged(t *testing.T) {
+	const img = "uri:latest"
+	manifestArgs := []string{"manifest", "inspect", "--verbose", img}
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wantedDigest string
+		wantedOut    string
+	}{
+		"skips the push if the registry has the local image": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", img}, gomock.Any()).
+					Do(mockStdout(`[{"Id":"sha256:config"}]`)).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", manifestArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"Descriptor":{"digest":"sha256:manifest"},"SchemaV2Manifest":{"config":{"digest":"sha256:config"}}}`)).Return(nil)
+			},
+			wantedDigest: "sha256:manifest",
+			wantedOut:    "Image uri:latest is already up to date in the registry, skipping the push.\n",
+		},
+		"pushes if the registry has another image": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", img}, gomock.Any()).
+					Do(mockStdout(`[{"Id":"sha256:config"}]`)).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", manifestArgs, gomock.Any(), gomock.Any()).
+					Do(mockStdout(`{"Descriptor":{"digest":"sha256:manifest"},"OCIManifest":{"config":{"digest":"sha256:old"}}}`)).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"push", img}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("latest: digest: sha256:beef size: 528\n")).Return(nil)
+			},
+			wantedDigest: "sha256:beef",
+			wantedOut:    "latest: digest: sha256:beef size: 528\n",
+		},
+		"pushes if the tag doesn't exist in the registry": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", img}, gomock.Any()).
+					Do(mockStdout(`[{"Id":"sha256:config"}]`)).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", manifestArgs, gomock.Any(), gomock.Any()).
+					Return(errors.New("no such manifest"))
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"push", img}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("latest: digest: sha256:beef size: 528\n")).Return(nil)
+			},
+			wantedDigest: "sha256:beef",
+			wantedOut:    "latest: digest: sha256:beef size: 528\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			c := DockerCmdClient{
+				runner: m,
+				lookupEnv: func(string) (string, bool) {
+					return "", false
+				},
+			}.WithSkipUnchangedPushes()
+			out := &strings.Builder{}
+
+			digest, err := c.Push(context.Background(), "uri", out, "latest")
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedDigest, digest)
+			require.Equal(t, tc.wantedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/google/uuid"
+)
+
+const (
+	smokeTestContainerPrefix = "copilot-smoke-test-"
+	defaultSmokeTestTimeout  = 30 * time.Second
+	smokeTestLogLines        = 20
+)
+
+// SmokeTestOptions configures the smoke test that runs a freshly built image before it's pushed,
+// so that an image that can't even start fails the deployment early.
+type SmokeTestOptions struct {
+	Command     []string      // Optional. Command to run in the image instead of starting it. It must exit with 0 before the timeout.
+	HealthCheck []string      // Optional. Command executed in the started container until it exits with 0. Ignored if Command is set.
+	Timeout     time.Duration // Optional. Defaults to 30 seconds.
+}
+
+// ErrSmokeTestFailed means an image failed its smoke test.
+type ErrSmokeTestFailed struct {
+	Image  string
+	Reason string // For example "container exited".
+	Logs   string // Last lines of the output of the container.
+}
+
+func (e *ErrSmokeTestFailed) Error() string {
+	if e.Logs == "" {
+		return fmt.Sprintf("smoke test of image %s failed: %s", e.Image, e.Reason)
+	}
+	return fmt.Sprintf("smoke test of image %s failed: %s. Last logs of the container:\n%s", e.Image, e.Reason, e.Logs)
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrSmokeTestFailed) RecommendActions() string {
+	return fmt.Sprintf("Run the image locally with `docker run --rm %s` to reproduce the failure.", e.Image)
+}
+
+type smokeTester struct {
+	client DockerCmdClient
+
+	// Override in unit tests.
+	pollInterval  time.Duration
+	containerName func() string
+}
+
+// SmokeTest runs the local image to check that it can start:
+//   - If opts.Command is set, the command runs in the image and must exit with 0 before the timeout.
+//   - Otherwise the container starts with the default command of the image and must pass opts.HealthCheck,
+//     or the HEALTHCHECK of the image, before the timeout. Without any health check, it must still be running at the timeout.
+//
+// The container is removed afterwards.
+func (c DockerCmdClient) SmokeTest(ctx context.Context, image string, opts *SmokeTestOptions) error {
+	t := &smokeTester{
+		client:       c,
+		pollInterval: defaultHealthPollInterval,
+		containerName: func() string {
+			return smokeTestContainerPrefix + uuid.NewString()
+		},
+	}
+	return t.test(ctx, image, opts)
+}
+
+func (t *smokeTester) test(ctx context.Context, image string, opts *SmokeTestOptions) error {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultSmokeTestTimeout
+	}
+	if len(opts.Command) != 0 {
+		return t.runCommand(ctx, image, opts.Command, timeout)
+	}
+	c := t.client
+	name := t.containerName()
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"run", "--detach", "--name", name, image}, exec.Stdout(&bytes.Buffer{})); err != nil {
+		return fmt.Errorf("run image %s: %w", image, err)
+	}
+	err := t.waitForStart(ctx, image, name, opts.HealthCheck, timeout)
+	// ctx may be canceled already, so we use a fresh context to clean up.
+	if rmErr := c.forceRemoveContainer(context.Background(), name); rmErr != nil {
+		return errors.Join(err, rmErr)
+	}
+	return err
+}
+
+// runCommand runs the command in the image and expects it to exit with 0 before the timeout.
+func (t *smokeTester) runCommand(ctx context.Context, image string, cmd []string, timeout time.Duration) error {
+	result, err := t.client.RunOnce(ctx, &RunOptions{
+		ImageURI:      image,
+		ContainerName: t.containerName(),
+		Command:       cmd,
+	}, timeout)
+	if err != nil {
+		return err
+	}
+	logs := lastLines(result.Stdout+result.Stderr, smokeTestLogLines)
+	switch {
+	case result.TimedOut:
+		return &ErrSmokeTestFailed{
+			Image:  image,
+			Reason: fmt.Sprintf("command %q did not complete within %s", strings.Join(cmd, " "), timeout),
+			Logs:   logs,
+		}
+	case result.ExitCode != 0:
+		return &ErrSmokeTestFailed{
+			Image:  image,
+			Reason: fmt.Sprintf("command %q exited with code %d", strings.Join(cmd, " "), result.ExitCode),
+			Logs:   logs,
+		}
+	}
+	return nil
+}
+
+// waitForStart polls the container until it passes its health check, or until the timeout.
+func (t *smokeTester) waitForStart(ctx context.Context, image, container string, healthCheck []string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		status, err := t.client.containerStatus(ctx, container)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case containerStatusUnhealthy, "exited", "dead":
+			return t.failed(image, container, fmt.Sprintf("container is %s", status))
+		case containerStatusHealthy:
+			if len(healthCheck) == 0 {
+				return nil
+			}
+		}
+		if len(healthCheck) != 0 {
+			// The command may fail to execute while the container is still starting, so errors are retried.
+			if result, err := t.client.ExecCapture(ctx, container, healthCheck); err == nil && result.ExitCode == 0 {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("smoke test image %s: %w", image, ctx.Err())
+		case <-deadline:
+			if len(healthCheck) == 0 && status == containerStatusRunning {
+				// The image has no health check: staying up is all it can prove.
+				return nil
+			}
+			return t.failed(image, container, fmt.Sprintf("container did not become healthy within %s", timeout))
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+// failed returns ErrSmokeTestFailed with the last logs of the container, if they can be retrieved.
+func (t *smokeTester) failed(image, container, reason string) error {
+	buf := &bytes.Buffer{}
+	args := []string{"logs", "--tail", fmt.Sprint(smokeTestLogLines), container}
+	if err := t.client.runner.RunWithContext(context.Background(), "docker", args, exec.Stdout(buf), exec.Stderr(buf)); err != nil {
+		buf.Reset()
+	}
+	return &ErrSmokeTestFailed{
+		Image:  image,
+		Reason: reason,
+		Logs:   strings.TrimSpace(buf.String()),
+	}
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmokeTester_Test(t *testing.T) {
+	ctx := context.Background()
+	runArgs := []string{"run", "--detach", "--name", "smoke", "web:latest"}
+	statusArgs := []string{"inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", "smoke"}
+	logsArgs := []string{"logs", "--tail", "20", "smoke"}
+	rmArgs := []string{"rm", "--force", "smoke"}
+
+	tests := map[string]struct {
+		opts       *SmokeTestOptions
+		setupMocks func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"passes once the container is healthy": {
+			opts: &SmokeTestOptions{},
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("starting\n")).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("healthy\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", rmArgs).Return(nil),
+				)
+			},
+		},
+		"passes if the container without health check is still running at the timeout": {
+			opts: &SmokeTestOptions{Timeout: 10 * time.Millisecond},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil).MinTimes(1)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", rmArgs).Return(nil)
+			},
+		},
+		"passes once the health check command succeeds": {
+			opts: &SmokeTestOptions{HealthCheck: []string{"curl", "-f", "localhost:8080"}},
+			setupMocks: func(m *MockCmd) {
+				execArgs := []string{"exec", "smoke", "curl", "-f", "localhost:8080"}
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", execArgs, gomock.Any(), gomock.Any()).Return(&mockExitError{code: 7}),
+					m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", execArgs, gomock.Any(), gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", rmArgs).Return(nil),
+				)
+			},
+		},
+		"fails with the logs of a container that exits": {
+			opts: &SmokeTestOptions{},
+			setupMocks: func(m *MockCmd) {
+				gomock.InOrder(
+					m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(nil),
+					m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("exited\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", logsArgs, gomock.Any(), gomock.Any()).
+						Do(mockStdout("panic: missing environment variable PORT\n")).Return(nil),
+					m.EXPECT().RunWithContext(gomock.Any(), "docker", rmArgs).Return(nil),
+				)
+			},
+			wantedErr: "smoke test of image web:latest failed: container is exited. Last logs of the container:\npanic: missing environment variable PORT",
+		},
+		"fails if the health check doesn't pass before the timeout": {
+			opts: &SmokeTestOptions{HealthCheck: []string{"false"}, Timeout: 10 * time.Millisecond},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(nil)
+				m.EXPECT().RunWithContext(ctx, "docker", statusArgs, gomock.Any()).Do(mockStdout("running\n")).Return(nil).MinTimes(1)
+				m.EXPECT().RunWithContext(ctx, "docker", []string{"exec", "smoke", "false"}, gomock.Any(), gomock.Any()).
+					Return(&mockExitError{code: 1}).MinTimes(1)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", logsArgs, gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", rmArgs).Return(nil)
+			},
+			wantedErr: "smoke test of image web:latest failed: container did not become healthy within 10ms",
+		},
+		"fails if the command exits with a non-zero code": {
+			opts: &SmokeTestOptions{Command: []string{"/app", "--version"}},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"run", "--rm", "--name", "smoke", "web:latest", "/app", "--version"}, gomock.Any(), gomock.Any()).
+					Do(mockStdout("exec /app: exec format error\n")).Return(&mockExitError{code: 1})
+			},
+			wantedErr: "smoke test of image web:latest failed: command \"/app --version\" exited with code 1. Last logs of the container:\nexec /app: exec format error",
+		},
+		"returns a wrapped error if the container can't start": {
+			opts: &SmokeTestOptions{},
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", runArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "run image web:latest: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := &smokeTester{
+				client: DockerCmdClient{
+					runner: m,
+				},
+				containerName: func() string {
+					return "smoke"
+				},
+			}
+
+			err := s.test(ctx, "web:latest", tc.opts)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/docker/dockerfile"
+	"github.com/spf13/afero"
+)
+
+// Stages returns the names of the build stages declared in the Dockerfile, in order.
+func (in *BuildArguments) Stages() ([]string, error) {
+	return dockerfile.New(afero.NewOsFs(), in.Dockerfile).GetStages()
+}
+
+// validateTarget returns ErrInvalidTarget if the target build stage is not declared in the Dockerfile.
+// The validation is best-effort: if the Dockerfile can't be parsed, the build is left to report the problem.
+func (in *BuildArguments) validateTarget() error {
+	if in.Target == "" || in.Dockerfile == stdinPath {
+		return nil
+	}
+	stages, err := in.Stages()
+	if err != nil {
+		return nil
+	}
+	for _, stage := range stages {
+		// Stage names are case-insensitive.
+		if strings.EqualFold(stage, in.Target) {
+			return nil
+		}
+	}
+	return &ErrInvalidTarget{
+		Target:     in.Target,
+		Dockerfile: in.Dockerfile,
+		Stages:     stages,
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildArguments_validateTarget(t *testing.T) {
+	dir := t.TempDir()
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, os.WriteFile(dockerfile, []byte(`
+FROM golang:1.20 AS build
+RUN go build -o /app .
+
+FROM build AS test
+RUN go test ./...
+
+FROM scratch
+COPY --from=build /app /app
+`), 0644))
+	unnamed := filepath.Join(dir, "Dockerfile.unnamed")
+	require.NoError(t, os.WriteFile(unnamed, []byte("FROM nginx\n"), 0644))
+
+	tests := map[string]struct {
+		in *BuildArguments
+
+		wantedErr error
+	}{
+		"passes without a target": {
+			in: &BuildArguments{Dockerfile: dockerfile},
+		},
+		"passes if the target is a stage of the Dockerfile": {
+			in: &BuildArguments{Dockerfile: dockerfile, Target: "Test"},
+		},
+		"lists the valid targets on a typo": {
+			in: &BuildArguments{Dockerfile: dockerfile, Target: "tset"},
+			wantedErr: &ErrInvalidTarget{
+				Target:     "tset",
+				Dockerfile: dockerfile,
+				Stages:     []string{"build", "test"},
+			},
+		},
+		"fails if the Dockerfile has no named stages": {
+			in: &BuildArguments{Dockerfile: unnamed, Target: "build"},
+			wantedErr: &ErrInvalidTarget{
+				Target:     "build",
+				Dockerfile: unnamed,
+			},
+		},
+		"leaves it to docker if the Dockerfile can't be read": {
+			in: &BuildArguments{Dockerfile: filepath.Join(dir, "missing"), Target: "build"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.in.validateTarget()
+
+			if tc.wantedErr != nil {
+				require.Equal(t, tc.wantedErr, err)
+				var target *ErrInvalidTarget
+				require.True(t, errors.As(err, &target))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestErrInvalidTarget_Error(t *testing.T) {
+	err := &ErrInvalidTarget{
+		Target:     "tset",
+		Dockerfile: "Dockerfile",
+		Stages:     []string{"build", "test"},
+	}
+	require.EqualError(t, err, `target stage "tset" not found in Dockerfile: valid targets are build, test`)
+}
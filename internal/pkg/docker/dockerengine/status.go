@@ -0,0 +1,191 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// States of a container of a local task.
+const (
+	ContainerStateStarting = "starting"
+	ContainerStateRunning  = "running"
+	ContainerStateExited   = "exited"
+)
+
+// Health statuses of a container with a health check.
+const (
+	ContainerHealthStarting  = "starting"
+	ContainerHealthHealthy   = "healthy"
+	ContainerHealthUnhealthy = "unhealthy"
+)
+
+// ContainerStatus is the live status of a container of a local task.
+type ContainerStatus struct {
+	Name        string            `json:"name"`
+	Image       string            `json:"image,omitempty"`
+	State       string            `json:"state"`
+	Health      string            `json:"health,omitempty"` // Empty if the container has no health check.
+	Ports       map[string]string `json:"ports,omitempty"`  // Container ports keyed by host port.
+	ExitCode    *int              `json:"exitCode,omitempty"`
+	Restarts    int               `json:"restarts"`
+	LastRestart *time.Time        `json:"lastRestart,omitempty"`
+}
+
+// TaskStatus is the live status of all the containers of a local task.
+type TaskStatus struct {
+	Containers []ContainerStatus `json:"containers"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+// StatusTracker keeps the status of the containers of a local task up to date for tools such as editor plugins.
+// The status is exposed as JSON over HTTP and, optionally, in a file rewritten on every update.
+type StatusTracker struct {
+	file string
+
+	// Override in unit tests.
+	now func() time.Time
+
+	mu         sync.Mutex
+	containers map[string]*ContainerStatus
+	updatedAt  time.Time
+}
+
+// NewStatusTracker returns a StatusTracker. If file is not empty, the status is written to it as JSON on every update.
+func NewStatusTracker(file string) *StatusTracker {
+	return &StatusTracker{
+		file:       file,
+		now:        time.Now,
+		containers: make(map[string]*ContainerStatus),
+	}
+}
+
+// Update applies fn to the status of the container, creating it if it isn't tracked yet.
+func (t *StatusTracker) Update(name string, fn func(status *ContainerStatus)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status, ok := t.containers[name]
+	if !ok {
+		status = &ContainerStatus{
+			Name:  name,
+			State: ContainerStateStarting,
+		}
+		t.containers[name] = status
+	}
+	fn(status)
+	t.updatedAt = t.now()
+	return t.writeFile()
+}
+
+// RecordRestart marks the container as restarted.
+func (t *StatusTracker) RecordRestart(name string) error {
+	now := t.now()
+	return t.Update(name, func(status *ContainerStatus) {
+		status.Restarts++
+		status.LastRestart = &now
+		status.State = ContainerStateStarting
+		status.ExitCode = nil
+	})
+}
+
+// Remove stops tracking the container.
+func (t *StatusTracker) Remove(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.containers, name)
+	t.updatedAt = t.now()
+	return t.writeFile()
+}
+
+// Status returns the status of the tracked containers sorted by name.
+func (t *StatusTracker) Status() TaskStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status()
+}
+
+// ServeHTTP responds with the status of the local task as JSON.
+func (t *StatusTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(t.Status())
+}
+
+// Serve serves the status on the listener until ctx is canceled.
+func (t *StatusTracker) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:           t,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("serve local task status: %w", err)
+	}
+}
+
+// status returns a copy of the status. The caller must hold t.mu.
+func (t *StatusTracker) status() TaskStatus {
+	out := TaskStatus{
+		Containers: make([]ContainerStatus, 0, len(t.containers)),
+		UpdatedAt:  t.updatedAt,
+	}
+	for _, status := range t.containers {
+		out.Containers = append(out.Containers, *status)
+	}
+	sort.Slice(out.Containers, func(i, j int) bool {
+		return out.Containers[i].Name < out.Containers[j].Name
+	})
+	return out
+}
+
+// writeFile replaces the status file so that readers never observe a partial write. The caller must hold t.mu.
+func (t *StatusTracker) writeFile() error {
+	if t.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.status(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal local task status: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.file), filepath.Base(t.file)+".tmp")
+	if err != nil {
+		return fmt.Errorf("create local task status file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write local task status file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write local task status file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), t.file); err != nil {
+		return fmt.Errorf("replace local task status file %s: %w", t.file, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTracker(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	file := filepath.Join(t.TempDir(), "status.json")
+	tracker := NewStatusTracker(file)
+	tracker.now = func() time.Time {
+		return now
+	}
+
+	require.NoError(t, tracker.Update("web", func(status *ContainerStatus) {
+		status.Image = "web:latest"
+		status.State = ContainerStateRunning
+		status.Health = ContainerHealthHealthy
+		status.Ports = map[string]string{"8080": "80"}
+	}))
+	require.NoError(t, tracker.Update("logging", func(status *ContainerStatus) {}))
+	require.NoError(t, tracker.RecordRestart("web"))
+	wanted := TaskStatus{
+		Containers: []ContainerStatus{
+			{Name: "logging", State: ContainerStateStarting},
+			{Name: "web", Image: "web:latest", State: ContainerStateStarting, Health: ContainerHealthHealthy,
+				Ports: map[string]string{"8080": "80"}, Restarts: 1, LastRestart: &now},
+		},
+		UpdatedAt: now,
+	}
+
+	t.Run("returns the status of all the containers", func(t *testing.T) {
+		require.Equal(t, wanted, tracker.Status())
+	})
+	t.Run("writes the status file", func(t *testing.T) {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		var got TaskStatus
+		require.NoError(t, json.Unmarshal(data, &got))
+		require.Equal(t, wanted, got)
+	})
+	t.Run("serves the status as JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var got TaskStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.Equal(t, wanted, got)
+	})
+	t.Run("stops tracking removed containers", func(t *testing.T) {
+		require.NoError(t, tracker.Remove("logging"))
+
+		require.Len(t, tracker.Status().Containers, 1)
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"io"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+// WithStdin returns a copy of the client that pipes r to the standard input of the commands that read from it:
+//   - Build, if the Dockerfile or the context is "-", to build a Dockerfile or a tar build context from r.
+//   - Login, if the password is empty, to read it from r, for example from a password file.
+//   - Run and ExecCapture, which keep the standard input of the container open.
+//
+// The content of r can only be read once, so the copy should run a single command.
+func (c DockerCmdClient) WithStdin(r io.Reader) DockerCmdClient {
+	c.stdin = r
+	return c
+}
+
+// withStdin appends the option that pipes the stdin of the client to opts, if it has one.
+func (c DockerCmdClient) withStdin(opts ...exec.CmdOption) []exec.CmdOption {
+	if c.stdin == nil {
+		return opts
+	}
+	return append(opts, exec.Stdin(c.stdin))
+}
+
+// runArgs returns the arguments of `docker run` for options, which keep the standard input of the container open if the client has one.
+func (c DockerCmdClient) runArgs(options *RunOptions) []string {
+	args := options.generateRunArguments()
+	if c.stdin == nil {
+		return args
+	}
+	return append([]string{args[0], "--interactive"}, args[1:]...)
+}
+
+// readsStdin returns true if the Dockerfile or the build context is read from stdin.
+func (in *BuildArguments) readsStdin() bool {
+	return in.Dockerfile == stdinPath || in.Context == stdinPath
+}
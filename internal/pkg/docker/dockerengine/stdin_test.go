@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"io"
+	osexec "os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// readStdin returns a function for gomock's Do that records the standard input of the command in stdin.
+func readStdin(stdin *string) func(context.Context, string, []string, ...exec.CmdOption) {
+	return func(_ context.Context, _ string, _ []string, opts ...exec.CmdOption) {
+		*stdin = stdinOf(opts)
+	}
+}
+
+func stdinOf(opts []exec.CmdOption) string {
+	cmd := &osexec.Cmd{}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+	if cmd.Stdin == nil {
+		return ""
+	}
+	b, _ := io.ReadAll(cmd.Stdin)
+	return string(b)
+}
+
+func TestDockerCommand_WithStdin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("logs in with the password read from stdin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var stdin string
+		m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", "mockURI"}, gomock.Any()).
+			Do(func(_ string, _ []string, opts ...exec.CmdOption) {
+				stdin = stdinOf(opts)
+			}).Return(nil)
+
+		err := DockerCmdClient{runner: m}.WithStdin(strings.NewReader("secret")).Login("mockURI", "AWS", "")
+
+		require.NoError(t, err)
+		require.Equal(t, "secret", stdin)
+	})
+	t.Run("prefers the password argument to stdin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var stdin string
+		m.EXPECT().Run("docker", []string{"login", "-u", "AWS", "--password-stdin", "mockURI"}, gomock.Any()).
+			Do(func(_ string, _ []string, opts ...exec.CmdOption) {
+				stdin = stdinOf(opts)
+			}).Return(nil)
+
+		err := DockerCmdClient{runner: m}.WithStdin(strings.NewReader("secret")).Login("mockURI", "AWS", "password")
+
+		require.NoError(t, err)
+		require.Equal(t, "password", stdin)
+	})
+	t.Run("keeps the stdin of a container open", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var stdin string
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"run", "--interactive", "--name", "web", "mockURI:latest", "cat"}, gomock.Any()).
+			Do(readStdin(&stdin)).Return(nil)
+
+		err := DockerCmdClient{runner: m}.WithStdin(strings.NewReader("hello")).Run(ctx, &RunOptions{
+			ImageURI:      "mockURI:latest",
+			ContainerName: "web",
+			Command:       []string{"cat"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "hello", stdin)
+	})
+	t.Run("pipes stdin to a command run in a container", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		var stdin string
+		m.EXPECT().RunWithContext(ctx, "docker", []string{"exec", "--interactive", "db", "psql"}, gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(readStdin(&stdin)).Do(mockStdout("CREATE TABLE\n")).Return(nil)
+
+		got, err := DockerCmdClient{runner: m}.WithStdin(strings.NewReader("CREATE TABLE t ();")).ExecCapture(ctx, "db", []string{"psql"})
+
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE\n", got.Stdout)
+		require.Equal(t, "CREATE TABLE t ();", stdin)
+	})
+}
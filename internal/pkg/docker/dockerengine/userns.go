@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+)
+
+const (
+	// defaultRemapUser is the user whose subordinate IDs the daemon maps containers to with `userns-remap: default`.
+	defaultRemapUser = "dockremap"
+	subuidPath       = "/etc/subuid"
+	subgidPath       = "/etc/subgid"
+)
+
+// UserNamespace describes how the daemon maps the users of containers to the users of the host,
+// which determines who owns the files that containers write to bind-mounted directories.
+type UserNamespace struct {
+	Remapped bool // True if the daemon runs with userns-remap: root in a container is an unprivileged subordinate user of the host.
+	Rootless bool // True if the daemon runs in rootless mode: root in a container is the user running the daemon.
+	RootUID  int  // Host uid that root in a container is remapped to, or -1 if unknown.
+	RootGID  int  // Host gid that root in a container is remapped to, or -1 if unknown.
+}
+
+// UserNamespace probes the daemon for user namespace remapping and rootless mode.
+func (c DockerCmdClient) UserNamespace(ctx context.Context) (*UserNamespace, error) {
+	buf := &bytes.Buffer{}
+	if err := c.runner.RunWithContext(ctx, "docker", []string{"info", "--format", "{{json .SecurityOptions}}"}, exec.Stdout(buf)); err != nil {
+		return nil, fmt.Errorf("get docker security options: %w", err)
+	}
+	var opts []string
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &opts); err != nil {
+		return nil, fmt.Errorf("unmarshal docker security options: %w", err)
+	}
+	ns := &UserNamespace{
+		RootUID: -1,
+		RootGID: -1,
+	}
+	for _, opt := range opts {
+		// Each option looks like "name=seccomp,profile=builtin".
+		name, _, _ := strings.Cut(strings.TrimPrefix(opt, "name="), ",")
+		switch name {
+		case "userns":
+			ns.Remapped = true
+		case "rootless":
+			ns.Rootless = true
+		}
+	}
+	if ns.Remapped {
+		ns.RootUID = subordinateID(subuidPath, defaultRemapUser)
+		ns.RootGID = subordinateID(subgidPath, defaultRemapUser)
+	}
+	return ns, nil
+}
+
+// MountGuidance returns advice for each bind mount of opts whose files may not be accessible from the container,
+// or owned by the host user once written by the container, because of how the daemon maps users.
+func (ns *UserNamespace) MountGuidance(opts *RunOptions) []string {
+	if opts.UsernsHost || (!ns.Remapped && !ns.Rootless) {
+		return nil
+	}
+	var hostPaths []string
+	for hostPath := range opts.Mounts {
+		hostPaths = append(hostPaths, hostPath)
+	}
+	sort.Strings(hostPaths)
+	var guidance []string
+	for _, hostPath := range hostPaths {
+		switch {
+		case ns.Rootless:
+			guidance = append(guidance, fmt.Sprintf("The docker daemon runs in rootless mode: only root in the container maps to your user, "+
+				"so other users of the container may not be able to write to %s and the files they create won't be owned by you. "+
+				"Run the container as root, or run `docker run --rm -v %s:/mnt %s chown -R 0:0 /mnt` to reclaim the files.",
+				hostPath, hostPath, opts.ImageURI))
+		case ns.RootUID >= 0 && ns.RootGID >= 0:
+			guidance = append(guidance, fmt.Sprintf("The docker daemon remaps the users of containers: root in the container is uid %d on the host, "+
+				"so it may not be able to write to %s. Run `sudo chown -R %d:%d %s`, or opt in to run the container in the user namespace of the host.",
+				ns.RootUID, hostPath, ns.RootUID, ns.RootGID, hostPath))
+		default:
+			guidance = append(guidance, fmt.Sprintf("The docker daemon remaps the users of containers, so they may not be able to write to %s. "+
+				"Change the owner of %s to the subordinate IDs in %s and %s, or opt in to run the container in the user namespace of the host.",
+				hostPath, hostPath, subuidPath, subgidPath))
+		}
+	}
+	return guidance
+}
+
+// warnMountOwnership warns about the bind mounts of opts that are subject to user namespace remapping.
+// The check is best-effort: if the daemon can't be probed, the container is left to report the problem.
+func (c DockerCmdClient) warnMountOwnership(ctx context.Context, opts *RunOptions) {
+	if len(opts.Mounts) == 0 || opts.UsernsHost {
+		return
+	}
+	ns, err := c.UserNamespace(ctx)
+	if err != nil {
+		return
+	}
+	for _, guidance := range ns.MountGuidance(opts) {
+		log.Warningf("%s\n", guidance)
+	}
+}
+
+// subordinateID returns the first subordinate ID of the user in a file formatted like /etc/subuid, or -1 if it can't be found.
+func subordinateID(path, user string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer f.Close()
+	return parseSubordinateID(f, user)
+}
+
+func parseSubordinateID(r io.Reader, user string) int {
+	// Each line looks like "dockremap:231072:65536".
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ":")
+		if len(fields) != 3 || fields[0] != user {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return -1
+		}
+		return id
+	}
+	return -1
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_UserNamespace(t *testing.T) {
+	ctx := context.Background()
+	infoArgs := []string{"info", "--format", "{{json .SecurityOptions}}"}
+	tests := map[string]struct {
+		setupMocks func(m *MockCmd)
+
+		wanted    *UserNamespace
+		wantedErr string
+	}{
+		"detects rootless mode": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`["name=seccomp,profile=builtin","name=rootless","name=cgroupns"]`)).Return(nil)
+			},
+			wanted: &UserNamespace{Rootless: true, RootUID: -1, RootGID: -1},
+		},
+		"detects a daemon without user namespaces": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).
+					Do(mockStdout(`["name=apparmor","name=seccomp,profile=builtin"]`)).Return(nil)
+			},
+			wanted: &UserNamespace{RootUID: -1, RootGID: -1},
+		},
+		"wraps errors from docker info": {
+			setupMocks: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "docker", infoArgs, gomock.Any()).Return(errors.New("some error"))
+			},
+			wantedErr: "get docker security options: some error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMocks(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			got, err := s.UserNamespace(ctx)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestUserNamespace_MountGuidance(t *testing.T) {
+	opts := &RunOptions{
+		ImageURI: "mockImage",
+		Mounts:   map[string]string{"/home/user/app": "/app"},
+	}
+	tests := map[string]struct {
+		ns         UserNamespace
+		usernsHost bool
+
+		wanted []string
+	}{
+		"no guidance without user namespaces": {
+			ns: UserNamespace{RootUID: -1, RootGID: -1},
+		},
+		"no guidance if the container runs in the user namespace of the host": {
+			ns:         UserNamespace{Remapped: true, RootUID: 231072, RootGID: 231072},
+			usernsHost: true,
+		},
+		"suggests to chown to the remapped root": {
+			ns: UserNamespace{Remapped: true, RootUID: 231072, RootGID: 231073},
+			wanted: []string{"The docker daemon remaps the users of containers: root in the container is uid 231072 on the host, " +
+				"so it may not be able to write to /home/user/app. Run `sudo chown -R 231072:231073 /home/user/app`, " +
+				"or opt in to run the container in the user namespace of the host."},
+		},
+		"points to the subordinate IDs if the remapped root is unknown": {
+			ns: UserNamespace{Remapped: true, RootUID: -1, RootGID: -1},
+			wanted: []string{"The docker daemon remaps the users of containers, so they may not be able to write to /home/user/app. " +
+				"Change the owner of /home/user/app to the subordinate IDs in /etc/subuid and /etc/subgid, " +
+				"or opt in to run the container in the user namespace of the host."},
+		},
+		"suggests to reclaim files in rootless mode": {
+			ns: UserNamespace{Rootless: true, RootUID: -1, RootGID: -1},
+			wanted: []string{"The docker daemon runs in rootless mode: only root in the container maps to your user, " +
+				"so other users of the container may not be able to write to /home/user/app and the files they create won't be owned by you. " +
+				"Run the container as root, or run `docker run --rm -v /home/user/app:/mnt mockImage chown -R 0:0 /mnt` to reclaim the files."},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			in := *opts
+			in.UsernsHost = tc.usernsHost
+
+			require.Equal(t, tc.wanted, tc.ns.MountGuidance(&in))
+		})
+	}
+}
+
+func TestParseSubordinateID(t *testing.T) {
+	subuid := "ubuntu:100000:65536\ndockremap:231072:65536\n"
+
+	require.Equal(t, 231072, parseSubordinateID(strings.NewReader(subuid), "dockremap"))
+	require.Equal(t, -1, parseSubordinateID(strings.NewReader(subuid), "nobody"))
+}
@@ -0,0 +1,154 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+)
+
+var errKeylessPolicyWithoutIdentity = errors.New("a keyless cosign trust policy requires a certificate identity and a certificate OIDC issuer")
+
+// TrustPolicy holds the signatures that images must carry to be trusted.
+type TrustPolicy struct {
+	Tool string // Required. Either SigningToolCosign or SigningToolNotation.
+
+	// Cosign only. URI of the public key, like "awskms:///arn:aws:kms:..." or a path to a PEM file.
+	// If it's empty, keyless signatures are verified with CertificateIdentity and CertificateOIDCIssuer.
+	Key                   string
+	CertificateIdentity   string // Regular expression matching the identity of keyless signers, for example "^https://github.com/org/".
+	CertificateOIDCIssuer string // Issuer of the OIDC tokens of keyless signers, for example "https://token.actions.githubusercontent.com".
+
+	// Notation verifies images against the trust policy and the trust stores of its own configuration.
+}
+
+// ErrSignatureVerificationFailed means an image doesn't have a signature trusted by the policy.
+type ErrSignatureVerificationFailed struct {
+	Image string
+	Tool  string
+
+	err error
+}
+
+func (e *ErrSignatureVerificationFailed) Error() string {
+	return fmt.Sprintf("verify the %s signature of image %s: %v", e.Tool, e.Image, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrSignatureVerificationFailed) Unwrap() error {
+	return e.err
+}
+
+// RecommendActions returns recommended actions to be taken after the error.
+func (e *ErrSignatureVerificationFailed) RecommendActions() string {
+	return fmt.Sprintf("Make sure that image %s is signed with a key or an identity trusted by the trust policy, or use a signed image.", e.Image)
+}
+
+// WithSignatureVerification returns a copy of the client that verifies the signature of images with policy
+// before pulling them or running containers from them.
+func (c DockerCmdClient) WithSignatureVerification(policy TrustPolicy) DockerCmdClient {
+	c.trustPolicy = &policy
+	return c
+}
+
+// VerifySignature returns ErrSignatureVerificationFailed if the image doesn't have a signature trusted by the policy.
+func (c DockerCmdClient) VerifySignature(ctx context.Context, imageRef string, policy TrustPolicy) error {
+	var args []string
+	switch policy.Tool {
+	case SigningToolCosign:
+		args = []string{"verify"}
+		switch {
+		case policy.Key != "":
+			args = append(args, "--key", policy.Key)
+		case policy.CertificateIdentity == "" || policy.CertificateOIDCIssuer == "":
+			// An empty identity or issuer would trust the signatures of anyone with a Sigstore certificate.
+			return errKeylessPolicyWithoutIdentity
+		default:
+			args = append(args, "--certificate-identity-regexp", policy.CertificateIdentity, "--certificate-oidc-issuer", policy.CertificateOIDCIssuer)
+		}
+	case SigningToolNotation:
+		args = []string{"verify"}
+	default:
+		return fmt.Errorf("unsupported signing tool %q", policy.Tool)
+	}
+	args = append(args, imageRef)
+	tail := &tailBuffer{size: buildOutputTailSize}
+	if err := c.runner.RunWithContext(ctx, policy.Tool, args, exec.Stdout(io.Discard), exec.Stderr(tail)); err != nil {
+		if out := strings.TrimSpace(tail.String()); out != "" {
+			err = fmt.Errorf("%w: %s", err, out)
+		}
+		return &ErrSignatureVerificationFailed{
+			Image: imageRef,
+			Tool:  policy.Tool,
+			err:   err,
+		}
+	}
+	return nil
+}
+
+// pinTrusted returns the reference by digest of the image, like "<repo>@sha256:<hex>", after verifying the signature
+// of the image at that digest if the client has a trust policy. Pulling or running the returned reference guarantees that
+// the verified content is used even if the tag moves in the meantime.
+// If the client has no trust policy, the reference is returned unchanged.
+func (c DockerCmdClient) pinTrusted(ctx context.Context, imageRef string) (string, error) {
+	if c.trustPolicy == nil {
+		return imageRef, nil
+	}
+	pinned := imageRef
+	if !strings.Contains(imageRef, "@") {
+		digest, err := c.RemoteDigest(ctx, imageRef)
+		if err != nil {
+			return "", fmt.Errorf("resolve the digest of image %s to verify: %w", imageRef, err)
+		}
+		pinned = imageRepository(imageRef) + "@" + digest
+	}
+	if err := c.VerifySignature(ctx, pinned, *c.trustPolicy); err != nil {
+		return "", err
+	}
+	return pinned, nil
+}
+
+// trustedRunOptions returns a copy of the options that runs the image by the digest verified with pinTrusted.
+// Every path that starts a container goes through it so that the trust policy can't be bypassed.
+func (c DockerCmdClient) trustedRunOptions(ctx context.Context, opts *RunOptions) (*RunOptions, error) {
+	image, err := c.pinTrusted(ctx, opts.ImageURI)
+	if err != nil {
+		return nil, err
+	}
+	trusted := *opts
+	trusted.ImageURI = image
+	return &trusted, nil
+}
+
+// trustedTask returns a copy of the task whose containers run the images by the digests verified with pinTrusted.
+func (c DockerCmdClient) trustedTask(ctx context.Context, spec *TaskSpec) (*TaskSpec, error) {
+	if c.trustPolicy == nil {
+		return spec, nil
+	}
+	trusted := *spec
+	trusted.Containers = make([]*RunOptions, len(spec.Containers))
+	for i, container := range spec.Containers {
+		opts, err := c.trustedRunOptions(ctx, container)
+		if err != nil {
+			return nil, err
+		}
+		trusted.Containers[i] = opts
+	}
+	return &trusted, nil
+}
+
+// imageRepository returns the repository of an image reference without its tag or digest,
+// like "public.ecr.aws/nginx/nginx" for "public.ecr.aws/nginx/nginx:1.25".
+func imageRepository(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i]
+	}
+	return ref
+}
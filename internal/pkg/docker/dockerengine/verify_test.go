@@ -0,0 +1,238 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerCommand_VerifySignature(t *testing.T) {
+	ctx := context.Background()
+	const ref = "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/svc:v1"
+	tests := map[string]struct {
+		policy    TrustPolicy
+		setupMock func(m *MockCmd)
+
+		wantedErr string
+	}{
+		"verifies with a cosign key": {
+			policy: TrustPolicy{Tool: SigningToolCosign, Key: "awskms:///alias/signing"},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "cosign", []string{"verify", "--key", "awskms:///alias/signing", ref}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"verifies keyless cosign signatures": {
+			policy: TrustPolicy{
+				Tool:                  SigningToolCosign,
+				CertificateIdentity:   "^https://github.com/org/",
+				CertificateOIDCIssuer: "https://token.actions.githubusercontent.com",
+			},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "cosign", []string{"verify", "--certificate-identity-regexp", "^https://github.com/org/", "--certificate-oidc-issuer", "https://token.actions.githubusercontent.com", ref}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"rejects keyless cosign policies without an identity": {
+			policy: TrustPolicy{
+				Tool:                  SigningToolCosign,
+				CertificateOIDCIssuer: "https://token.actions.githubusercontent.com",
+			},
+			setupMock: func(m *MockCmd) {},
+			wantedErr: "a keyless cosign trust policy requires a certificate identity and a certificate OIDC issuer",
+		},
+		"rejects keyless cosign policies without an issuer": {
+			policy: TrustPolicy{
+				Tool:                SigningToolCosign,
+				CertificateIdentity: "^https://github.com/org/",
+			},
+			setupMock: func(m *MockCmd) {},
+			wantedErr: "a keyless cosign trust policy requires a certificate identity and a certificate OIDC issuer",
+		},
+		"verifies with notation": {
+			policy: TrustPolicy{Tool: SigningToolNotation},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", ref}, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		"returns ErrSignatureVerificationFailed with the output of the tool": {
+			policy: TrustPolicy{Tool: SigningToolNotation},
+			setupMock: func(m *MockCmd) {
+				m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", ref}, gomock.Any(), gomock.Any()).
+					Do(mockStderr("Error: signature verification failed: no signature is associated with the image\n")).Return(errors.New("exit status 1"))
+			},
+			wantedErr: "verify the notation signature of image " + ref + ": exit status 1: Error: signature verification failed: no signature is associated with the image",
+		},
+		"returns an error for unsupported tools": {
+			policy:    TrustPolicy{Tool: "gpg"},
+			setupMock: func(m *MockCmd) {},
+			wantedErr: `unsupported signing tool "gpg"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := NewMockCmd(ctrl)
+			tc.setupMock(m)
+			s := DockerCmdClient{
+				runner: m,
+			}
+
+			err := s.VerifySignature(ctx, ref, tc.policy)
+
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDockerCommand_WithSignatureVerification(t *testing.T) {
+	ctx := context.Background()
+	const digest = "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+	digestArgs := []string{"buildx", "imagetools", "inspect", "--format", "{{json .Manifest}}", "nginx:1.25"}
+	manifest := fmt.Sprintf(`{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"%s","size":10229}`, digest)
+	policy := TrustPolicy{Tool: SigningToolNotation}
+
+	t.Run("doesn't pull untrusted images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1")),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		err := s.Pull(ctx, "nginx:1.25", PullOptions{})
+
+		var verifyErr *ErrSignatureVerificationFailed
+		require.ErrorAs(t, err, &verifyErr)
+	})
+	t.Run("pulls the verified digest from the mirror", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "docker", []string{"pull", "mirror.example.com/library/nginx@" + digest}, gomock.Any(), gomock.Any()).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "docker", []string{"tag", "mirror.example.com/library/nginx@" + digest, "nginx:1.25"}).Return(nil),
+		)
+		s := DockerCmdClient{
+			runner: m,
+			mirror: "mirror.example.com",
+		}.WithSignatureVerification(policy)
+
+		err := s.Pull(ctx, "nginx:1.25", PullOptions{})
+
+		require.NoError(t, err)
+	})
+	t.Run("runs containers from the verified digest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "docker", []string{"run", "--name", "web", "nginx@" + digest}).Return(nil),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		err := s.Run(ctx, &RunOptions{ImageURI: "nginx:1.25", ContainerName: "web"})
+
+		require.NoError(t, err)
+	})
+	t.Run("verifies images already pinned by digest without resolving them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "docker", []string{"run", "--rm", "--name", "job", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(nil),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		_, err := s.RunOnce(ctx, &RunOptions{ImageURI: "nginx@" + digest, ContainerName: "job"}, 0)
+
+		require.NoError(t, err)
+	})
+	t.Run("doesn't run one-off containers from untrusted images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1")),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		_, err := s.RunOnce(ctx, &RunOptions{ImageURI: "nginx:1.25"}, 0)
+
+		var verifyErr *ErrSignatureVerificationFailed
+		require.ErrorAs(t, err, &verifyErr)
+	})
+	t.Run("doesn't replace containers with untrusted images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1")),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		err := s.ReplaceContainer(ctx, "web-1", &RunOptions{ImageURI: "nginx:1.25", ContainerName: "web-2"}, &ReplaceOptions{
+			Network: "copilot",
+			Aliases: []string{"web"},
+		})
+
+		var verifyErr *ErrSignatureVerificationFailed
+		require.ErrorAs(t, err, &verifyErr)
+	})
+	t.Run("doesn't start tasks with untrusted images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		gomock.InOrder(
+			m.EXPECT().RunWithContext(ctx, "docker", digestArgs, gomock.Any()).Do(mockStdout(manifest)).Return(nil),
+			m.EXPECT().RunWithContext(ctx, "notation", []string{"verify", "nginx@" + digest}, gomock.Any(), gomock.Any()).Return(errors.New("exit status 1")),
+		)
+		s := DockerCmdClient{
+			runner: m,
+		}.WithSignatureVerification(policy)
+
+		err := s.ComposeUp(ctx, "web", &TaskSpec{
+			Containers: []*RunOptions{{ImageURI: "nginx:1.25", ContainerName: "web"}},
+		}, nil, io.Discard)
+
+		var verifyErr *ErrSignatureVerificationFailed
+		require.ErrorAs(t, err, &verifyErr)
+	})
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := map[string]string{
+		"nginx:1.25":                "nginx",
+		"nginx":                     "nginx",
+		"localhost:5000/app/svc:v1": "localhost:5000/app/svc",
+		"localhost:5000/app/svc":    "localhost:5000/app/svc",
+		"public.ecr.aws/nginx/nginx:1.25@sha256:4c0fdaa": "public.ecr.aws/nginx/nginx",
+	}
+	for ref, wanted := range tests {
+		t.Run(ref, func(t *testing.T) {
+			require.Equal(t, wanted, imageRepository(ref))
+		})
+	}
+}
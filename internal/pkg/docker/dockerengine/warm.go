@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// baseImage is an image that a Dockerfile builds from.
+type baseImage struct {
+	ref      string
+	platform string // Set if the FROM instruction pins the platform, for example "linux/amd64".
+}
+
+// WarmBaseImages pulls the base images of the Dockerfiles that are missing from the local image store in parallel,
+// so that it can overlap with other deployment work before the builds start.
+// Base images are read from the FROM instructions: previous build stages and scratch are skipped,
+// and variables are replaced with the defaults of the ARG instructions declared before the first FROM.
+// Images whose reference depends on build args without a default are left for the build to pull.
+// All the images are pulled like Pull even if some of them fail, and the errors are returned together.
+func (c DockerCmdClient) WarmBaseImages(ctx context.Context, dockerfiles []string) error {
+	var images []baseImage
+	seen := make(map[baseImage]bool)
+	for _, dockerfile := range dockerfiles {
+		refs, err := dockerfileBaseImages(dockerfile)
+		if err != nil {
+			return err
+		}
+		for _, img := range refs {
+			if !seen[img] {
+				seen[img] = true
+				images = append(images, img)
+			}
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].ref < images[j].ref || images[i].ref == images[j].ref && images[i].platform < images[j].platform
+	})
+
+	return pullInParallel(len(images), func(i int) error {
+		return c.warmBaseImage(ctx, images[i])
+	})
+}
+
+func (c DockerCmdClient) warmBaseImage(ctx context.Context, img baseImage) error {
+	// Images pinned to another platform than the local one can't be checked reliably, so they're always pulled.
+	if img.platform == "" {
+		if _, err := c.inspectLocalImage(ctx, img.ref); err == nil {
+			return nil
+		}
+	}
+	return c.Pull(ctx, img.ref, PullOptions{
+		Platform: img.platform,
+		Quiet:    true,
+	})
+}
+
+// dockerfileBaseImages returns the images that the stages of the Dockerfile build from.
+func dockerfileBaseImages(path string) ([]baseImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open Dockerfile: %w", err)
+	}
+	defer f.Close()
+	instrs, err := scanInstructions(f)
+	if err != nil {
+		return nil, fmt.Errorf("read Dockerfile %s: %w", path, err)
+	}
+	globalArgs := make(map[string]string)
+	stages := make(map[string]bool)
+	var images []baseImage
+	var sawFrom bool
+	for _, instr := range instrs {
+		switch instr.cmd {
+		case "ARG":
+			if sawFrom {
+				continue
+			}
+			// Only the ARGs declared before the first FROM are in the scope of FROM instructions.
+			for _, arg := range strings.Fields(instr.args) {
+				if name, value, ok := strings.Cut(arg, "="); ok {
+					globalArgs[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			sawFrom = true
+			img, stage, ok := parseBaseImage(instr.args, globalArgs)
+			if stage != "" {
+				stages[stage] = true
+			}
+			if !ok || img.ref == "scratch" || stages[strings.ToLower(img.ref)] && strings.ToLower(img.ref) != stage {
+				continue
+			}
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+// parseBaseImage returns the base image and the lowercase stage name from the arguments of a FROM instruction
+// like "--platform=$BUILDPLATFORM golang:${GO_VERSION} AS build". It returns false if the image
+// references variables that can't be resolved. Platforms that can't be resolved, like $BUILDPLATFORM,
+// default to the platform of the daemon.
+func parseBaseImage(args string, vars map[string]string) (img baseImage, stage string, ok bool) {
+	ref, stage := parseFromArgs(args)
+	if ref == "" {
+		return baseImage{}, "", false
+	}
+	img.ref, ok = expandArgs(ref, vars)
+	for _, field := range strings.Fields(args) {
+		if p, found := strings.CutPrefix(field, "--platform="); found {
+			if platform, resolved := expandArgs(p, vars); resolved {
+				img.platform = platform
+			}
+		}
+	}
+	return img, stage, ok
+}
+
+// expandArgs replaces the variables in s with their values in vars.
+// It returns false if one of the variables doesn't have a value.
+func expandArgs(s string, vars map[string]string) (string, bool) {
+	resolved := true
+	expanded := os.Expand(s, func(name string) string {
+		v := vars[name]
+		if v == "" {
+			resolved = false
+		}
+		return v
+	})
+	return expanded, resolved
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerfileBaseImages(t *testing.T) {
+	tests := map[string]struct {
+		dockerfile string
+
+		wanted []baseImage
+	}{
+		"skips build stages and scratch": {
+			dockerfile: `FROM golang:1.20 AS build
+RUN go build -o /app
+FROM build AS test
+FROM scratch
+COPY --from=build /app /app`,
+			wanted: []baseImage{{ref: "golang:1.20"}},
+		},
+		"replaces global args with their defaults": {
+			dockerfile: `ARG GO_VERSION=1.20
+ARG DISTROLESS
+FROM --platform=linux/arm64 golang:${GO_VERSION} AS build
+ARG NODE_VERSION=20
+FROM node:$NODE_VERSION
+FROM gcr.io/distroless/${DISTROLESS}`,
+			wanted: []baseImage{{ref: "golang:1.20", platform: "linux/arm64"}},
+		},
+		"ignores platforms that can't be resolved": {
+			dockerfile: `FROM --platform=$BUILDPLATFORM public.ecr.aws/docker/library/alpine:3.18`,
+			wanted:     []baseImage{{ref: "public.ecr.aws/docker/library/alpine:3.18"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			require.NoError(t, os.WriteFile(path, []byte(tc.dockerfile), 0644))
+
+			got, err := dockerfileBaseImages(path)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wanted, got)
+		})
+	}
+}
+
+func TestDockerCommand_WarmBaseImages(t *testing.T) {
+	dir := t.TempDir()
+	web, api := filepath.Join(dir, "web.Dockerfile"), filepath.Join(dir, "api.Dockerfile")
+	require.NoError(t, os.WriteFile(web, []byte("FROM nginx:1.25\nFROM --platform=linux/arm64 alpine:3.18"), 0644))
+	require.NoError(t, os.WriteFile(api, []byte("FROM golang:1.20 AS build\nFROM nginx:1.25"), 0644))
+
+	t.Run("pulls each missing base image once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "nginx:1.25"}, gomock.Any()).
+			Do(mockStdout(`[{"Id":"sha256:abc"}]`)).Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "golang:1.20"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "golang:1.20"}, gomock.Any(), gomock.Any()).
+			Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--platform", "linux/arm64", "--quiet", "alpine:3.18"}, gomock.Any(), gomock.Any()).
+			Return(nil)
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.WarmBaseImages(context.Background(), []string{web, api})
+
+		require.NoError(t, err)
+	})
+	t.Run("pulls Docker Hub base images from the mirror", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"image", "inspect", "nginx:1.25"}, gomock.Any()).
+			Return(errors.New("no such image"))
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"pull", "--quiet", "mirror.example.com/library/nginx:1.25"}, gomock.Any(), gomock.Any()).
+			Return(nil)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", []string{"tag", "mirror.example.com/library/nginx:1.25", "nginx:1.25"}).
+			Return(nil)
+		c := DockerCmdClient{
+			runner: m,
+			mirror: "mirror.example.com",
+		}
+
+		dockerfile := filepath.Join(t.TempDir(), "Dockerfile")
+		require.NoError(t, os.WriteFile(dockerfile, []byte("FROM nginx:1.25"), 0644))
+
+		err := c.WarmBaseImages(context.Background(), []string{dockerfile})
+
+		require.NoError(t, err)
+	})
+	t.Run("pulls the other images if one fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := NewMockCmd(ctrl)
+		m.EXPECT().RunWithContext(gomock.Any(), "docker", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, args []string, _ ...exec.CmdOption) error {
+				if args[0] == "pull" && args[len(args)-1] == "golang:1.20" {
+					return errors.New("some error")
+				}
+				if args[0] == "image" {
+					return errors.New("no such image")
+				}
+				return nil
+			}).Times(5)
+		c := DockerCmdClient{
+			runner: m,
+		}
+
+		err := c.WarmBaseImages(context.Background(), []string{web, api})
+
+		require.EqualError(t, err, "docker pull golang:1.20: some error")
+	})
+	t.Run("errors if a Dockerfile can't be read", func(t *testing.T) {
+		c := DockerCmdClient{}
+
+		err := c.WarmBaseImages(context.Background(), []string{filepath.Join(dir, "missing")})
+
+		require.ErrorContains(t, err, "open Dockerfile")
+	})
+}
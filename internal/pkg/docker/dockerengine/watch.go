@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchPollInterval = 500 * time.Millisecond
+	defaultWatchDebounce     = 300 * time.Millisecond
+)
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+type buildWatcher struct {
+	paths        []string
+	build        func(ctx context.Context) error
+	onBuilt      func(err error)
+	pollInterval time.Duration
+	debounce     time.Duration
+}
+
+// WatchAndBuild builds the image, then rebuilds it every time files under watchPaths change until ctx is canceled.
+// Changes are debounced so that saving many files at once triggers a single build, and a build still in progress
+// when new changes arrive is canceled in favor of a new one. Files excluded by the .dockerignore file of a watched
+// directory, as well as .git directories, are ignored.
+// onBuilt is called with the result of every build that ran to completion.
+func (c DockerCmdClient) WatchAndBuild(ctx context.Context, in *BuildArguments, watchPaths []string, w io.Writer, onBuilt func(err error)) error {
+	bw := &buildWatcher{
+		paths: watchPaths,
+		build: func(ctx context.Context) error {
+			return c.Build(ctx, in, w)
+		},
+		onBuilt:      onBuilt,
+		pollInterval: defaultWatchPollInterval,
+		debounce:     defaultWatchDebounce,
+	}
+	return bw.watch(ctx)
+}
+
+func (bw *buildWatcher) watch(ctx context.Context) error {
+	last, err := bw.snapshot()
+	if err != nil {
+		return err
+	}
+	var (
+		wg     sync.WaitGroup
+		cancel context.CancelFunc = func() {}
+	)
+	start := func() {
+		cancel() // Cancel the build in progress, if any.
+		var buildCtx context.Context
+		buildCtx, cancel = context.WithCancel(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := bw.build(buildCtx)
+			if buildCtx.Err() != nil {
+				// The build was superseded by a newer one or the watch stopped.
+				return
+			}
+			bw.onBuilt(err)
+		}()
+	}
+
+	start()
+	ticker := time.NewTicker(bw.pollInterval)
+	defer ticker.Stop()
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+			curr, err := bw.snapshot()
+			if err != nil {
+				// Files can be removed while we walk the directories, try again on the next tick.
+				continue
+			}
+			if !sameSnapshot(last, curr) {
+				last = curr
+				debounce = time.After(bw.debounce)
+			}
+		case <-debounce:
+			debounce = nil
+			start()
+		}
+	}
+}
+
+// snapshot returns the version of every file under the watched paths that isn't ignored.
+func (bw *buildWatcher) snapshot() (map[string]fileStamp, error) {
+	files := make(map[string]fileStamp)
+	for _, root := range bw.paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("watch %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			files[root] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			continue
+		}
+		matcher, err := dockerignoreMatcher(filepath.Join(root, dockerignoreFile), ".git")
+		if err != nil {
+			return nil, err
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil || rel == "." {
+				return err
+			}
+			ignored, err := matcher.Matches(rel)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				if d.IsDir() && !matcher.Exclusions() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("watch %s: %w", root, err)
+		}
+	}
+	return files, nil
+}
+
+func sameSnapshot(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		other, ok := b[path]
+		if !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dockerengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWatcher_watch(t *testing.T) {
+	newDir := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, dockerignoreFile), []byte("*.log\n"), 0644))
+		return dir
+	}
+	waitFor := func(t *testing.T, ch <-chan error) error {
+		select {
+		case err := <-ch:
+			return err
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for a build")
+			return nil
+		}
+	}
+
+	t.Run("rebuilds when a watched file changes but not when an ignored file changes", func(t *testing.T) {
+		dir := newDir(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		built := make(chan error, 10)
+		var builds int
+		bw := &buildWatcher{
+			paths: []string{dir},
+			build: func(ctx context.Context) error {
+				builds++
+				return nil
+			},
+			onBuilt: func(err error) {
+				built <- err
+			},
+			pollInterval: 5 * time.Millisecond,
+			debounce:     10 * time.Millisecond,
+		}
+		done := make(chan error)
+		go func() {
+			done <- bw.watch(ctx)
+		}()
+
+		require.NoError(t, waitFor(t, built)) // Initial build.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("ignored"), 0644))
+		time.Sleep(50 * time.Millisecond)
+		require.Empty(t, built)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644))
+		require.NoError(t, waitFor(t, built))
+
+		cancel()
+		require.NoError(t, <-done)
+		require.Equal(t, 2, builds)
+	})
+	t.Run("cancels the build in progress when files change", func(t *testing.T) {
+		dir := newDir(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		started := make(chan struct{}, 10)
+		canceled := make(chan error, 10)
+		built := make(chan error, 10)
+		var builds int
+		bw := &buildWatcher{
+			paths: []string{dir},
+			build: func(ctx context.Context) error {
+				builds++
+				started <- struct{}{}
+				if builds > 1 {
+					return nil
+				}
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return ctx.Err()
+			},
+			onBuilt: func(err error) {
+				built <- err
+			},
+			pollInterval: 5 * time.Millisecond,
+			debounce:     10 * time.Millisecond,
+		}
+		done := make(chan error)
+		go func() {
+			done <- bw.watch(ctx)
+		}()
+
+		<-started
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644))
+		require.ErrorIs(t, waitFor(t, canceled), context.Canceled)
+		require.NoError(t, waitFor(t, built))
+
+		cancel()
+		require.NoError(t, <-done)
+		require.Empty(t, built, "the canceled build should not be reported")
+	})
+}
@@ -69,6 +69,7 @@ type HealthCheck struct {
 type Dockerfile struct {
 	exposedPorts []Port
 	healthCheck  *HealthCheck
+	stages       []string
 	parsed       bool
 	path         string
 
@@ -121,6 +122,17 @@ func (df *Dockerfile) GetHealthCheck() (*HealthCheck, error) {
 	return df.healthCheck, nil
 }
 
+// GetStages returns the names of the build stages declared with `FROM <image> AS <name>` in the Dockerfile, in order.
+// Stage names are case-insensitive, so they are returned in lowercase.
+func (df *Dockerfile) GetStages() ([]string, error) {
+	if !df.parsed {
+		if err := df.parse(); err != nil {
+			return nil, err
+		}
+	}
+	return df.stages, nil
+}
+
 // parse takes a Dockerfile and fills in struct members based on methods like parseExpose and parseHealthcheck.
 func (df *Dockerfile) parse() error {
 	if df.parsed {
@@ -146,6 +158,7 @@ func (df *Dockerfile) parse() error {
 
 	df.exposedPorts = parsedDockerfile.exposedPorts
 	df.healthCheck = parsedDockerfile.healthCheck
+	df.stages = parsedDockerfile.stages
 	df.parsed = true
 	return nil
 }
@@ -172,10 +185,30 @@ func parse(name, content string) (*Dockerfile, error) {
 				return nil, err
 			}
 			df.healthCheck = hc
+		case instrFrom:
+			if stage := parseStageName(instr.args); stage != "" {
+				df.stages = append(df.stages, stage)
+			}
 		}
 	}
 }
 
+// parseStageName returns the name of the stage from the arguments of a FROM instruction
+// like "--platform=$BUILDPLATFORM golang:1.20 AS build", or an empty string if the stage is unnamed.
+func parseStageName(args string) string {
+	var fields []string
+	for _, field := range strings.Fields(args) {
+		if strings.HasPrefix(field, "--") {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) != 3 || !strings.EqualFold(fields[1], "as") {
+		return ""
+	}
+	return strings.ToLower(fields[2])
+}
+
 func parseExpose(line string) []Port {
 	// group 0: whole match
 	// group 1: port
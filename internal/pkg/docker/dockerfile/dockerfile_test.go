@@ -277,6 +277,62 @@ HEALTHCHECK   CMD     ["a",    "b"]
 	}
 }
 
+func TestDockerfile_GetStages(t *testing.T) {
+	testCases := map[string]struct {
+		dockerfile   []byte
+		wantedStages []string
+	}{
+		"returns nothing for a single unnamed stage": {
+			dockerfile: []byte(`FROM nginx`),
+		},
+		"returns the named stages in order": {
+			dockerfile: []byte(`
+FROM --platform=$BUILDPLATFORM golang:1.20 AS Build
+RUN go build -o /app .
+
+from alpine as test
+COPY --from=build /app /app
+
+FROM scratch
+COPY --from=build /app /app
+`),
+			wantedStages: []string{"build", "test"},
+		},
+		"handles FROM instructions that span multiple lines": {
+			dockerfile: []byte(`
+FROM public.ecr.aws/docker/library/node:18 \
+	AS deps
+`),
+			wantedStages: []string{"deps"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			require.NoError(t, fs.WriteFile("./Dockerfile", tc.dockerfile, 0644))
+
+			// Ensure the stages match the ones parsed by Docker.
+			ast, err := parser.Parse(bytes.NewReader(tc.dockerfile))
+			require.NoError(t, err)
+			stages, _, err := instructions.Parse(ast.AST)
+			require.NoError(t, err)
+			var dockerStages []string
+			for _, stage := range stages {
+				if stage.Name != "" {
+					dockerStages = append(dockerStages, stage.Name)
+				}
+			}
+
+			got, err := New(fs, "./Dockerfile").GetStages()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedStages, got)
+			require.Equal(t, dockerStages, got)
+		})
+	}
+}
+
 func stringifyPorts(ports []Port) []string {
 	var arr []string
 	for _, p := range ports {
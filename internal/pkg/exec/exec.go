@@ -71,6 +71,17 @@ func Stderr(writer io.Writer) CmdOption {
 	}
 }
 
+// Env appends environment variables, in the form "key=value", to the internal *exec.Cmd's environment.
+// The environment defaults to the one of the current process.
+func Env(env ...string) CmdOption {
+	return func(c *exec.Cmd) {
+		if c.Env == nil {
+			c.Env = os.Environ()
+		}
+		c.Env = append(c.Env, env...)
+	}
+}
+
 // Run starts the named command and waits until it finishes.
 func (c *Cmd) Run(name string, args []string, opts ...CmdOption) error {
 	cmd := c.command(context.Background(), name, args, opts...)
@@ -81,5 +92,5 @@ func (c *Cmd) Run(name string, args []string, opts ...CmdOption) error {
 // Command execution process will be killed if the context becomes done before the command completes on its own.
 func (c *Cmd) RunWithContext(ctx context.Context, name string, args []string, opts ...CmdOption) error {
 	cmd := c.command(ctx, name, args, opts...)
-	return cmd.Run()
+	return run(cmd)
 }
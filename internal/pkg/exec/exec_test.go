@@ -5,6 +5,7 @@ package exec
 
 import (
 	"context"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -58,3 +59,13 @@ func TestCmd_RunWithContext(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestEnv(t *testing.T) {
+	t.Setenv("COPILOT_TEST_VAR", "inherited")
+	cmd := exec.Command("env")
+
+	Env("DOCKER_CONFIG=/tmp/config")(cmd)
+
+	require.Contains(t, cmd.Env, "COPILOT_TEST_VAR=inherited")
+	require.Equal(t, "DOCKER_CONFIG=/tmp/config", cmd.Env[len(cmd.Env)-1])
+}
@@ -0,0 +1,82 @@
+//go:build !windows
+
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package exec
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// KillProcessGroup runs the command in its own process group, so that the processes it spawns, like the BuildKit session
+// of `docker buildx build`, are stopped with it instead of being orphaned.
+// When the context is done, the group is interrupted like with Ctrl-C so that the processes can clean up,
+// and killed if it's still running after grace. Wait returns once the command exited, or at the latest after grace.
+// Commands that read from a terminal, like `docker run -it`, stay in the foreground process group of the terminal,
+// since they would be stopped with SIGTTIN otherwise: they receive Ctrl-C from the terminal, and only they are killed.
+// The option must come after the Stdin option of the command.
+func KillProcessGroup(grace time.Duration) CmdOption {
+	return func(c *exec.Cmd) {
+		c.WaitDelay = grace
+		if isTerminal(c.Stdin) {
+			return
+		}
+		c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		c.Cancel = func() error {
+			pgid := -c.Process.Pid
+			if err := syscall.Kill(pgid, syscall.SIGINT); err != nil {
+				if errors.Is(err, syscall.ESRCH) {
+					return os.ErrProcessDone
+				}
+				return err
+			}
+			time.AfterFunc(grace, func() {
+				_ = syscall.Kill(pgid, syscall.SIGKILL)
+			})
+			return nil
+		}
+	}
+}
+
+// isTerminal returns true if r is a terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// run runs the command. If it runs in its own process group, the interrupts received by copilot are forwarded
+// to the group while it runs, since the group is not in the foreground of the terminal and doesn't receive them.
+func run(cmd cmdRunner) error {
+	c, ok := cmd.(*exec.Cmd)
+	if !ok || c.SysProcAttr == nil || !c.SysProcAttr.Setpgid {
+		return cmd.Run()
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+	if err := c.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-sig:
+				_ = syscall.Kill(-c.Process.Pid, syscall.SIGINT)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return c.Wait()
+}
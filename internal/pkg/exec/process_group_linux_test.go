@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+// openPTY returns the terminal side of a new pseudo-terminal.
+func openPTY(t *testing.T) *os.File {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("open /dev/ptmx: %v", err)
+	}
+	t.Cleanup(func() { ptmx.Close() })
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, ptmx.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Skipf("unlock pseudo-terminal: %v", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, ptmx.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Skipf("get pseudo-terminal number: %v", errno)
+	}
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("open pseudo-terminal: %v", err)
+	}
+	t.Cleanup(func() { tty.Close() })
+	return tty
+}
+
+func TestKillProcessGroup_Terminal(t *testing.T) {
+	t.Run("runs the command in its own process group if it doesn't read from a terminal", func(t *testing.T) {
+		cmd := exec.Command("true")
+		Stdin(strings.NewReader("input"))(cmd)
+
+		KillProcessGroup(time.Second)(cmd)
+
+		require.True(t, cmd.SysProcAttr.Setpgid)
+		require.NotNil(t, cmd.Cancel)
+	})
+	t.Run("keeps interactive commands in the foreground process group of the terminal", func(t *testing.T) {
+		cmd := exec.Command("true")
+		Stdin(openPTY(t))(cmd)
+
+		KillProcessGroup(time.Second)(cmd)
+
+		require.Nil(t, cmd.SysProcAttr, "the command would be stopped with SIGTTIN when it reads from the terminal")
+		require.Nil(t, cmd.Cancel)
+		require.Equal(t, time.Second, cmd.WaitDelay)
+	})
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmd_RunWithContext_KillProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	pid := &bytes.Buffer{}
+
+	// Background jobs of non-interactive shells ignore SIGINT, so the child has to be killed after the grace period.
+	err := NewCmd().RunWithContext(ctx, "sh", []string{"-c", "sleep 30 & echo $!; wait"}, Stdout(pid), KillProcessGroup(100*time.Millisecond))
+
+	require.Error(t, err)
+	child, convErr := strconv.Atoi(strings.TrimSpace(pid.String()))
+	require.NoError(t, convErr)
+	require.Eventually(t, func() bool {
+		// The child may linger as a zombie until it's reaped by init.
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", child))
+		return syscall.Kill(child, 0) == syscall.ESRCH || err == nil && strings.Contains(string(stat), ") Z ")
+	}, time.Second, 10*time.Millisecond, "the child of the command should be killed with it")
+}
@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package exec
+
+import (
+	"os/exec"
+	"time"
+)
+
+// KillProcessGroup bounds the time that Wait waits for the command once the context is done.
+// Processes on Windows don't have process groups, so only the command is killed.
+func KillProcessGroup(grace time.Duration) CmdOption {
+	return func(c *exec.Cmd) {
+		c.WaitDelay = grace
+	}
+}
+
+// run runs the command.
+func run(cmd cmdRunner) error {
+	return cmd.Run()
+}
@@ -50,20 +50,6 @@ func (mr *MockContainerLoginBuildPusherMockRecorder) Build(ctx, args, w interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockContainerLoginBuildPusher)(nil).Build), ctx, args, w)
 }
 
-// IsEcrCredentialHelperEnabled mocks base method.
-func (m *MockContainerLoginBuildPusher) IsEcrCredentialHelperEnabled(uri string) bool {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsEcrCredentialHelperEnabled", uri)
-	ret0, _ := ret[0].(bool)
-	return ret0
-}
-
-// IsEcrCredentialHelperEnabled indicates an expected call of IsEcrCredentialHelperEnabled.
-func (mr *MockContainerLoginBuildPusherMockRecorder) IsEcrCredentialHelperEnabled(uri interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEcrCredentialHelperEnabled", reflect.TypeOf((*MockContainerLoginBuildPusher)(nil).IsEcrCredentialHelperEnabled), uri)
-}
-
 // Login mocks base method.
 func (m *MockContainerLoginBuildPusher) Login(uri, username, password string) error {
 	m.ctrl.T.Helper()
@@ -98,6 +84,73 @@ func (mr *MockContainerLoginBuildPusherMockRecorder) Push(ctx, uri, w interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockContainerLoginBuildPusher)(nil).Push), varargs...)
 }
 
+// RemoteDigest mocks base method.
+func (m *MockContainerLoginBuildPusher) RemoteDigest(ctx context.Context, ref string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoteDigest", ctx, ref)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoteDigest indicates an expected call of RemoteDigest.
+func (mr *MockContainerLoginBuildPusherMockRecorder) RemoteDigest(ctx, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoteDigest", reflect.TypeOf((*MockContainerLoginBuildPusher)(nil).RemoteDigest), ctx, ref)
+}
+
+// SmokeTest mocks base method.
+func (m *MockContainerLoginBuildPusher) SmokeTest(ctx context.Context, image string, opts *dockerengine.SmokeTestOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SmokeTest", ctx, image, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SmokeTest indicates an expected call of SmokeTest.
+func (mr *MockContainerLoginBuildPusherMockRecorder) SmokeTest(ctx, image, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SmokeTest", reflect.TypeOf((*MockContainerLoginBuildPusher)(nil).SmokeTest), ctx, image, opts)
+}
+
+// MockCredentialResolver is a mock of CredentialResolver interface.
+type MockCredentialResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialResolverMockRecorder
+}
+
+// MockCredentialResolverMockRecorder is the mock recorder for MockCredentialResolver.
+type MockCredentialResolverMockRecorder struct {
+	mock *MockCredentialResolver
+}
+
+// NewMockCredentialResolver creates a new mock instance.
+func NewMockCredentialResolver(ctrl *gomock.Controller) *MockCredentialResolver {
+	mock := &MockCredentialResolver{ctrl: ctrl}
+	mock.recorder = &MockCredentialResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialResolver) EXPECT() *MockCredentialResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockCredentialResolver) Resolve(uri string, auth func() (string, string, error)) (dockerengine.CredentialSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", uri, auth)
+	ret0, _ := ret[0].(dockerengine.CredentialSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockCredentialResolverMockRecorder) Resolve(uri, auth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockCredentialResolver)(nil).Resolve), uri, auth)
+}
+
 // MockRegistry is a mock of Registry interface.
 type MockRegistry struct {
 	ctrl     *gomock.Controller
@@ -151,3 +204,42 @@ func (mr *MockRegistryMockRecorder) RepositoryURI(name interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepositoryURI", reflect.TypeOf((*MockRegistry)(nil).RepositoryURI), name)
 }
+
+// MockPublicRegistry is a mock of PublicRegistry interface.
+type MockPublicRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublicRegistryMockRecorder
+}
+
+// MockPublicRegistryMockRecorder is the mock recorder for MockPublicRegistry.
+type MockPublicRegistryMockRecorder struct {
+	mock *MockPublicRegistry
+}
+
+// NewMockPublicRegistry creates a new mock instance.
+func NewMockPublicRegistry(ctrl *gomock.Controller) *MockPublicRegistry {
+	mock := &MockPublicRegistry{ctrl: ctrl}
+	mock.recorder = &MockPublicRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublicRegistry) EXPECT() *MockPublicRegistryMockRecorder {
+	return m.recorder
+}
+
+// Auth mocks base method.
+func (m *MockPublicRegistry) Auth() (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Auth")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Auth indicates an expected call of Auth.
+func (mr *MockPublicRegistryMockRecorder) Auth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Auth", reflect.TypeOf((*MockPublicRegistry)(nil).Auth))
+}
@@ -0,0 +1,281 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/copilot-cli/internal/pkg/aws/codebuild"
+	"github.com/aws/copilot-cli/internal/pkg/docker/dockerengine"
+	"github.com/aws/copilot-cli/internal/pkg/exec"
+	"github.com/aws/copilot-cli/internal/pkg/term/log"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	remoteBuildKeyPrefix       = "copilot-remote-builds"
+	remoteBuildDigestVar       = "IMAGE_DIGEST"
+	defaultRemoteBuildInterval = 5 * time.Second
+)
+
+// ImageBuilder builds an image from a Dockerfile and pushes it to its repository.
+type ImageBuilder interface {
+	BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (digest string, err error)
+}
+
+type contextUploader interface {
+	Upload(bucket, key string, data io.Reader) (string, error)
+}
+
+type remoteBuildRunner interface {
+	StartBuild(ctx context.Context, in *codebuild.StartBuildInput) (string, error)
+	Build(ctx context.Context, id string) (*codebuild.Build, error)
+}
+
+// RemoteBuilder builds images in AWS CodeBuild, so that images can be built and pushed without a docker daemon.
+// The build context is packaged and uploaded to S3, and built by an existing CodeBuild project whose
+// environment runs docker in privileged mode and whose role can push to the repository.
+type RemoteBuilder struct {
+	uploader contextUploader
+	runner   remoteBuildRunner
+	project  string
+	bucket   string
+
+	pollInterval time.Duration // Override in unit tests.
+}
+
+// NewRemoteBuilder returns a RemoteBuilder that uploads build contexts to the bucket and builds them with the CodeBuild project.
+func NewRemoteBuilder(uploader contextUploader, runner remoteBuildRunner, project, bucket string) *RemoteBuilder {
+	return &RemoteBuilder{
+		uploader:     uploader,
+		runner:       runner,
+		project:      project,
+		bucket:       bucket,
+		pollInterval: defaultRemoteBuildInterval,
+	}
+}
+
+// BuildAndPush builds the image in CodeBuild and pushes it to args.URI with all of args.Tags.
+// Options that depend on the local machine, such as build secrets or stdin, are not supported.
+func (b *RemoteBuilder) BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error) {
+	buildspec, err := remoteBuildspec(args)
+	if err != nil {
+		return "", err
+	}
+	archive := &bytes.Buffer{}
+	if err := args.PackageContext(archive); err != nil {
+		return "", fmt.Errorf("package build context: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s.zip", remoteBuildKeyPrefix, uuid.NewString())
+	if _, err := b.uploader.Upload(b.bucket, key, archive); err != nil {
+		return "", fmt.Errorf("upload build context to bucket %s: %w", b.bucket, err)
+	}
+	id, err := b.runner.StartBuild(ctx, &codebuild.StartBuildInput{
+		Project:   b.project,
+		Source:    fmt.Sprintf("%s/%s", b.bucket, key),
+		Buildspec: buildspec,
+	})
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(w, "Building %s remotely in CodeBuild build %s\n", args.URI, id)
+	build, err := b.wait(ctx, id, w)
+	if err != nil {
+		return "", err
+	}
+	if build.Status != codebuild.StatusSucceeded {
+		return "", fmt.Errorf("remote build %s of %s finished with status %s: see the logs at %s", id, args.Dockerfile, build.Status, build.LogsURL)
+	}
+	digest := build.ExportedVariables[remoteBuildDigestVar]
+	if digest == "" {
+		return "", fmt.Errorf("remote build %s did not report the digest of the image", id)
+	}
+	return digest, nil
+}
+
+// wait polls the build until it completes, and writes its phases to w.
+func (b *RemoteBuilder) wait(ctx context.Context, id string, w io.Writer) (*codebuild.Build, error) {
+	var phase string
+	for {
+		build, err := b.runner.Build(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if build.Phase != phase {
+			phase = build.Phase
+			fmt.Fprintf(w, "Remote build %s: %s\n", id, phase)
+		}
+		if build.Status != codebuild.StatusInProgress {
+			return build, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for remote build %s: %w", id, ctx.Err())
+		case <-time.After(b.pollInterval):
+		}
+	}
+}
+
+type buildspec struct {
+	Version string         `yaml:"version"`
+	Env     buildspecEnv   `yaml:"env"`
+	Phases  buildspecPhase `yaml:"phases"`
+}
+
+type buildspecEnv struct {
+	ExportedVariables []string `yaml:"exported-variables"`
+}
+
+type buildspecPhase struct {
+	PreBuild buildspecCommands `yaml:"pre_build"`
+	Build    buildspecCommands `yaml:"build"`
+}
+
+type buildspecCommands struct {
+	Commands []string `yaml:"commands"`
+}
+
+// remoteBuildspec returns a buildspec that builds the image from the packaged build context, pushes it, and exports its digest.
+func remoteBuildspec(args *dockerengine.BuildArguments) (string, error) {
+	var unsupported []string
+	if args.Dockerfile == "-" || args.Context == "-" {
+		unsupported = append(unsupported, "stdin")
+	}
+	if len(args.Secrets) != 0 {
+		unsupported = append(unsupported, "secrets")
+	}
+	if len(args.ExtraContexts) != 0 {
+		unsupported = append(unsupported, "additional build contexts")
+	}
+	if len(args.ArgsFromEnv) != 0 {
+		unsupported = append(unsupported, "build args from the environment")
+	}
+	if args.Builder != "" {
+		unsupported = append(unsupported, "buildx builders")
+	}
+	if args.SmokeTest != nil {
+		unsupported = append(unsupported, "smoke tests")
+	}
+	if len(unsupported) != 0 {
+		return "", fmt.Errorf("build %s remotely: %s are not supported", args.Dockerfile, strings.Join(unsupported, ", "))
+	}
+	if args.URI == "" || len(args.Tags) == 0 {
+		return "", errors.New("a repository URI and tags are required to build an image remotely")
+	}
+	// The build context is extracted at the root of the source directory.
+	dockerfile, err := filepath.Rel(args.ContextDir(), args.Dockerfile)
+	if err != nil {
+		return "", fmt.Errorf("get path of Dockerfile %s relative to context %s: %w", args.Dockerfile, args.ContextDir(), err)
+	}
+	remote := *args
+	remote.Context = "."
+	remote.Dockerfile = filepath.ToSlash(dockerfile)
+	buildArgs, err := remote.GenerateDockerBuildArgs(dockerengine.New(exec.NewCmd()))
+	if err != nil {
+		return "", fmt.Errorf("generate docker build args: %w", err)
+	}
+	registry, _, _ := strings.Cut(args.URI, "/")
+	image := fmt.Sprintf("%s:%s", args.URI, args.Tags[0])
+	spec := buildspec{
+		Version: "0.2",
+		Env: buildspecEnv{
+			ExportedVariables: []string{remoteBuildDigestVar},
+		},
+		Phases: buildspecPhase{
+			PreBuild: buildspecCommands{
+				Commands: []string{
+					fmt.Sprintf("aws ecr get-login-password | docker login --username AWS --password-stdin %s", shellQuote(registry)),
+				},
+			},
+		},
+	}
+	build := []string{shellCommand("docker", buildArgs...)}
+	for _, tag := range args.Tags {
+		build = append(build, shellCommand("docker", "push", fmt.Sprintf("%s:%s", args.URI, tag)))
+	}
+	// The image may have digests in other repositories, for example if it's also tagged for a base image, so only the one of args.URI counts.
+	build = append(build, fmt.Sprintf("export %s=$(docker inspect --format '{{range .RepoDigests}}{{println .}}{{end}}' %s | awk -F@ -v repo=%s '$1 == repo {print $2; exit}')",
+		remoteBuildDigestVar, shellQuote(image), shellQuote(args.URI)))
+	spec.Phases.Build.Commands = build
+	out, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("marshal buildspec: %w", err)
+	}
+	return string(out), nil
+}
+
+func shellCommand(name string, args ...string) string {
+	quoted := []string{name}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s for a POSIX shell if it contains characters other than letters, digits and a few safe punctuation marks.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// LocalBuilder logs in to a repository, and builds and pushes images to it with the local docker engine.
+type LocalBuilder interface {
+	ImageBuilder
+	Login() (string, dockerengine.CredentialSource, error)
+	URI() (string, error)
+}
+
+// FallbackBuilder builds images with a local builder when the docker engine is running, and with a remote one otherwise,
+// so that users without docker can still deploy.
+// CheckDockerEngineRunning decides where the images are built, so it must be called before Login and BuildAndPush.
+type FallbackBuilder struct {
+	Local             LocalBuilder
+	Remote            ImageBuilder
+	CheckDockerEngine func() error
+
+	remote bool // True if the docker engine isn't running and images are built remotely.
+}
+
+// CheckDockerEngineRunning returns an error if the docker engine isn't running and there is no remote builder to fall back to.
+func (b *FallbackBuilder) CheckDockerEngineRunning() error {
+	err := b.CheckDockerEngine()
+	if err == nil || b.Remote == nil {
+		b.remote = false
+		return err
+	}
+	log.Warningf("Docker engine is not available (%v), building images remotely.\n", err)
+	b.remote = true
+	return nil
+}
+
+// Login logs in to the repository if images are built locally.
+// Remote builds log in to the repository themselves, so only the URI of the repository is returned for them.
+func (b *FallbackBuilder) Login() (string, dockerengine.CredentialSource, error) {
+	if !b.remote {
+		return b.Local.Login()
+	}
+	uri, err := b.Local.URI()
+	if err != nil {
+		return "", "", fmt.Errorf("retrieve URI for repository: %w", err)
+	}
+	return uri, "", nil
+}
+
+// BuildAndPush builds and pushes the image with the remote builder if the docker engine isn't running, and with the local builder otherwise.
+func (b *FallbackBuilder) BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (string, error) {
+	if b.remote {
+		return b.Remote.BuildAndPush(ctx, args, w)
+	}
+	return b.Local.BuildAndPush(ctx, args, w)
+}
@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/copilot-cli/internal/pkg/aws/codebuild"
+	"github.com/aws/copilot-cli/internal/pkg/docker/dockerengine"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUploader struct {
+	bucket, key string
+	data        []byte
+}
+
+func (u *fakeUploader) Upload(bucket, key string, data io.Reader) (string, error) {
+	u.bucket, u.key = bucket, key
+	var err error
+	u.data, err = io.ReadAll(data)
+	return "", err
+}
+
+type fakeRemoteBuildRunner struct {
+	started *codebuild.StartBuildInput
+	builds  []*codebuild.Build // Returned in order by successive calls to Build.
+}
+
+func (r *fakeRemoteBuildRunner) StartBuild(_ context.Context, in *codebuild.StartBuildInput) (string, error) {
+	r.started = in
+	return "copilot-remote-build:1234", nil
+}
+
+func (r *fakeRemoteBuildRunner) Build(_ context.Context, _ string) (*codebuild.Build, error) {
+	build := r.builds[0]
+	r.builds = r.builds[1:]
+	return build, nil
+}
+
+func TestRemoteBuilder_BuildAndPush(t *testing.T) {
+	t.Setenv("CI", "false") // The build args depend on the CI environment variable.
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web", "Dockerfile"), []byte("FROM scratch"), 0644))
+	args := &dockerengine.BuildArguments{
+		URI:        "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web",
+		Tags:       []string{"latest", "v1"},
+		Dockerfile: filepath.Join(dir, "web", "Dockerfile"),
+		Context:    dir,
+		Args:       map[string]string{"GREETING": "hello world"},
+	}
+
+	t.Run("builds the image in CodeBuild and returns its digest", func(t *testing.T) {
+		uploader := &fakeUploader{}
+		runner := &fakeRemoteBuildRunner{
+			builds: []*codebuild.Build{
+				{Status: codebuild.StatusInProgress, Phase: "BUILD"},
+				{Status: codebuild.StatusSucceeded, Phase: "COMPLETED", ExportedVariables: map[string]string{"IMAGE_DIGEST": "sha256:abc"}},
+			},
+		}
+		b := NewRemoteBuilder(uploader, runner, "copilot-remote-build", "artifacts")
+		b.pollInterval = 0
+		out := &strings.Builder{}
+
+		digest, err := b.BuildAndPush(ctx, args, out)
+
+		require.NoError(t, err)
+		require.Equal(t, "sha256:abc", digest)
+		require.Equal(t, "artifacts", uploader.bucket)
+		zr, err := zip.NewReader(bytes.NewReader(uploader.data), int64(len(uploader.data)))
+		require.NoError(t, err)
+		var files []string
+		for _, f := range zr.File {
+			files = append(files, f.Name)
+		}
+		require.Equal(t, []string{"web/", "web/Dockerfile"}, files)
+		require.Equal(t, "copilot-remote-build", runner.started.Project)
+		require.Equal(t, "artifacts/"+uploader.key, runner.started.Source)
+		require.Equal(t, `version: "0.2"
+env:
+    exported-variables:
+        - IMAGE_DIGEST
+phases:
+    pre_build:
+        commands:
+            - aws ecr get-login-password | docker login --username AWS --password-stdin 123456789012.dkr.ecr.us-west-2.amazonaws.com
+    build:
+        commands:
+            - docker build -t 123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:latest -t 123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:v1 --build-arg 'GREETING=hello world' . -f web/Dockerfile
+            - docker push 123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:latest
+            - docker push 123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:v1
+            - export IMAGE_DIGEST=$(docker inspect --format '{{range .RepoDigests}}{{println .}}{{end}}' 123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web:latest | awk -F@ -v repo=123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web '$1 == repo {print $2; exit}')
+`, runner.started.Buildspec)
+		require.Contains(t, out.String(), "Remote build copilot-remote-build:1234: COMPLETED")
+	})
+	t.Run("returns an error if the build fails", func(t *testing.T) {
+		runner := &fakeRemoteBuildRunner{
+			builds: []*codebuild.Build{
+				{Status: "FAILED", Phase: "COMPLETED", LogsURL: "https://console.aws.amazon.com/logs"},
+			},
+		}
+		b := NewRemoteBuilder(&fakeUploader{}, runner, "copilot-remote-build", "artifacts")
+
+		_, err := b.BuildAndPush(ctx, args, io.Discard)
+
+		require.EqualError(t, err, "remote build copilot-remote-build:1234 of "+args.Dockerfile+" finished with status FAILED: see the logs at https://console.aws.amazon.com/logs")
+	})
+	t.Run("rejects the options that depend on the local machine", func(t *testing.T) {
+		local := *args
+		local.Secrets = map[string]string{"npmrc": "/home/user/.npmrc"}
+		b := NewRemoteBuilder(&fakeUploader{}, &fakeRemoteBuildRunner{}, "copilot-remote-build", "artifacts")
+
+		_, err := b.BuildAndPush(ctx, &local, io.Discard)
+
+		require.EqualError(t, err, "build "+args.Dockerfile+" remotely: secrets are not supported")
+	})
+}
+
+type fakeLocalBuilder struct {
+	fakeImageBuilder
+	uri string
+}
+
+func (b fakeLocalBuilder) Login() (string, dockerengine.CredentialSource, error) {
+	return b.uri, dockerengine.CredentialSourceLogin, nil
+}
+
+func (b fakeLocalBuilder) URI() (string, error) {
+	return b.uri, nil
+}
+
+type fakeImageBuilder struct {
+	digest string
+}
+
+func (b fakeImageBuilder) BuildAndPush(context.Context, *dockerengine.BuildArguments, io.Writer) (string, error) {
+	return b.digest, nil
+}
+
+func TestFallbackBuilder(t *testing.T) {
+	tests := map[string]struct {
+		dockerErr error
+		remote    ImageBuilder
+
+		wantedSource dockerengine.CredentialSource
+		wantedDigest string
+		wantedErr    string
+	}{
+		"logs in and builds locally if docker is running": {
+			remote:       fakeImageBuilder{digest: "sha256:remote"},
+			wantedSource: dockerengine.CredentialSourceLogin,
+			wantedDigest: "sha256:local",
+		},
+		"builds remotely without logging in if docker isn't running": {
+			dockerErr:    errors.New("docker daemon is not responsive"),
+			remote:       fakeImageBuilder{digest: "sha256:remote"},
+			wantedDigest: "sha256:remote",
+		},
+		"errors if docker isn't running and there is no remote builder": {
+			dockerErr: errors.New("docker daemon is not responsive"),
+			wantedErr: "docker daemon is not responsive",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &FallbackBuilder{
+				Local: fakeLocalBuilder{
+					fakeImageBuilder: fakeImageBuilder{digest: "sha256:local"},
+					uri:              "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web",
+				},
+				Remote: tc.remote,
+				CheckDockerEngine: func() error {
+					return tc.dockerErr
+				},
+			}
+
+			err := b.CheckDockerEngineRunning()
+			if tc.wantedErr != "" {
+				require.EqualError(t, err, tc.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			uri, source, err := b.Login()
+			require.NoError(t, err)
+			require.Equal(t, "123456789012.dkr.ecr.us-west-2.amazonaws.com/app/web", uri)
+			require.Equal(t, tc.wantedSource, source)
+			digest, err := b.BuildAndPush(context.Background(), &dockerengine.BuildArguments{Dockerfile: "Dockerfile"}, io.Discard)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantedDigest, digest)
+		})
+	}
+}
@@ -19,7 +19,13 @@ type ContainerLoginBuildPusher interface {
 	Build(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) error
 	Login(uri, username, password string) error
 	Push(ctx context.Context, uri string, w io.Writer, tags ...string) (digest string, err error)
-	IsEcrCredentialHelperEnabled(uri string) bool
+	RemoteDigest(ctx context.Context, ref string) (string, error)
+	SmokeTest(ctx context.Context, image string, opts *dockerengine.SmokeTestOptions) error
+}
+
+// CredentialResolver makes sure that docker has the credentials of a registry.
+type CredentialResolver interface {
+	Resolve(uri string, auth func() (username, password string, err error)) (dockerengine.CredentialSource, error)
 }
 
 // Registry gets information of repositories.
@@ -28,33 +34,50 @@ type Registry interface {
 	Auth() (string, string, error)
 }
 
+// PublicRegistry gets the credentials of ECR Public.
+type PublicRegistry interface {
+	Auth() (string, string, error)
+}
+
 // Repository builds and pushes images to a repository.
 type Repository struct {
-	name     string
-	registry Registry
-	uri      string
-	docker   ContainerLoginBuildPusher
+	name        string
+	registry    Registry
+	public      PublicRegistry
+	uri         string
+	docker      ContainerLoginBuildPusher
+	credentials CredentialResolver
 }
 
 // New instantiates a new Repository.
 func New(registry Registry, name string) *Repository {
+	docker := dockerengine.New(exec.NewCmd())
 	return &Repository{
-		name:     name,
-		registry: registry,
-		docker:   dockerengine.New(exec.NewCmd()),
+		name:        name,
+		registry:    registry,
+		docker:      docker,
+		credentials: dockerengine.NewCredentialResolver(docker),
 	}
 }
 
 // NewWithURI instantiates a new Repository with uri being set.
 func NewWithURI(registry Registry, name, uri string) *Repository {
+	docker := dockerengine.New(exec.NewCmd())
 	return &Repository{
-		name:     name,
-		registry: registry,
-		uri:      uri,
-		docker:   dockerengine.New(exec.NewCmd()),
+		name:        name,
+		registry:    registry,
+		uri:         uri,
+		docker:      docker,
+		credentials: dockerengine.NewCredentialResolver(docker),
 	}
 }
 
+// WithPublicRegistry sets the registry that provides the credentials of repositories in ECR Public.
+func (r *Repository) WithPublicRegistry(public PublicRegistry) *Repository {
+	r.public = public
+	return r
+}
+
 // Build build the image from Dockerfile
 func (r *Repository) Build(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (digest string, err error) {
 	if err := r.docker.Build(ctx, args, w); err != nil {
@@ -67,7 +90,7 @@ func (r *Repository) Build(ctx context.Context, args *dockerengine.BuildArgument
 // BuildAndPush builds the image from Dockerfile and pushes it to the repository with tags.
 func (r *Repository) BuildAndPush(ctx context.Context, args *dockerengine.BuildArguments, w io.Writer) (digest string, err error) {
 	if args.URI == "" {
-		uri, err := r.repositoryURI()
+		uri, err := r.URI()
 		if err != nil {
 			return "", err
 		}
@@ -76,6 +99,21 @@ func (r *Repository) BuildAndPush(ctx context.Context, args *dockerengine.BuildA
 	if err := r.docker.Build(ctx, args, w); err != nil {
 		return "", fmt.Errorf("build Dockerfile at %s: %w", args.Dockerfile, err)
 	}
+	if args.Output == dockerengine.PushDirect {
+		// The builder already pushed the image, so only its digest is left to retrieve.
+		ref := fmt.Sprintf("%s:%s", args.URI, args.Tags[0])
+		digest, err = r.docker.RemoteDigest(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("get digest of image %s: %w", ref, err)
+		}
+		return digest, nil
+	}
+	if args.SmokeTest != nil {
+		image := fmt.Sprintf("%s:%s", args.URI, args.Tags[0])
+		if err := r.docker.SmokeTest(ctx, image, args.SmokeTest); err != nil {
+			return "", fmt.Errorf("smoke test image built from Dockerfile at %s: %w", args.Dockerfile, err)
+		}
+	}
 
 	digest, err = r.docker.Push(ctx, args.URI, w, args.Tags...)
 	if err != nil {
@@ -84,8 +122,8 @@ func (r *Repository) BuildAndPush(ctx context.Context, args *dockerengine.BuildA
 	return digest, nil
 }
 
-// repositoryURI() returns the uri of the repository.
-func (r *Repository) repositoryURI() (string, error) {
+// URI returns the uri of the repository.
+func (r *Repository) URI() (string, error) {
 	if r.uri != "" {
 		return r.uri, nil
 	}
@@ -97,24 +135,26 @@ func (r *Repository) repositoryURI() (string, error) {
 	return uri, nil
 }
 
-// Login authenticates with a ECR registry by performing a Docker login,
-// but only if the `credStore` attribute value is not set to `ecr-login`.
-// If the `credStore` value is `ecr-login`, no login is performed.
-// Returns uri of the repository or an error, if any occurs during the login process.
-func (r *Repository) Login() (string, error) {
-	uri, err := r.repositoryURI()
+// Login makes sure that docker has the credentials of the ECR registry of the repository.
+// The credentials of repositories in ECR Public are obtained from the public registry instead.
+// A Docker login is only performed if no credential helper, credential store, or previous login
+// in the docker config file provides them.
+// Returns the uri of the repository and where the credentials come from, or an error, if any occurs during the login process.
+func (r *Repository) Login() (string, dockerengine.CredentialSource, error) {
+	uri, err := r.URI()
 	if err != nil {
-		return "", fmt.Errorf("retrieve URI for repository: %w", err)
+		return "", "", fmt.Errorf("retrieve URI for repository: %w", err)
 	}
-	if !r.docker.IsEcrCredentialHelperEnabled(uri) {
-		username, password, err := r.registry.Auth()
-		if err != nil {
-			return "", fmt.Errorf("get auth: %w", err)
-		}
-
-		if err := r.docker.Login(uri, username, password); err != nil {
-			return "", fmt.Errorf("docker login %s: %w", uri, err)
+	auth := r.registry.Auth
+	if dockerengine.IsECRPublicURI(uri) {
+		if r.public == nil {
+			return "", "", fmt.Errorf("docker login %s: no ECR Public registry to get the credentials from", uri)
 		}
+		auth = r.public.Auth
 	}
-	return uri, nil
+	source, err := r.credentials.Resolve(uri, auth)
+	if err != nil {
+		return "", "", fmt.Errorf("docker login %s: %w", uri, err)
+	}
+	return uri, source, nil
 }
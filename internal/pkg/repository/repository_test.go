@@ -89,6 +89,8 @@ func TestRepository_BuildAndPush(t *testing.T) {
 
 	testCases := map[string]struct {
 		inURI        string
+		inOutput     dockerengine.BuildOutput
+		inSmokeTest  *dockerengine.SmokeTestOptions
 		inMockDocker func(m *mocks.MockContainerLoginBuildPusher)
 
 		mockRegistry func(m *mocks.MockRegistry)
@@ -130,6 +132,46 @@ func TestRepository_BuildAndPush(t *testing.T) {
 			},
 			wantedDigest: "sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807",
 		},
+		"failed to get the digest of an image pushed by the builder": {
+			inURI:    defaultDockerArguments.URI,
+			inOutput: dockerengine.PushDirect,
+			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
+				m.EXPECT().Build(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().RemoteDigest(ctx, "mockRepoURI:tag1").Return("", errors.New("some error"))
+			},
+			wantedError: errors.New("get digest of image mockRepoURI:tag1: some error"),
+		},
+		"returns the digest of an image pushed by the builder": {
+			inURI:    defaultDockerArguments.URI,
+			inOutput: dockerengine.PushDirect,
+			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
+				m.EXPECT().Build(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().Push(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				m.EXPECT().RemoteDigest(ctx, "mockRepoURI:tag1").Return("sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807", nil)
+			},
+			wantedDigest: "sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807",
+		},
+		"does not push an image that fails its smoke test": {
+			inURI:       defaultDockerArguments.URI,
+			inSmokeTest: &dockerengine.SmokeTestOptions{Command: []string{"/app", "--version"}},
+			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
+				m.EXPECT().Build(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().SmokeTest(ctx, "mockRepoURI:tag1", &dockerengine.SmokeTestOptions{Command: []string{"/app", "--version"}}).
+					Return(errors.New("some error"))
+				m.EXPECT().Push(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantedError: errors.New("smoke test image built from Dockerfile at path/to/dockerfile: some error"),
+		},
+		"pushes an image that passes its smoke test": {
+			inURI:       defaultDockerArguments.URI,
+			inSmokeTest: &dockerengine.SmokeTestOptions{},
+			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
+				m.EXPECT().Build(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().SmokeTest(ctx, "mockRepoURI:tag1", &dockerengine.SmokeTestOptions{}).Return(nil)
+				m.EXPECT().Push(ctx, mockRepoURI, gomock.Any(), mockTag1, mockTag2, mockTag3).Return("sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807", nil)
+			},
+			wantedDigest: "sha256:f1d4ae3f7261a72e98c6ebefe9985cf10a0ea5bd762585a43e0700ed99863807",
+		},
 		"success": {
 			mockRegistry: func(m *mocks.MockRegistry) {
 				m.EXPECT().RepositoryURI(inRepoName).Return(defaultDockerArguments.URI, nil)
@@ -167,6 +209,8 @@ func TestRepository_BuildAndPush(t *testing.T) {
 				Dockerfile: inDockerfilePath,
 				Context:    filepath.Dir(inDockerfilePath),
 				Tags:       []string{mockTag1, mockTag2, mockTag3},
+				Output:     tc.inOutput,
+				SmokeTest:  tc.inSmokeTest,
 			}, buf)
 			if tc.wantedError != nil {
 				require.EqualError(t, tc.wantedError, err.Error())
@@ -179,42 +223,70 @@ func TestRepository_BuildAndPush(t *testing.T) {
 }
 
 func Test_Login(t *testing.T) {
-	const mockRepoURI = "mockRepoURI"
+	const (
+		mockRepoURI   = "mockRepoURI"
+		mockPublicURI = "public.ecr.aws/alias/my-repo"
+	)
 	testCases := map[string]struct {
-		inMockDocker func(m *mocks.MockContainerLoginBuildPusher)
-		mockRegistry func(m *mocks.MockRegistry)
-		wantedURI    string
-		wantedError  error
+		uri             string
+		noPublic        bool
+		mockRegistry    func(m *mocks.MockRegistry)
+		mockPublic      func(m *mocks.MockPublicRegistry)
+		mockCredentials func(m *mocks.MockCredentialResolver)
+		wantedURI       string
+		wantedSource    dockerengine.CredentialSource
+		wantedError     error
 	}{
-		"failed to get auth": {
-			mockRegistry: func(m *mocks.MockRegistry) {
-				m.EXPECT().Auth().Return("", "", errors.New("error getting auth"))
+		"failed to resolve credentials": {
+			mockCredentials: func(m *mocks.MockCredentialResolver) {
+				m.EXPECT().Resolve(mockRepoURI, gomock.Any()).Return(dockerengine.CredentialSource(""), errors.New("get auth: error getting auth"))
 			},
-			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
-				m.EXPECT().IsEcrCredentialHelperEnabled("mockRepoURI").Return(false)
-				m.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-			},
-			wantedError: errors.New("get auth: error getting auth"),
+			wantedError: fmt.Errorf("docker login %s: get auth: error getting auth", mockRepoURI),
 		},
-		"failed to login": {
+		"logs in with the auth of the registry": {
 			mockRegistry: func(m *mocks.MockRegistry) {
 				m.EXPECT().Auth().Return("my-name", "my-pwd", nil)
 			},
-			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
-				m.EXPECT().IsEcrCredentialHelperEnabled("mockRepoURI").Return(false)
-				m.EXPECT().Login("mockRepoURI", "my-name", "my-pwd").Return(errors.New("error logging in"))
+			mockCredentials: func(m *mocks.MockCredentialResolver) {
+				m.EXPECT().Resolve(mockRepoURI, gomock.Any()).DoAndReturn(func(_ string, auth func() (string, string, error)) (dockerengine.CredentialSource, error) {
+					username, password, err := auth()
+					require.NoError(t, err)
+					require.Equal(t, "my-name", username)
+					require.Equal(t, "my-pwd", password)
+					return dockerengine.CredentialSourceLogin, nil
+				})
 			},
-			wantedError: fmt.Errorf("docker login %s: error logging in", mockRepoURI),
+			wantedURI:    mockRepoURI,
+			wantedSource: dockerengine.CredentialSourceLogin,
 		},
-		"no error when performing login": {
-			mockRegistry: func(m *mocks.MockRegistry) {
-				m.EXPECT().Auth().Return("my-name", "my-pwd", nil)
+		"returns the configured source of the credentials": {
+			mockCredentials: func(m *mocks.MockCredentialResolver) {
+				m.EXPECT().Resolve(mockRepoURI, gomock.Any()).Return(dockerengine.CredentialSourceHelper, nil)
 			},
-			inMockDocker: func(m *mocks.MockContainerLoginBuildPusher) {
-				m.EXPECT().IsEcrCredentialHelperEnabled("mockRepoURI").Return(false)
-				m.EXPECT().Login("mockRepoURI", "my-name", "my-pwd").Return(nil)
+			wantedURI:    mockRepoURI,
+			wantedSource: dockerengine.CredentialSourceHelper,
+		},
+		"logs in to ECR Public with the auth of the public registry": {
+			uri: mockPublicURI,
+			mockPublic: func(m *mocks.MockPublicRegistry) {
+				m.EXPECT().Auth().Return("AWS", "public-pwd", nil)
 			},
-			wantedURI: mockRepoURI,
+			mockCredentials: func(m *mocks.MockCredentialResolver) {
+				m.EXPECT().Resolve(mockPublicURI, gomock.Any()).DoAndReturn(func(_ string, auth func() (string, string, error)) (dockerengine.CredentialSource, error) {
+					_, password, err := auth()
+					require.NoError(t, err)
+					require.Equal(t, "public-pwd", password)
+					return dockerengine.CredentialSourceLogin, nil
+				})
+			},
+			wantedURI:    mockPublicURI,
+			wantedSource: dockerengine.CredentialSourceLogin,
+		},
+		"errors logging in to ECR Public without a public registry": {
+			uri:             mockPublicURI,
+			noPublic:        true,
+			mockCredentials: func(m *mocks.MockCredentialResolver) {},
+			wantedError:     fmt.Errorf("docker login %s: no ECR Public registry to get the credentials from", mockPublicURI),
 		},
 	}
 	for name, tc := range testCases {
@@ -222,27 +294,38 @@ func Test_Login(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			mockRepoGetter := mocks.NewMockRegistry(ctrl)
-			mockDocker := mocks.NewMockContainerLoginBuildPusher(ctrl)
+			mockPublic := mocks.NewMockPublicRegistry(ctrl)
+			mockCredentials := mocks.NewMockCredentialResolver(ctrl)
 
 			if tc.mockRegistry != nil {
 				tc.mockRegistry(mockRepoGetter)
 			}
-			if tc.inMockDocker != nil {
-				tc.inMockDocker(mockDocker)
+			if tc.mockPublic != nil {
+				tc.mockPublic(mockPublic)
+			}
+			tc.mockCredentials(mockCredentials)
+			uri := mockRepoURI
+			if tc.uri != "" {
+				uri = tc.uri
 			}
 
 			repo := &Repository{
-				registry: mockRepoGetter,
-				uri:      mockRepoURI,
-				docker:   mockDocker,
+				registry:    mockRepoGetter,
+				uri:         uri,
+				docker:      mocks.NewMockContainerLoginBuildPusher(ctrl),
+				credentials: mockCredentials,
+			}
+			if !tc.noPublic {
+				repo.WithPublicRegistry(mockPublic)
 			}
 
-			gotURI, gotErr := repo.Login()
+			gotURI, gotSource, gotErr := repo.Login()
 			if tc.wantedError != nil {
 				require.EqualError(t, tc.wantedError, gotErr.Error())
 			} else {
 				require.NoError(t, gotErr)
 				require.Equal(t, tc.wantedURI, gotURI)
+				require.Equal(t, tc.wantedSource, gotSource)
 			}
 		})
 	}